@@ -2,8 +2,14 @@
 // for chart configurations. The IR is designed to be AI-friendly,
 // easily validated via JSON Schema, and compiled to various chart formats
 // including Apache ECharts, go-analyze/charts (PNG/SVG), and others.
+//
+// JSONSchema returns the JSON Schema for the IR and Validate checks
+// references between datasets, marks and columns that the schema
+// cannot express.
 package chartir
 
+import "fmt"
+
 // ChartIR is the top-level chart intermediate representation.
 // It provides a normalized, non-polymorphic structure that can be
 // compiled to various chart output formats.
@@ -42,6 +48,24 @@ func (c *ChartIR) GetDataset(id string) *Dataset {
 	return nil
 }
 
+// MarkDataset returns the dataset referenced by the mark's DatasetID.
+func (c *ChartIR) MarkDataset(m Mark) (*Dataset, error) {
+	if ds := c.GetDataset(m.DatasetID); ds != nil {
+		return ds, nil
+	}
+	return nil, fmt.Errorf("chartir: dataset not found: %s", m.DatasetID)
+}
+
+// GetAxis returns the axis with the given ID, or nil if not found.
+func (c *ChartIR) GetAxis(id string) *Axis {
+	for i := range c.Axes {
+		if c.Axes[i].ID == id {
+			return &c.Axes[i]
+		}
+	}
+	return nil
+}
+
 // GetXAxis returns the first horizontal axis, or nil if none.
 func (c *ChartIR) GetXAxis() *Axis {
 	for i := range c.Axes {
@@ -61,3 +85,16 @@ func (c *ChartIR) GetYAxis() *Axis {
 	}
 	return nil
 }
+
+// GetMarkYAxis returns the vertical axis referenced by the mark's YAxisID,
+// or the first vertical axis if YAxisID is empty. It returns nil if no
+// matching vertical axis exists.
+func (c *ChartIR) GetMarkYAxis(m Mark) *Axis {
+	if m.YAxisID == "" {
+		return c.GetYAxis()
+	}
+	if a := c.GetAxis(m.YAxisID); a != nil && a.IsVertical() {
+		return a
+	}
+	return nil
+}
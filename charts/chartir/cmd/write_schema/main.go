@@ -0,0 +1,26 @@
+// write_schema writes the ChartIR JSON Schema to a file.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+func main() {
+	filename := "chartir.schema.json"
+	if len(os.Args) > 1 {
+		filename = os.Args[1]
+	}
+
+	b, err := chartir.JSONSchemaBytes()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filename, append(b, '\n'), 0600); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("WROTE [%s]\n", filename)
+}
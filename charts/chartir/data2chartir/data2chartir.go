@@ -0,0 +1,97 @@
+// Package data2chartir builds chartir.ChartIR values from the gocharts data
+// types `table.Table`, `timeseries.TimeSeries`, `timeseries.TimeSeriesSet`,
+// `histogram.Histogram` and `histogram.HistogramSet`. The resulting IR can be
+// compiled by any chartir backend. It is a separate package so that chartir
+// itself does not depend on the data packages.
+package data2chartir
+
+import (
+	"strconv"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+const (
+	AxisIDX = "x"
+	AxisIDY = "y"
+
+	// StackID is the stack group used for stacked marks.
+	StackID = "total"
+)
+
+// Opts configures the ChartIR produced by the builders in this package.
+type Opts struct {
+	// Title is the chart title. If empty, the name of the source data is used.
+	Title string
+
+	// Geometry is the mark geometry. If empty, bar is used for histograms
+	// and category tables, and line is used for time series and numeric tables.
+	Geometry chartir.Geometry
+
+	// Stacked puts all marks in a single stack group.
+	Stacked bool
+
+	// XAxisName and YAxisName are the axis titles.
+	XAxisName string
+	YAxisName string
+
+	// Legend shows the chart legend. A legend is always shown for
+	// charts with more than one mark.
+	Legend bool
+
+	// IntervalLines draws forecast prediction intervals as bound lines
+	// instead of a shaded band, for backends which cannot combine stacked
+	// areas and lines, such as wchart.
+	IntervalLines bool
+}
+
+func (opts *Opts) title(def string) string {
+	if opts != nil && opts.Title != "" {
+		return opts.Title
+	}
+	return def
+}
+
+func (opts *Opts) geometry(def chartir.Geometry) chartir.Geometry {
+	if opts != nil && opts.Geometry != "" {
+		return opts.Geometry
+	}
+	return def
+}
+
+func (opts *Opts) stack() string {
+	if opts != nil && opts.Stacked {
+		return StackID
+	}
+	return ""
+}
+
+// newChartIR returns a ChartIR with x and y axes and a legend when
+// there is more than one mark.
+func newChartIR(title string, xAxisType chartir.AxisType, datasets []chartir.Dataset, marks []chartir.Mark, opts *Opts) *chartir.ChartIR {
+	xAxis := chartir.Axis{ID: AxisIDX, Type: xAxisType, Position: chartir.AxisPositionBottom}
+	yAxis := chartir.Axis{ID: AxisIDY, Type: chartir.AxisTypeValue, Position: chartir.AxisPositionLeft}
+	if opts != nil {
+		xAxis.Name = opts.XAxisName
+		yAxis.Name = opts.YAxisName
+	}
+	ir := &chartir.ChartIR{
+		Title:    title,
+		Datasets: datasets,
+		Marks:    marks,
+		Axes:     []chartir.Axis{xAxis, yAxis},
+		Tooltip:  &chartir.Tooltip{Show: true, Trigger: chartir.TooltipTriggerAxis},
+	}
+	if len(marks) > 1 || (opts != nil && opts.Legend) {
+		ir.Legend = &chartir.Legend{Show: true, Position: chartir.LegendPositionTop}
+	}
+	return ir
+}
+
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,179 @@
+package data2chartir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-analyze/charts/chartdraw"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/charts/chartir/echarts"
+	"github.com/grokify/gocharts/v2/charts/chartir/google"
+	"github.com/grokify/gocharts/v2/charts/chartir/wchart"
+	"github.com/grokify/gocharts/v2/data/histogram"
+	"github.com/grokify/gocharts/v2/data/table"
+	"github.com/grokify/gocharts/v2/data/timeseries"
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+func TestHistogramSetChartIR(t *testing.T) {
+	hset := histogram.NewHistogramSet("Requests")
+	hset.Add("api", "200", 10)
+	hset.Add("api", "500", 2)
+	hset.Add("web", "200", 7)
+
+	ir, err := HistogramSetChartIR(hset, []string{"api", "web"}, []string{"200", "500"}, &Opts{Stacked: true})
+	if err != nil {
+		t.Fatalf("HistogramSetChartIR() error: %v", err)
+	}
+	if err := chartir.Validate(ir); err != nil {
+		t.Fatalf("chartir.Validate() error: %v", err)
+	}
+	if len(ir.Marks) != 2 || ir.Marks[1].Stack != StackID {
+		t.Errorf("HistogramSetChartIR() marks mismatch: got [%v]", ir.Marks)
+	}
+	if got := ir.Datasets[0].GetStringValues("web"); len(got) != 2 || got[0] != "7" || got[1] != "0" {
+		t.Errorf("HistogramSetChartIR() values mismatch: want [7 0], got [%v]", got)
+	}
+}
+
+func TestBarChartIRWChart(t *testing.T) {
+	hist := histogram.NewHistogram("Status")
+	hist.Add("200", 12)
+	hist.Add("404", 3)
+	histIR, err := HistogramChartIR(hist, nil)
+	if err != nil {
+		t.Fatalf("HistogramChartIR() error: %v", err)
+	}
+	tbl := table.NewTable("Sales")
+	tbl.Columns = []string{"month", "sales"}
+	tbl.Rows = [][]string{{"Jan", "12"}, {"Feb", "3"}}
+	tblIR, err := TableChartIR(&tbl, "month", []string{"sales"}, nil)
+	if err != nil {
+		t.Fatalf("TableChartIR() error: %v", err)
+	}
+
+	// the category column is drawn as bar labels and the counts as values
+	for _, tt := range []struct {
+		name   string
+		ir     *chartir.ChartIR
+		labels []string
+	}{
+		{"HistogramChartIR", histIR, []string{"200", "404"}},
+		{"TableChartIR", tblIR, []string{"Jan", "Feb"}},
+	} {
+		chart, err := wchart.NewCompiler().Compile(tt.ir)
+		if err != nil {
+			t.Fatalf("%s() wchart.Compiler.Compile() error: %v", tt.name, err)
+		}
+		bar, ok := chart.(*chartdraw.BarChart)
+		if !ok {
+			t.Fatalf("%s() wchart.Compiler.Compile() mismatch: want *chartdraw.BarChart, got %T", tt.name, chart)
+		}
+		if len(bar.Bars) != 2 || bar.Bars[0].Label != tt.labels[0] || bar.Bars[0].Value != 12 ||
+			bar.Bars[1].Label != tt.labels[1] || bar.Bars[1].Value != 3 {
+			t.Errorf("%s() wchart bars mismatch: want labels [%v] values [12 3], got [%v]", tt.name, tt.labels, bar.Bars)
+		}
+	}
+}
+
+func TestTimeSeriesSetChartIR(t *testing.T) {
+	dt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	set := timeseries.TimeSeriesSet{
+		Name: "Users",
+		Series: map[string]timeseries.TimeSeries{
+			"free": {SeriesName: "free", ItemMap: map[string]timeseries.TimeItem{
+				dt.Format(time.RFC3339): {SeriesName: "free", Time: dt, Value: 5}}},
+			"paid": {SeriesName: "paid", ItemMap: map[string]timeseries.TimeItem{
+				dt.Format(time.RFC3339): {SeriesName: "paid", Time: dt, Value: 3}}},
+		},
+	}
+
+	ir, err := TimeSeriesSetChartIR(set, nil)
+	if err != nil {
+		t.Fatalf("TimeSeriesSetChartIR() error: %v", err)
+	}
+	if err := chartir.Validate(ir); err != nil {
+		t.Fatalf("chartir.Validate() error: %v", err)
+	}
+	if len(ir.Marks) != 2 || ir.Marks[0].Name != "free" || ir.GetXAxis().Type != chartir.AxisTypeTime {
+		t.Errorf("TimeSeriesSetChartIR() mismatch: got marks [%v] axes [%v]", ir.Marks, ir.Axes)
+	}
+	if got := ir.Datasets[1].Rows[0]; got[0] != "2024-01-01T00:00:00Z" || got[1] != "3" {
+		t.Errorf("TimeSeriesSetChartIR() row mismatch: got [%v]", got)
+	}
+}
+
+func TestForecastChartIR(t *testing.T) {
+	forecast := func(values []int64) (timeseries.TimeSeries, *timeseries.Forecast) {
+		history := timeseries.NewTimeSeries("Revenue")
+		history.Interval = timeutil.IntervalQuarter
+		dt := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		for i, v := range values {
+			history.AddInt64(dt.AddDate(0, 3*i, 0), v)
+		}
+		fc, err := history.Forecast(timeseries.ForecastOptions{Method: timeseries.ForecastDrift, Horizon: 2})
+		if err != nil {
+			t.Fatalf("TimeSeries.Forecast() error: %v", err)
+		}
+		return history, fc
+	}
+
+	// the interval is a shaded band on backends that stack areas
+	history, fc := forecast([]int64{10, 13, 14, 16})
+	ir, err := ForecastChartIR(history, fc, nil)
+	if err != nil {
+		t.Fatalf("ForecastChartIR() error: %v", err)
+	}
+	if len(ir.Marks) != 4 || ir.Marks[1].Name != "Revenue Forecast" || ir.Marks[3].Name != "95% Interval" {
+		t.Fatalf("ForecastChartIR() marks mismatch: got [%v]", ir.Marks)
+	}
+	for i, opacity := range []float64{0, BandOpacity} {
+		mark := ir.Marks[2+i]
+		if mark.Geometry != chartir.GeometryArea || mark.Stack != StackIDBand ||
+			mark.Style == nil || mark.Style.AreaOpacity == nil || *mark.Style.AreaOpacity != opacity {
+			t.Errorf("ForecastChartIR() band mark mismatch: got [%v]", mark)
+		}
+	}
+	var buf bytes.Buffer
+	if err := echarts.NewCompiler().RenderHTML(ir, &buf); err != nil {
+		t.Errorf("echarts.Compiler.RenderHTML() error: %v", err)
+	} else if !strings.Contains(buf.String(), "95% Interval") {
+		t.Error("echarts.Compiler.RenderHTML() output missing interval series")
+	}
+	buf.Reset()
+	if err := google.NewCompiler().RenderHTML(ir, &buf); err != nil {
+		t.Errorf("google.Compiler.RenderHTML() error: %v", err)
+	} else if !strings.Contains(buf.String(), "95% Interval") {
+		t.Error("google.Compiler.RenderHTML() output missing interval series")
+	}
+
+	// wchart cannot stack areas with lines, so it draws bound lines
+	ir, err = ForecastChartIR(history, fc, &Opts{IntervalLines: true})
+	if err != nil {
+		t.Fatalf("ForecastChartIR() error: %v", err)
+	}
+	if len(ir.Marks) != 4 || ir.Marks[2].Name != "95% Lower" || ir.Marks[3].Name != "95% Upper" || ir.Marks[3].Geometry != chartir.GeometryLine {
+		t.Fatalf("ForecastChartIR() bound marks mismatch: got [%v]", ir.Marks)
+	}
+	buf.Reset()
+	if err := wchart.NewCompiler().RenderPNG(ir, &buf); err != nil {
+		t.Errorf("wchart.Compiler.RenderPNG() error: %v", err)
+	} else if buf.Len() == 0 {
+		t.Error("wchart.Compiler.RenderPNG() output is empty")
+	}
+
+	// stacked areas start at zero, so negative bounds are drawn as lines
+	history, fc = forecast([]int64{3, 1, 4, 2})
+	ir, err = ForecastChartIR(history, fc, nil)
+	if err != nil {
+		t.Fatalf("ForecastChartIR() error: %v", err)
+	}
+	if len(ir.Marks) != 4 || ir.Marks[2].Name != "95% Lower" || ir.Marks[3].Name != "95% Upper" {
+		t.Fatalf("ForecastChartIR() bound marks mismatch: got [%v]", ir.Marks)
+	}
+	if got := ir.Datasets[2].Rows[0][1]; !strings.HasPrefix(got, "-") {
+		t.Errorf("ForecastChartIR() lower bound mismatch: want negative, got [%s]", got)
+	}
+}
@@ -0,0 +1,74 @@
+package data2chartir
+
+import (
+	"fmt"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/data/timeseries"
+)
+
+const (
+	// StackIDBand is the stack group used for prediction interval bands.
+	StackIDBand = "band"
+
+	// BandOpacity is the area opacity of prediction interval bands.
+	BandOpacity = 0.2
+
+	// BoundColor is the line color of prediction interval bounds drawn
+	// as lines.
+	BoundColor = "#9e9e9e"
+)
+
+// ForecastChartIR returns a ChartIR with `history` and the forecast mean as
+// lines and the prediction interval as a shaded band. The band is two
+// stacked areas, a transparent area up to the lower bound and a shaded area
+// of the interval width, which renders on backends that stack areas under
+// lines, such as echarts and google. Stacked areas start at zero, so the
+// interval is drawn as lower and upper bound lines in `BoundColor` if a
+// lower bound is negative or `opts.IntervalLines` is set, such as for wchart.
+func ForecastChartIR(history timeseries.TimeSeries, fc *timeseries.Forecast, opts *Opts) (*chartir.ChartIR, error) {
+	if fc == nil {
+		return nil, fmt.Errorf("chartir: forecast is nil")
+	}
+	lines := opts != nil && opts.IntervalLines
+	width := timeseries.NewTimeSeries(fmt.Sprintf("%g%% Interval", fc.Level*100))
+	width.IsFloat = true
+	for _, lower := range fc.Lower.ItemsSorted() {
+		upper, err := fc.Upper.Get(lower.Time)
+		if err != nil {
+			return nil, fmt.Errorf("chartir: forecast upper bound not found: %s", lower.Time)
+		}
+		width.AddFloat64(lower.Time, upper.Float64()-lower.Float64())
+		lines = lines || lower.Float64() < 0
+	}
+	bounds := []timeseries.TimeSeries{fc.Lower, width}
+	if lines {
+		bounds[1] = fc.Upper
+	}
+
+	var datasets []chartir.Dataset
+	var marks []chartir.Mark
+	for _, ts := range append([]timeseries.TimeSeries{history, fc.Mean}, bounds...) {
+		ds := timeSeriesDataset(datasetID(ts.SeriesName, fmt.Sprintf("series%d", len(datasets))), ts)
+		mark := timeSeriesMark(ds.ID, nil)
+		mark.Name = ts.SeriesName
+		datasets = append(datasets, ds)
+		marks = append(marks, mark)
+	}
+	if lines {
+		for i, bound := range []string{"Lower", "Upper"} {
+			mark := &marks[2+i]
+			mark.Name = fmt.Sprintf("%g%% %s", fc.Level*100, bound)
+			mark.Style = &chartir.Style{Color: BoundColor, Symbol: "none"}
+		}
+	} else {
+		noWidth, noOpacity, bandOpacity := 0.0, 0.0, BandOpacity
+		for i, areaOpacity := range []*float64{&noOpacity, &bandOpacity} {
+			mark := &marks[2+i]
+			mark.Geometry = chartir.GeometryArea
+			mark.Stack = StackIDBand
+			mark.Style = &chartir.Style{LineWidth: &noWidth, AreaOpacity: areaOpacity, Symbol: "none"}
+		}
+	}
+	return newChartIR(opts.title(history.SeriesName), chartir.AxisTypeTime, datasets, marks, opts), nil
+}
@@ -0,0 +1,102 @@
+package data2chartir
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/data/histogram"
+)
+
+const (
+	ColumnNameBin   = "bin"
+	ColumnNameCount = "count"
+)
+
+// HistogramChartIR returns a ChartIR with a single bar mark of bin counts on
+// a category x axis. Bins are ordered by `hist.Order`, falling back to
+// sorted bin names.
+func HistogramChartIR(hist *histogram.Histogram, opts *Opts) (*chartir.ChartIR, error) {
+	if hist == nil {
+		return nil, histogram.ErrHistogramCannotBeNil
+	}
+	binNames := hist.ItemNamesOrderOrDefault()
+	if len(hist.Order) == 0 {
+		sort.Strings(binNames)
+	}
+	ds := chartir.Dataset{
+		ID: datasetID(hist.Name, "histogram"),
+		Columns: []chartir.Column{
+			{Name: ColumnNameBin, Type: chartir.ColumnTypeString},
+			{Name: ColumnNameCount, Type: chartir.ColumnTypeNumber},
+		},
+		Rows: [][]string{},
+	}
+	for _, binName := range binNames {
+		ds.Rows = append(ds.Rows, []string{binName, formatInt(int64(hist.BinValueOrDefault(binName, 0)))})
+	}
+	mark := chartir.Mark{
+		ID:        ds.ID,
+		DatasetID: ds.ID,
+		Geometry:  opts.geometry(chartir.GeometryBar),
+		Encode:    chartir.Encode{X: ColumnNameBin, Y: ColumnNameCount},
+		Name:      ds.ID,
+	}
+	return newChartIR(opts.title(hist.Name), chartir.AxisTypeCategory, []chartir.Dataset{ds}, []chartir.Mark{mark}, opts), nil
+}
+
+// HistogramSetChartIR returns a ChartIR with bins on a category x axis and
+// one bar mark per histogram. Bars are grouped unless `opts.Stacked` is set.
+// If histNames or binNames are empty, `hset.Order` and `hset.BinNames()`
+// are used, falling back to sorted histogram names.
+func HistogramSetChartIR(hset *histogram.HistogramSet, histNames, binNames []string, opts *Opts) (*chartir.ChartIR, error) {
+	if hset == nil {
+		return nil, histogram.ErrHistogramSetCannotBeNil
+	}
+	if len(histNames) == 0 {
+		if len(hset.Order) > 0 {
+			histNames = hset.Order
+		} else {
+			histNames = hset.ItemNames()
+			sort.Strings(histNames)
+		}
+	}
+	if len(histNames) == 0 {
+		return nil, fmt.Errorf("chartir: histogram set has no histograms")
+	}
+	if len(binNames) == 0 {
+		binNames = hset.BinNames()
+	}
+
+	ds := chartir.Dataset{
+		ID:      datasetID(hset.Name, "histogramset"),
+		Columns: []chartir.Column{{Name: ColumnNameBin, Type: chartir.ColumnTypeString}},
+		Rows:    [][]string{},
+	}
+	for _, histName := range histNames {
+		if histName == ColumnNameBin {
+			return nil, fmt.Errorf("chartir: histogram name conflicts with bin column: %s", histName)
+		}
+		ds.Columns = append(ds.Columns, chartir.Column{Name: histName, Type: chartir.ColumnTypeNumber})
+	}
+	for _, binName := range binNames {
+		row := []string{binName}
+		for _, histName := range histNames {
+			row = append(row, formatInt(int64(hset.BinValue(histName, binName))))
+		}
+		ds.Rows = append(ds.Rows, row)
+	}
+
+	var marks []chartir.Mark
+	for _, histName := range histNames {
+		marks = append(marks, chartir.Mark{
+			ID:        histName,
+			DatasetID: ds.ID,
+			Geometry:  opts.geometry(chartir.GeometryBar),
+			Encode:    chartir.Encode{X: ColumnNameBin, Y: histName},
+			Stack:     opts.stack(),
+			Name:      histName,
+		})
+	}
+	return newChartIR(opts.title(hset.Name), chartir.AxisTypeCategory, []chartir.Dataset{ds}, marks, opts), nil
+}
@@ -0,0 +1,121 @@
+package data2chartir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/data/table"
+)
+
+// TableChartIR returns a ChartIR with one mark per y column plotted against
+// the x column. The x axis type is derived from the x column's `FormatMap`
+// entry: `date` and `time` produce a time axis, `int`, `float` and `percent`
+// produce a value axis and all other formats produce a category axis.
+// Y column values must be numeric or empty.
+func TableChartIR(tbl *table.Table, xColName string, yColNames []string, opts *Opts) (*chartir.ChartIR, error) {
+	if tbl == nil {
+		return nil, table.ErrTableCannotBeNil
+	} else if len(yColNames) == 0 {
+		return nil, fmt.Errorf("chartir: no y columns specified")
+	}
+	xIdx := tbl.Columns.Index(xColName)
+	if xIdx < 0 {
+		return nil, fmt.Errorf("chartir: column not found: %s", xColName)
+	}
+	yIdxs := make([]int, len(yColNames))
+	for i, yColName := range yColNames {
+		if yIdxs[i] = tbl.Columns.Index(yColName); yIdxs[i] < 0 {
+			return nil, fmt.Errorf("chartir: column not found: %s", yColName)
+		}
+	}
+
+	xAxisType := chartir.AxisTypeCategory
+	xColType := chartir.ColumnTypeString
+	switch tbl.FormatMap.FormatForIdx(xIdx) {
+	case table.FormatDate, table.FormatTime:
+		xAxisType = chartir.AxisTypeTime
+	case table.FormatInt, table.FormatFloat, table.FormatPercent:
+		xAxisType = chartir.AxisTypeValue
+		xColType = chartir.ColumnTypeNumber
+	}
+
+	ds := chartir.Dataset{
+		ID:      datasetID(tbl.Name, "table"),
+		Columns: []chartir.Column{{Name: xColName, Type: xColType}},
+	}
+	for _, yColName := range yColNames {
+		ds.Columns = append(ds.Columns, chartir.Column{Name: yColName, Type: chartir.ColumnTypeNumber})
+	}
+
+	for i, row := range tableRows(tbl) {
+		out := make([]string, 0, len(yIdxs)+1)
+		x := cell(row, xIdx)
+		if xColType == chartir.ColumnTypeNumber && x != "" {
+			if _, err := strconv.ParseFloat(x, 64); err != nil {
+				return nil, fmt.Errorf("chartir: non-numeric value (%s) in row (%d) column (%s)", x, i, xColName)
+			}
+		}
+		out = append(out, x)
+		for j, yIdx := range yIdxs {
+			y := cell(row, yIdx)
+			if y != "" {
+				if _, err := strconv.ParseFloat(y, 64); err != nil {
+					return nil, fmt.Errorf("chartir: non-numeric value (%s) in row (%d) column (%s)", y, i, yColNames[j])
+				}
+			}
+			out = append(out, y)
+		}
+		ds.Rows = append(ds.Rows, out)
+	}
+
+	defGeometry := chartir.GeometryLine
+	if xAxisType == chartir.AxisTypeCategory {
+		defGeometry = chartir.GeometryBar
+	}
+	geometry := opts.geometry(defGeometry)
+	var marks []chartir.Mark
+	for _, yColName := range yColNames {
+		marks = append(marks, chartir.Mark{
+			ID:        yColName,
+			DatasetID: ds.ID,
+			Geometry:  geometry,
+			Encode:    chartir.Encode{X: xColName, Y: yColName},
+			Stack:     opts.stack(),
+			Name:      yColName,
+		})
+	}
+	return newChartIR(opts.title(tbl.Name), xAxisType, []chartir.Dataset{ds}, marks, opts), nil
+}
+
+// tableRows returns the table rows as strings, formatting `RowsFloat64`
+// for float tables.
+func tableRows(tbl *table.Table) [][]string {
+	if !tbl.IsFloat64 {
+		return tbl.Rows
+	}
+	rows := make([][]string, len(tbl.RowsFloat64))
+	for i, rowFloat := range tbl.RowsFloat64 {
+		row := make([]string, len(rowFloat))
+		for j, v := range rowFloat {
+			row[j] = formatFloat(v)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+func cell(row []string, idx int) string {
+	if idx < len(row) {
+		return strings.TrimSpace(row[idx])
+	}
+	return ""
+}
+
+func datasetID(name, def string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	return def
+}
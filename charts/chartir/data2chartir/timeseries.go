@@ -0,0 +1,102 @@
+package data2chartir
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/data/timeseries"
+)
+
+const (
+	ColumnNameTime  = "time"
+	ColumnNameValue = "value"
+)
+
+// TimeSeriesChartIR returns a ChartIR with a single mark on a time x axis.
+// Times are formatted as RFC 3339 strings.
+func TimeSeriesChartIR(ts timeseries.TimeSeries, opts *Opts) (*chartir.ChartIR, error) {
+	ds := timeSeriesDataset(datasetID(ts.SeriesName, ColumnNameValue), ts)
+	mark := timeSeriesMark(ds.ID, opts)
+	if ts.SeriesName != "" {
+		mark.Name = ts.SeriesName
+	}
+	return newChartIR(opts.title(ts.SeriesName), chartir.AxisTypeTime, []chartir.Dataset{ds}, []chartir.Mark{mark}, opts), nil
+}
+
+// TimeSeriesSetChartIR returns a ChartIR with one dataset and one mark per
+// series on a shared time x axis. Series are ordered by `set.Order`,
+// falling back to sorted series names. Each series has its own dataset so
+// times missing from a series are left out rather than set to zero.
+func TimeSeriesSetChartIR(set timeseries.TimeSeriesSet, opts *Opts) (*chartir.ChartIR, error) {
+	if len(set.Series) == 0 {
+		return nil, fmt.Errorf("chartir: time series set has no series")
+	}
+	var datasets []chartir.Dataset
+	var marks []chartir.Mark
+	for _, seriesName := range timeSeriesSetOrder(set) {
+		ts, ok := set.Series[seriesName]
+		if !ok {
+			return nil, fmt.Errorf("chartir: series not found: %s", seriesName)
+		}
+		ds := timeSeriesDataset(seriesName, ts)
+		mark := timeSeriesMark(ds.ID, opts)
+		mark.Name = seriesName
+		datasets = append(datasets, ds)
+		marks = append(marks, mark)
+	}
+	return newChartIR(opts.title(set.Name), chartir.AxisTypeTime, datasets, marks, opts), nil
+}
+
+func timeSeriesSetOrder(set timeseries.TimeSeriesSet) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range set.Order {
+		if name = strings.TrimSpace(name); name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		return names
+	}
+	for name := range set.Series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func timeSeriesDataset(id string, ts timeseries.TimeSeries) chartir.Dataset {
+	ds := chartir.Dataset{
+		ID: id,
+		Columns: []chartir.Column{
+			{Name: ColumnNameTime, Type: chartir.ColumnTypeString},
+			{Name: ColumnNameValue, Type: chartir.ColumnTypeNumber},
+		},
+		Rows: [][]string{},
+	}
+	for _, item := range ts.ItemsSorted() {
+		var val string
+		if item.IsFloat {
+			val = formatFloat(item.ValueFloat)
+		} else {
+			val = formatInt(item.Value)
+		}
+		ds.Rows = append(ds.Rows, []string{item.Time.UTC().Format(time.RFC3339), val})
+	}
+	return ds
+}
+
+func timeSeriesMark(datasetID string, opts *Opts) chartir.Mark {
+	return chartir.Mark{
+		ID:        datasetID,
+		DatasetID: datasetID,
+		Geometry:  opts.geometry(chartir.GeometryLine),
+		Encode:    chartir.Encode{X: ColumnNameTime, Y: ColumnNameValue},
+		Stack:     opts.stack(),
+		Name:      datasetID,
+	}
+}
@@ -10,6 +10,14 @@ const (
 	ColumnTypeNumber ColumnType = "number"
 )
 
+// ColumnTypes returns all valid column type values.
+func ColumnTypes() []ColumnType {
+	return []ColumnType{
+		ColumnTypeString,
+		ColumnTypeNumber,
+	}
+}
+
 // Column defines a typed column in a dataset.
 type Column struct {
 	// Name is the column name/header.
@@ -54,6 +62,16 @@ func (d *Dataset) GetColumn(name string) *Column {
 	return &d.Columns[idx]
 }
 
+// ColumnIs returns true if the dataset has a column with the given name and
+// type. It returns false for a nil dataset.
+func (d *Dataset) ColumnIs(name string, colType ColumnType) bool {
+	if d == nil {
+		return false
+	}
+	col := d.GetColumn(name)
+	return col != nil && col.Type == colType
+}
+
 // GetStringValues returns all string values for the given column name.
 func (d *Dataset) GetStringValues(colName string) []string {
 	idx := d.ColumnIndex(colName)
@@ -0,0 +1,252 @@
+// Package echarts compiles ChartIR to go-echarts for interactive HTML
+// rendering with Apache ECharts.
+package echarts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+// Chart is the interface for compiled go-echarts charts.
+type Chart interface {
+	components.Charter
+	Render(w io.Writer) error
+	JSON() map[string]any
+}
+
+// Compiler converts ChartIR to go-echarts types.
+type Compiler struct {
+	// Width is the chart width in pixels. Default: 900.
+	Width int
+
+	// Height is the chart height in pixels. Default: 500.
+	Height int
+
+	// Theme is the go-echarts theme name. Default: "white".
+	Theme string
+
+	// AssetsHost is the host for the ECharts JavaScript assets.
+	// If empty, the go-echarts default host is used.
+	AssetsHost string
+}
+
+// NewCompiler creates a new compiler with default settings.
+func NewCompiler() *Compiler {
+	return &Compiler{
+		Width:  900,
+		Height: 500,
+		Theme:  "white",
+	}
+}
+
+// Compile validates the ChartIR with chartir.Validate and converts it
+// to a go-echarts chart. Cartesian geometries
+// (bar, line, area, scatter) can be combined in one chart. All other
+// geometries require every mark to share the same geometry.
+func (c *Compiler) Compile(ir *chartir.ChartIR) (Chart, error) {
+	if err := chartir.Validate(ir); err != nil {
+		return nil, err
+	}
+
+	// Determine chart type from first mark's geometry
+	geometry := ir.Marks[0].Geometry
+	for _, mark := range ir.Marks[1:] {
+		if mark.Geometry == geometry {
+			continue
+		}
+		if !geometry.IsCartesian() || !mark.Geometry.IsCartesian() {
+			return nil, fmt.Errorf("chartir: cannot combine geometries: %s, %s", geometry, mark.Geometry)
+		}
+	}
+
+	switch geometry {
+	case chartir.GeometryBar, chartir.GeometryLine, chartir.GeometryArea, chartir.GeometryScatter:
+		return c.compileCartesianChart(ir)
+	case chartir.GeometryHeatmap:
+		return c.compileHeatmapChart(ir)
+	case chartir.GeometryPie:
+		return c.compilePieChart(ir)
+	case chartir.GeometryFunnel:
+		return c.compileFunnelChart(ir)
+	case chartir.GeometryGauge:
+		return c.compileGaugeChart(ir)
+	case chartir.GeometryRadar:
+		return c.compileRadarChart(ir)
+	case chartir.GeometryTreemap:
+		return c.compileTreemapChart(ir)
+	case chartir.GeometrySankey:
+		return c.compileSankeyChart(ir)
+	default:
+		return nil, fmt.Errorf("chartir: unsupported geometry: %s", geometry)
+	}
+}
+
+// Options returns the ECharts option object for the ChartIR, suitable
+// for JSON encoding and passing to `echarts.setOption()`.
+func (c *Compiler) Options(ir *chartir.ChartIR) (map[string]any, error) {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return nil, err
+	}
+	chart.Validate()
+	b, err := json.Marshal(chart.JSON())
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]any{}
+	return out, json.Unmarshal(funcMarkers.ReplaceAll(b, nil), &out)
+}
+
+// funcMarkers matches the markers go-echarts puts around JavaScript
+// literals, such as array border radii, which are unquoted when rendered.
+var funcMarkers = regexp.MustCompile(`(__f__")|("__f__)|(__f__)`)
+
+// RenderHTML renders the ChartIR as a standalone HTML page.
+func (c *Compiler) RenderHTML(ir *chartir.ChartIR, w io.Writer) error {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return err
+	}
+	return chart.Render(w)
+}
+
+// WriteHTMLFile renders the ChartIR as a standalone HTML page and writes it to a file.
+func (c *Compiler) WriteHTMLFile(ir *chartir.ChartIR, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := c.RenderHTML(ir, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// globalOptions returns the options shared by all chart types.
+func (c *Compiler) globalOptions(ir *chartir.ChartIR) []charts.GlobalOpts {
+	init := opts.Initialization{
+		PageTitle:  ir.Title,
+		Theme:      c.Theme,
+		AssetsHost: c.AssetsHost,
+	}
+	if c.Width > 0 {
+		init.Width = fmt.Sprintf("%dpx", c.Width)
+	}
+	if c.Height > 0 {
+		init.Height = fmt.Sprintf("%dpx", c.Height)
+	}
+
+	out := []charts.GlobalOpts{
+		charts.WithInitializationOpts(init),
+		charts.WithTitleOpts(opts.Title{Title: ir.Title}),
+	}
+	if ir.Legend != nil {
+		out = append(out, charts.WithLegendOpts(legendOptions(ir.Legend)))
+	}
+	if ir.Tooltip != nil {
+		out = append(out, charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(ir.Tooltip.Show),
+			Trigger: string(ir.Tooltip.Trigger),
+		}))
+	}
+	if ir.Grid != nil {
+		out = append(out, charts.WithGridOpts(opts.Grid{
+			Left:         ir.Grid.Left,
+			Right:        ir.Grid.Right,
+			Top:          ir.Grid.Top,
+			Bottom:       ir.Grid.Bottom,
+			Width:        ir.Grid.Width,
+			Height:       ir.Grid.Height,
+			ContainLabel: opts.Bool(ir.Grid.ContainLabel),
+		}))
+	}
+	return out
+}
+
+func legendOptions(l *chartir.Legend) opts.Legend {
+	legend := opts.Legend{Show: opts.Bool(l.Show)}
+	switch l.Position {
+	case chartir.LegendPositionTop:
+		legend.Top = "top"
+	case chartir.LegendPositionBottom:
+		legend.Top = "bottom"
+	case chartir.LegendPositionLeft:
+		legend.Left = "left"
+		legend.Orient = "vertical"
+	case chartir.LegendPositionRight:
+		legend.Left = "right"
+		legend.Orient = "vertical"
+	}
+	if len(l.Items) > 0 {
+		legend.Data = l.Items
+	}
+	return legend
+}
+
+// requireColumn verifies the column is defined in the dataset.
+func requireColumn(dataset *chartir.Dataset, colName string) error {
+	if colName == "" {
+		return fmt.Errorf("chartir: encode column not specified for dataset: %s", dataset.ID)
+	} else if dataset.ColumnIndex(colName) < 0 {
+		return fmt.Errorf("chartir: column not found: %s", colName)
+	}
+	return nil
+}
+
+// itemStyle converts the Style to an ECharts item style, or nil if
+// no item styling is set.
+func itemStyle(s *chartir.Style) *opts.ItemStyle {
+	if s == nil {
+		return nil
+	}
+	is := &opts.ItemStyle{
+		Color:       s.Color,
+		BorderColor: s.BorderColor,
+	}
+	if s.Opacity != nil {
+		is.Opacity = opts.Float(float32(*s.Opacity))
+	}
+	if s.BorderWidth != nil {
+		is.BorderWidth = float32(*s.BorderWidth)
+	}
+	if s.BorderRadius != nil {
+		is.BorderRadius = borderRadius(s.BorderRadius)
+	}
+	if *is == (opts.ItemStyle{}) {
+		return nil
+	}
+	return is
+}
+
+// borderRadius returns a border radius for the string item style field.
+// Strings, such as "50%", are used as is. Numbers and per-corner arrays,
+// such as [4, 4, 0, 0], are inserted as JSON literals, since ECharts does
+// not accept them as strings.
+func borderRadius(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(opts.FuncOpts(string(b)))
+}
+
+// styleSeriesOptions returns the series options shared by all geometries.
+func styleSeriesOptions(s *chartir.Style) []charts.SeriesOpts {
+	var out []charts.SeriesOpts
+	if is := itemStyle(s); is != nil {
+		out = append(out, charts.WithItemStyleOpts(*is))
+	}
+	return out
+}
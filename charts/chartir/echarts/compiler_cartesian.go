@@ -0,0 +1,334 @@
+package echarts
+
+import (
+	"fmt"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+// rectChart is a chart in rectangular coordinates that other
+// rectangular charts can be overlapped onto.
+type rectChart interface {
+	Chart
+	charts.Overlaper
+	Overlap(a ...charts.Overlaper)
+	SetGlobalOptions(options ...charts.GlobalOpts) *charts.RectChart
+	ExtendYAxis(yAxis ...opts.YAxis)
+}
+
+// cartesianMark holds the resolved columns for one mark.
+type cartesianMark struct {
+	mark    chartir.Mark
+	dataset *chartir.Dataset
+	catCol  string
+	valCol  string
+
+	// yAxisIndex is 1 for marks on a right-positioned secondary y-axis.
+	yAxisIndex int
+}
+
+func (c *Compiler) compileCartesianChart(ir *chartir.ChartIR) (Chart, error) {
+	var cms []cartesianMark
+	horizontal := false
+	hasBar := false
+	var rightAxis *chartir.Axis
+	for i, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		catCol, valCol, horiz := mark.CartesianColumns(dataset)
+		if err := requireColumn(dataset, catCol); err != nil {
+			return nil, err
+		}
+		if err := requireColumn(dataset, valCol); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			horizontal = horiz
+		} else if mark.Geometry == chartir.GeometryBar && horiz != horizontal {
+			return nil, fmt.Errorf("chartir: cannot combine horizontal and vertical bars")
+		}
+		if mark.Geometry == chartir.GeometryBar {
+			hasBar = true
+		}
+		cm := cartesianMark{mark: mark, dataset: dataset, catCol: catCol, valCol: valCol}
+		if axis := ir.GetMarkYAxis(mark); axis != nil && axis.Position == chartir.AxisPositionRight {
+			if rightAxis != nil && rightAxis.ID != axis.ID {
+				return nil, fmt.Errorf("chartir: multiple secondary y-axes are not supported: %s, %s", rightAxis.ID, axis.ID)
+			}
+			rightAxis = axis
+			cm.yAxisIndex = 1
+		}
+		cms = append(cms, cm)
+	}
+	if horizontal && rightAxis != nil {
+		return nil, fmt.Errorf("chartir: horizontal bars do not support a secondary y-axis")
+	}
+	if horizontal {
+		for _, mark := range ir.Marks {
+			if mark.Geometry != chartir.GeometryBar {
+				return nil, fmt.Errorf("chartir: cannot combine horizontal bars with geometry: %s", mark.Geometry)
+			}
+		}
+	}
+
+	// The category axis is the y-axis for horizontal bars and the x-axis otherwise.
+	catAxis := ir.GetXAxis()
+	valAxis := leftYAxis(ir)
+	if horizontal {
+		catAxis, valAxis = valAxis, catAxis
+	}
+	categorical := hasBar
+	if catAxis != nil && catAxis.Type != "" {
+		if catAxis.Type == chartir.AxisTypeCategory {
+			categorical = true
+		} else if hasBar {
+			return nil, fmt.Errorf("chartir: bar geometry requires a category axis, got: %s", catAxis.Type)
+		}
+	} else if !cms[0].dataset.ColumnIs(cms[0].catCol, chartir.ColumnTypeNumber) {
+		categorical = true
+	}
+
+	var categories []string
+	if categorical {
+		categories = cartesianCategories(cms)
+	}
+
+	var base rectChart
+	for _, cm := range cms {
+		var data []any
+		if categorical {
+			data = categoryData(cm, categories)
+		} else {
+			data = pairData(cm, catAxis)
+		}
+		chart := newCartesianMarkChart(cm.mark, categories, data, cm.yAxisIndex)
+		if base == nil {
+			base = chart
+		} else {
+			base.Overlap(chart)
+		}
+	}
+
+	catAxisType := "category"
+	if !categorical {
+		catAxisType = "value"
+		if catAxis != nil && catAxis.Type != "" {
+			catAxisType = string(catAxis.Type)
+		}
+	}
+	valAxisType := "value"
+	if valAxis != nil && valAxis.Type == chartir.AxisTypeLog {
+		valAxisType = string(chartir.AxisTypeLog)
+	}
+
+	globalOpts := c.globalOptions(ir)
+	if horizontal {
+		globalOpts = append(globalOpts,
+			charts.WithXAxisOpts(xAxisOptions(valAxis, valAxisType)),
+			charts.WithYAxisOpts(yAxisOptions(catAxis, catAxisType)))
+	} else {
+		globalOpts = append(globalOpts,
+			charts.WithXAxisOpts(xAxisOptions(catAxis, catAxisType)),
+			charts.WithYAxisOpts(yAxisOptions(valAxis, valAxisType)))
+	}
+	base.SetGlobalOptions(globalOpts...)
+	if rightAxis != nil {
+		yAxis := yAxisOptions(rightAxis, "value")
+		if rightAxis.Type == chartir.AxisTypeLog {
+			yAxis.Type = string(chartir.AxisTypeLog)
+		}
+		yAxis.Position = string(chartir.AxisPositionRight)
+		base.ExtendYAxis(yAxis)
+	}
+
+	if horizontal {
+		if bar, ok := base.(*charts.Bar); ok {
+			bar.XYReversal()
+		}
+	}
+
+	return base, nil
+}
+
+// cartesianCategories returns the union of category labels across marks,
+// in order of first appearance.
+func cartesianCategories(cms []cartesianMark) []string {
+	var categories []string
+	seen := map[string]bool{}
+	for _, cm := range cms {
+		for _, label := range cm.dataset.GetStringValues(cm.catCol) {
+			if !seen[label] {
+				seen[label] = true
+				categories = append(categories, label)
+			}
+		}
+	}
+	return categories
+}
+
+// categoryData returns the mark values aligned to categories. Categories
+// not present in the mark's dataset are set to "-", the ECharts
+// placeholder for missing data.
+func categoryData(cm cartesianMark, categories []string) []any {
+	labels := cm.dataset.GetStringValues(cm.catCol)
+	values := cm.dataset.GetFloat64Values(cm.valCol)
+	lookup := map[string]float64{}
+	for i, label := range labels {
+		lookup[label] = values[i]
+	}
+	data := make([]any, len(categories))
+	for i, category := range categories {
+		if v, ok := lookup[category]; ok {
+			data[i] = v
+		} else {
+			data[i] = "-"
+		}
+	}
+	return data
+}
+
+// pairData returns the mark values as [x, y] pairs. Time axis x values
+// are passed through as strings for ECharts to parse.
+func pairData(cm cartesianMark, xAxis *chartir.Axis) []any {
+	yValues := cm.dataset.GetFloat64Values(cm.valCol)
+	data := make([]any, len(yValues))
+	if xAxis != nil && xAxis.Type == chartir.AxisTypeTime {
+		xValues := cm.dataset.GetStringValues(cm.catCol)
+		for i := range yValues {
+			data[i] = []any{xValues[i], yValues[i]}
+		}
+	} else {
+		xValues := cm.dataset.GetFloat64Values(cm.catCol)
+		for i := range yValues {
+			data[i] = []any{xValues[i], yValues[i]}
+		}
+	}
+	return data
+}
+
+// leftYAxis returns the first vertical axis that is not right-positioned.
+func leftYAxis(ir *chartir.ChartIR) *chartir.Axis {
+	for i := range ir.Axes {
+		if ir.Axes[i].IsVertical() && ir.Axes[i].Position != chartir.AxisPositionRight {
+			return &ir.Axes[i]
+		}
+	}
+	return nil
+}
+
+func newCartesianMarkChart(mark chartir.Mark, categories []string, data []any, yAxisIndex int) rectChart {
+	name := mark.DisplayName()
+	seriesOpts := styleSeriesOptions(mark.Style)
+	s := mark.Style
+	if s == nil {
+		s = &chartir.Style{}
+	}
+
+	switch mark.Geometry {
+	case chartir.GeometryBar:
+		chart := charts.NewBar()
+		if categories != nil {
+			chart.SetXAxis(categories)
+		}
+		items := make([]opts.BarData, len(data))
+		for i, v := range data {
+			items[i] = opts.BarData{Value: v}
+		}
+		barOpts := opts.BarChart{
+			YAxisIndex: yAxisIndex,
+			Stack:      mark.Stack,
+			BarGap:     s.BarGap,
+		}
+		if s.BarWidth != nil {
+			barOpts.BarWidth = fmt.Sprint(s.BarWidth)
+		}
+		seriesOpts = append(seriesOpts, charts.WithBarChartOpts(barOpts))
+		chart.AddSeries(name, items, seriesOpts...)
+		return chart
+	case chartir.GeometryScatter:
+		chart := charts.NewScatter()
+		if categories != nil {
+			chart.SetXAxis(categories)
+		}
+		items := make([]opts.ScatterData, len(data))
+		for i, v := range data {
+			items[i] = opts.ScatterData{Value: v}
+		}
+		scatterOpts := opts.ScatterChart{YAxisIndex: yAxisIndex, Symbol: s.Symbol}
+		if s.SymbolSize != nil {
+			scatterOpts.SymbolSize = *s.SymbolSize
+		}
+		seriesOpts = append(seriesOpts, charts.WithScatterChartOpts(scatterOpts))
+		chart.AddSeries(name, items, seriesOpts...)
+		return chart
+	default:
+		chart := charts.NewLine()
+		if categories != nil {
+			chart.SetXAxis(categories)
+		}
+		items := make([]opts.LineData, len(data))
+		for i, v := range data {
+			items[i] = opts.LineData{Value: v}
+		}
+		lineOpts := opts.LineChart{
+			YAxisIndex: yAxisIndex,
+			Stack:      mark.Stack,
+			Symbol:     s.Symbol,
+		}
+		if mark.Smooth || s.Smooth {
+			lineOpts.Smooth = opts.Bool(true)
+		}
+		if s.SymbolSize != nil {
+			lineOpts.SymbolSize = *s.SymbolSize
+		}
+		seriesOpts = append(seriesOpts, charts.WithLineChartOpts(lineOpts))
+		if s.LineWidth != nil || s.Color != "" {
+			lineStyle := opts.LineStyle{Color: s.Color}
+			if s.LineWidth != nil {
+				lineStyle.Width = float32(*s.LineWidth)
+			}
+			seriesOpts = append(seriesOpts, charts.WithLineStyleOpts(lineStyle))
+		}
+		if mark.Geometry == chartir.GeometryArea {
+			areaStyle := opts.AreaStyle{}
+			if s.AreaOpacity != nil {
+				areaStyle.Opacity = opts.Float(float32(*s.AreaOpacity))
+			}
+			seriesOpts = append(seriesOpts, charts.WithAreaStyleOpts(areaStyle))
+		}
+		chart.AddSeries(name, items, seriesOpts...)
+		return chart
+	}
+}
+
+func xAxisOptions(axis *chartir.Axis, axisType string) opts.XAxis {
+	xAxis := opts.XAxis{Type: axisType}
+	if axis != nil {
+		xAxis.Name = axis.Name
+		if axis.Min != nil {
+			xAxis.Min = *axis.Min
+		}
+		if axis.Max != nil {
+			xAxis.Max = *axis.Max
+		}
+	}
+	return xAxis
+}
+
+func yAxisOptions(axis *chartir.Axis, axisType string) opts.YAxis {
+	yAxis := opts.YAxis{Type: axisType}
+	if axis != nil {
+		yAxis.Name = axis.Name
+		if axis.Min != nil {
+			yAxis.Min = *axis.Min
+		}
+		if axis.Max != nil {
+			yAxis.Max = *axis.Max
+		}
+	}
+	return yAxis
+}
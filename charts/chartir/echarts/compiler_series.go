@@ -0,0 +1,344 @@
+package echarts
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/mogo/type/stringsutil"
+)
+
+// nameValues returns the names and values for a mark using Encode.NameValueColumns.
+func nameValues(ir *chartir.ChartIR, mark chartir.Mark) ([]string, []float64, error) {
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, nil, err
+	}
+	nameCol, valueCol := mark.Encode.NameValueColumns()
+	if err := requireColumn(dataset, nameCol); err != nil {
+		return nil, nil, err
+	}
+	if err := requireColumn(dataset, valueCol); err != nil {
+		return nil, nil, err
+	}
+	return dataset.GetStringValues(nameCol), dataset.GetFloat64Values(valueCol), nil
+}
+
+func (c *Compiler) compilePieChart(ir *chartir.ChartIR) (Chart, error) {
+	chart := charts.NewPie()
+	chart.SetGlobalOptions(c.globalOptions(ir)...)
+	for _, mark := range ir.Marks {
+		names, values, err := nameValues(ir, mark)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]opts.PieData, len(names))
+		for i := range names {
+			items[i] = opts.PieData{Name: names[i], Value: values[i]}
+		}
+		chart.AddSeries(mark.DisplayName(), items, styleSeriesOptions(mark.Style)...)
+	}
+	return chart, nil
+}
+
+// compileFunnelChart compiles funnel marks. Style.FunnelSort is supported;
+// FunnelAlign and FunnelGap are not exposed by go-echarts and are ignored.
+func (c *Compiler) compileFunnelChart(ir *chartir.ChartIR) (Chart, error) {
+	chart := charts.NewFunnel()
+	chart.SetGlobalOptions(c.globalOptions(ir)...)
+	for _, mark := range ir.Marks {
+		names, values, err := nameValues(ir, mark)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]opts.FunnelData, len(names))
+		for i := range names {
+			items[i] = opts.FunnelData{Name: names[i], Value: values[i]}
+		}
+		seriesOpts := styleSeriesOptions(mark.Style)
+		if mark.Style != nil && mark.Style.FunnelSort != "" {
+			sort := mark.Style.FunnelSort
+			seriesOpts = append(seriesOpts, charts.WithSeriesOpts(func(s *charts.SingleSeries) {
+				s.Sort = sort
+			}))
+		}
+		chart.AddSeries(mark.DisplayName(), items, seriesOpts...)
+	}
+	return chart, nil
+}
+
+func (c *Compiler) compileGaugeChart(ir *chartir.ChartIR) (Chart, error) {
+	chart := charts.NewGauge()
+	chart.SetGlobalOptions(c.globalOptions(ir)...)
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		nameCol, valueCol := mark.Encode.NameValueColumns()
+		if err := requireColumn(dataset, valueCol); err != nil {
+			return nil, err
+		}
+		values := dataset.GetFloat64Values(valueCol)
+		names := dataset.GetStringValues(nameCol)
+		items := make([]opts.GaugeData, len(values))
+		for i := range values {
+			items[i] = opts.GaugeData{Value: values[i]}
+			if names != nil {
+				items[i].Name = names[i]
+			}
+		}
+		seriesOpts := styleSeriesOptions(mark.Style)
+		if s := mark.Style; s != nil {
+			seriesOpts = append(seriesOpts, charts.WithSeriesOpts(func(ss *charts.SingleSeries) {
+				if s.GaugeMin != nil {
+					ss.Min = int(math.Floor(*s.GaugeMin))
+				}
+				if s.GaugeMax != nil {
+					ss.Max = int(math.Ceil(*s.GaugeMax))
+				}
+				if s.StartAngle != nil {
+					ss.StartAngle = *s.StartAngle
+				}
+				if s.EndAngle != nil {
+					ss.EndAngle = *s.EndAngle
+				}
+			}))
+		}
+		chart.AddSeries(mark.DisplayName(), items, seriesOpts...)
+	}
+	return chart, nil
+}
+
+// compileRadarChart compiles radar marks. Each mark is one radar polygon
+// with Encode.Indicator naming the indicator and Encode.Value its value.
+// Indicator maximums are set to the largest value across all marks.
+func (c *Compiler) compileRadarChart(ir *chartir.ChartIR) (Chart, error) {
+	type radarMark struct {
+		mark   chartir.Mark
+		lookup map[string]float64
+	}
+	var rms []radarMark
+	var indicators []string
+	maxValue := 0.0
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		indCol := stringsutil.FirstNonEmpty(mark.Encode.Indicator, mark.Encode.Name, mark.Encode.Category)
+		valueCol := stringsutil.FirstNonEmpty(mark.Encode.Value, mark.Encode.Y)
+		if err := requireColumn(dataset, indCol); err != nil {
+			return nil, err
+		}
+		if err := requireColumn(dataset, valueCol); err != nil {
+			return nil, err
+		}
+		names := dataset.GetStringValues(indCol)
+		values := dataset.GetFloat64Values(valueCol)
+		rm := radarMark{mark: mark, lookup: map[string]float64{}}
+		for i, name := range names {
+			if !slices.Contains(indicators, name) {
+				indicators = append(indicators, name)
+			}
+			rm.lookup[name] = values[i]
+			maxValue = math.Max(maxValue, values[i])
+		}
+		rms = append(rms, rm)
+	}
+
+	radar := opts.RadarComponent{}
+	for _, name := range indicators {
+		radar.Indicator = append(radar.Indicator, &opts.Indicator{Name: name, Max: float32(maxValue)})
+	}
+	if s := ir.Marks[0].Style; s != nil {
+		radar.Shape = s.Shape
+	}
+
+	chart := charts.NewRadar()
+	chart.SetGlobalOptions(append(c.globalOptions(ir), charts.WithRadarComponentOpts(radar))...)
+	for _, rm := range rms {
+		values := make([]float64, len(indicators))
+		for i, name := range indicators {
+			values[i] = rm.lookup[name]
+		}
+		name := rm.mark.DisplayName()
+		chart.AddSeries(name, []opts.RadarData{{Name: name, Value: values}}, styleSeriesOptions(rm.mark.Style)...)
+	}
+	return chart, nil
+}
+
+// compileHeatmapChart compiles heatmap marks with Encode.X and Encode.Y as
+// category axes and Encode.Heat as the cell intensity.
+func (c *Compiler) compileHeatmapChart(ir *chartir.ChartIR) (Chart, error) {
+	var xCats, yCats []string
+	xIndex := map[string]int{}
+	yIndex := map[string]int{}
+	type heatSeries struct {
+		mark chartir.Mark
+		data []opts.HeatMapData
+	}
+	var series []heatSeries
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		heatCol := stringsutil.FirstNonEmpty(mark.Encode.Heat, mark.Encode.Value)
+		for _, colName := range []string{mark.Encode.X, mark.Encode.Y, heatCol} {
+			if err := requireColumn(dataset, colName); err != nil {
+				return nil, err
+			}
+		}
+		xs := dataset.GetStringValues(mark.Encode.X)
+		ys := dataset.GetStringValues(mark.Encode.Y)
+		heats := dataset.GetFloat64Values(heatCol)
+		hs := heatSeries{mark: mark}
+		for i := range heats {
+			xi, ok := xIndex[xs[i]]
+			if !ok {
+				xi = len(xCats)
+				xIndex[xs[i]] = xi
+				xCats = append(xCats, xs[i])
+			}
+			yi, ok := yIndex[ys[i]]
+			if !ok {
+				yi = len(yCats)
+				yIndex[ys[i]] = yi
+				yCats = append(yCats, ys[i])
+			}
+			hs.data = append(hs.data, opts.HeatMapData{Value: [3]any{xi, yi, heats[i]}})
+			minValue = math.Min(minValue, heats[i])
+			maxValue = math.Max(maxValue, heats[i])
+		}
+		series = append(series, hs)
+	}
+	if len(xCats) == 0 {
+		minValue, maxValue = 0, 0
+	}
+
+	xAxis := xAxisOptions(ir.GetXAxis(), string(chartir.AxisTypeCategory))
+	xAxis.Data = xCats
+	xAxis.SplitArea = &opts.SplitArea{Show: opts.Bool(true)}
+	yAxis := yAxisOptions(ir.GetYAxis(), string(chartir.AxisTypeCategory))
+	yAxis.Data = yCats
+	yAxis.SplitArea = &opts.SplitArea{Show: opts.Bool(true)}
+
+	chart := charts.NewHeatMap()
+	chart.SetGlobalOptions(append(c.globalOptions(ir),
+		charts.WithXAxisOpts(xAxis),
+		charts.WithYAxisOpts(yAxis),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: opts.Bool(true),
+			Min:        float32(minValue),
+			Max:        float32(maxValue),
+		}),
+	)...)
+	for _, hs := range series {
+		chart.AddSeries(hs.mark.DisplayName(), hs.data, styleSeriesOptions(hs.mark.Style)...)
+	}
+	return chart, nil
+}
+
+// compileTreemapChart compiles treemap marks. Encode.Name is the leaf name
+// and Encode.Value its size. If Encode.Category is also set, leaves are
+// grouped under parent nodes named by the category column. Values are
+// rounded to integers as required by go-echarts.
+func (c *Compiler) compileTreemapChart(ir *chartir.ChartIR) (Chart, error) {
+	chart := charts.NewTreeMap()
+	chart.SetGlobalOptions(c.globalOptions(ir)...)
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		nameCol := stringsutil.FirstNonEmpty(mark.Encode.Name, mark.Encode.Category)
+		valueCol := mark.Encode.Value
+		if err := requireColumn(dataset, nameCol); err != nil {
+			return nil, err
+		}
+		if err := requireColumn(dataset, valueCol); err != nil {
+			return nil, err
+		}
+		names := dataset.GetStringValues(nameCol)
+		values := dataset.GetFloat64Values(valueCol)
+
+		var nodes []opts.TreeMapNode
+		if mark.Encode.Category != "" && mark.Encode.Category != nameCol {
+			if err := requireColumn(dataset, mark.Encode.Category); err != nil {
+				return nil, err
+			}
+			parents := dataset.GetStringValues(mark.Encode.Category)
+			parentIndex := map[string]int{}
+			for i := range names {
+				pi, ok := parentIndex[parents[i]]
+				if !ok {
+					pi = len(nodes)
+					parentIndex[parents[i]] = pi
+					nodes = append(nodes, opts.TreeMapNode{Name: parents[i]})
+				}
+				nodes[pi].Children = append(nodes[pi].Children, opts.TreeMapNode{
+					Name:  names[i],
+					Value: int(math.Round(values[i])),
+				})
+			}
+		} else {
+			for i := range names {
+				nodes = append(nodes, opts.TreeMapNode{
+					Name:  names[i],
+					Value: int(math.Round(values[i])),
+				})
+			}
+		}
+		chart.AddSeries(mark.DisplayName(), nodes, styleSeriesOptions(mark.Style)...)
+	}
+	return chart, nil
+}
+
+// compileSankeyChart compiles sankey marks using Encode.Source,
+// Encode.Target and Encode.Value as the link definitions. Nodes are
+// derived from the unique source and target names.
+func (c *Compiler) compileSankeyChart(ir *chartir.ChartIR) (Chart, error) {
+	chart := charts.NewSankey()
+	chart.SetGlobalOptions(c.globalOptions(ir)...)
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		for _, colName := range []string{mark.Encode.Source, mark.Encode.Target, mark.Encode.Value} {
+			if err := requireColumn(dataset, colName); err != nil {
+				return nil, err
+			}
+		}
+		sources := dataset.GetStringValues(mark.Encode.Source)
+		targets := dataset.GetStringValues(mark.Encode.Target)
+		values := dataset.GetFloat64Values(mark.Encode.Value)
+
+		var nodes []opts.SankeyNode
+		seen := map[string]bool{}
+		var links []opts.SankeyLink
+		for i := range values {
+			if sources[i] == targets[i] {
+				return nil, fmt.Errorf("chartir: sankey link source and target are the same: %s", sources[i])
+			}
+			for _, name := range []string{sources[i], targets[i]} {
+				if !seen[name] {
+					seen[name] = true
+					nodes = append(nodes, opts.SankeyNode{Name: name})
+				}
+			}
+			links = append(links, opts.SankeyLink{
+				Source: sources[i],
+				Target: targets[i],
+				Value:  float32(values[i]),
+			})
+		}
+		chart.AddSeries(mark.DisplayName(), nodes, links, styleSeriesOptions(mark.Style)...)
+	}
+	return chart, nil
+}
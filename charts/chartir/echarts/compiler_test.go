@@ -0,0 +1,164 @@
+package echarts
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+func salesDataset() chartir.Dataset {
+	return chartir.Dataset{
+		ID: "data",
+		Columns: []chartir.Column{
+			{Name: "month", Type: chartir.ColumnTypeString},
+			{Name: "sales", Type: chartir.ColumnTypeNumber},
+			{Name: "target", Type: chartir.ColumnTypeNumber},
+		},
+		Rows: [][]string{
+			{"Jan", "10", "12"},
+			{"Feb", "20", "18"},
+			{"Mar", "15", "16"},
+		},
+	}
+}
+
+func TestCompileComboChart(t *testing.T) {
+	ir := &chartir.ChartIR{
+		Title:    "Sales vs Target",
+		Datasets: []chartir.Dataset{salesDataset()},
+		Marks: []chartir.Mark{
+			{ID: "sales", DatasetID: "data", Geometry: chartir.GeometryBar,
+				Encode: chartir.Encode{X: "month", Y: "sales"}},
+			{ID: "target", DatasetID: "data", Geometry: chartir.GeometryLine,
+				Encode: chartir.Encode{X: "month", Y: "target"}},
+		},
+		Legend: &chartir.Legend{Show: true, Position: chartir.LegendPositionBottom},
+	}
+
+	options, err := NewCompiler().Options(ir)
+	if err != nil {
+		t.Fatalf("Options failed: %v", err)
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	s := string(b)
+	for _, want := range []string{`"type":"bar"`, `"type":"line"`, `"Jan"`, `"Sales vs Target"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Options missing %s: %s", want, s)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		ds   chartir.Dataset
+		mark chartir.Mark
+	}{
+		{"pie", salesDataset(), chartir.Mark{Geometry: chartir.GeometryPie,
+			Encode: chartir.Encode{Name: "month", Value: "sales"}}},
+		{"funnel", salesDataset(), chartir.Mark{Geometry: chartir.GeometryFunnel,
+			Encode: chartir.Encode{Name: "month", Value: "sales"}}},
+		{"gauge", salesDataset(), chartir.Mark{Geometry: chartir.GeometryGauge,
+			Encode: chartir.Encode{Value: "sales"}}},
+		{"radar", salesDataset(), chartir.Mark{Geometry: chartir.GeometryRadar,
+			Encode: chartir.Encode{Indicator: "month", Value: "sales"}}},
+		{"treemap", salesDataset(), chartir.Mark{Geometry: chartir.GeometryTreemap,
+			Encode: chartir.Encode{Name: "month", Value: "sales"}}},
+		{"heatmap", chartir.Dataset{
+			Columns: []chartir.Column{
+				{Name: "x", Type: chartir.ColumnTypeString},
+				{Name: "y", Type: chartir.ColumnTypeString},
+				{Name: "v", Type: chartir.ColumnTypeNumber},
+			},
+			Rows: [][]string{{"a", "p", "1"}, {"b", "p", "2"}, {"a", "q", "3"}},
+		}, chartir.Mark{Geometry: chartir.GeometryHeatmap,
+			Encode: chartir.Encode{X: "x", Y: "y", Heat: "v"}}},
+		{"sankey", chartir.Dataset{
+			Columns: []chartir.Column{
+				{Name: "from", Type: chartir.ColumnTypeString},
+				{Name: "to", Type: chartir.ColumnTypeString},
+				{Name: "v", Type: chartir.ColumnTypeNumber},
+			},
+			Rows: [][]string{{"a", "b", "5"}, {"b", "c", "3"}},
+		}, chartir.Mark{Geometry: chartir.GeometrySankey,
+			Encode: chartir.Encode{Source: "from", Target: "to", Value: "v"}}},
+	}
+
+	for _, tt := range tests {
+		tt.ds.ID = "data"
+		tt.mark.ID = tt.name
+		tt.mark.DatasetID = "data"
+		ir := &chartir.ChartIR{
+			Title:    tt.name,
+			Datasets: []chartir.Dataset{tt.ds},
+			Marks:    []chartir.Mark{tt.mark},
+		}
+		var buf bytes.Buffer
+		if err := NewCompiler().RenderHTML(ir, &buf); err != nil {
+			t.Errorf("RenderHTML(%s) failed: %v", tt.name, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), "echarts.init") {
+			t.Errorf("RenderHTML(%s) output doesn't look like an ECharts page", tt.name)
+		}
+	}
+}
+
+func TestCompileMixedGeometryError(t *testing.T) {
+	ir := &chartir.ChartIR{
+		Datasets: []chartir.Dataset{salesDataset()},
+		Marks: []chartir.Mark{
+			{ID: "pie", DatasetID: "data", Geometry: chartir.GeometryPie,
+				Encode: chartir.Encode{Name: "month", Value: "sales"}},
+			{ID: "bar", DatasetID: "data", Geometry: chartir.GeometryBar,
+				Encode: chartir.Encode{X: "month", Y: "sales"}},
+		},
+	}
+	if _, err := NewCompiler().Compile(ir); err == nil {
+		t.Error("Compile expected error for pie and bar geometries")
+	}
+}
+
+func TestItemStyleBorderRadius(t *testing.T) {
+	tests := []struct {
+		radius any
+		want   string
+	}{
+		{[]int{4, 4, 0, 0}, `"borderRadius":[4,4,0,0]`},
+		{[]any{4.0, 4.0, 0.0, 0.0}, `"borderRadius":[4,4,0,0]`},
+		{6, `"borderRadius":6`},
+		{"50%", `"borderRadius":"50%"`},
+	}
+	for _, tt := range tests {
+		ir := &chartir.ChartIR{
+			Datasets: []chartir.Dataset{salesDataset()},
+			Marks: []chartir.Mark{{ID: "sales", DatasetID: "data", Geometry: chartir.GeometryBar,
+				Encode: chartir.Encode{X: "month", Y: "sales"},
+				Style:  &chartir.Style{BorderRadius: tt.radius}}},
+		}
+		options, err := NewCompiler().Options(ir)
+		if err != nil {
+			t.Fatalf("Options failed: %v", err)
+		}
+		b, err := json.Marshal(options)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %v", err)
+		}
+		if !strings.Contains(string(b), tt.want) {
+			t.Errorf("Options border radius mismatch: want [%s] got [%s]", tt.want, b)
+		}
+		var buf bytes.Buffer
+		if err := NewCompiler().RenderHTML(ir, &buf); err != nil {
+			t.Fatalf("RenderHTML failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("RenderHTML border radius mismatch: want [%s]", tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package google
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/grokify/mogo/encoding/jsonutil"
+
+	gcharts "github.com/grokify/gocharts/v2/charts/google"
+)
+
+// Google Charts loader packages.
+const (
+	PackageCoreChart = "corechart"
+	PackageGauge     = "gauge"
+	PackageSankey    = "sankey"
+	PackageTreemap   = "treemap"
+)
+
+// Google Charts visualization class names.
+const (
+	TypeAreaChart    = "AreaChart"
+	TypeBarChart     = "BarChart"
+	TypeColumnChart  = "ColumnChart"
+	TypeComboChart   = "ComboChart"
+	TypeGauge        = "Gauge"
+	TypeLineChart    = "LineChart"
+	TypePieChart     = "PieChart"
+	TypeSankey       = "Sankey"
+	TypeScatterChart = "ScatterChart"
+	TypeTreeMap      = "TreeMap"
+)
+
+// Chart is a compiled Google Chart. It implements the `google.Chart`
+// interface of the `charts/google` package.
+type Chart struct {
+	Title     string
+	ChartDiv  string
+	Package   string
+	Type      string
+	DataTable gcharts.DataTable
+	Options   map[string]any
+}
+
+func (chart *Chart) ChartDivOrDefault() string {
+	if div := strings.TrimSpace(chart.ChartDiv); div != "" {
+		return div
+	} else {
+		return gcharts.DefaultChartDiv
+	}
+}
+
+// DataTableJSON returns the DataTable as JSON for `google.visualization.arrayToDataTable()`.
+func (chart *Chart) DataTableJSON() []byte {
+	return chart.DataTable.MustJSON()
+}
+
+// OptionsJSON returns the options as JSON for `chart.draw()`.
+func (chart *Chart) OptionsJSON() []byte {
+	if chart.Options == nil {
+		return []byte(jsonutil.EmptyObject)
+	}
+	return jsonutil.MustMarshalOrDefault(chart.Options, []byte(jsonutil.EmptyObject))
+}
+
+func (chart *Chart) PageTitle() string { return chart.Title }
+
+// PackageOrDefault returns the loader package, defaulting to `corechart`.
+func (chart *Chart) PackageOrDefault() string {
+	if pkg := strings.TrimSpace(chart.Package); pkg != "" {
+		return pkg
+	}
+	return PackageCoreChart
+}
+
+// PageHTML returns a standalone HTML page for the chart.
+func (chart *Chart) PageHTML() string { return ChartPage(chart) }
+
+// WritePageHTML writes a standalone HTML page for the chart.
+func (chart *Chart) WritePageHTML(w io.Writer) { WriteChartPage(w, chart) }
+
+func (chart *Chart) WriteFilePageHTML(filename string, perm os.FileMode) error {
+	return os.WriteFile(filename, []byte(chart.PageHTML()), perm)
+}
@@ -0,0 +1,162 @@
+// Package google compiles ChartIR to Google Charts. A compiled Chart
+// provides the `google.DataTable`, the options JSON and a standalone
+// HTML page, and implements the `charts/google` `Chart` interface.
+package google
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	gcharts "github.com/grokify/gocharts/v2/charts/google"
+)
+
+// Compiler converts ChartIR to Google Charts.
+type Compiler struct {
+	// Width is the chart width in pixels. Default: 900.
+	Width int
+
+	// Height is the chart height in pixels. Default: 500.
+	Height int
+
+	// ChartDiv is the HTML element ID for the chart. Default: "chart_div".
+	ChartDiv string
+}
+
+// NewCompiler creates a new compiler with default settings.
+func NewCompiler() *Compiler {
+	return &Compiler{
+		Width:  gcharts.DefaultWidth,
+		Height: gcharts.DefaultHeight,
+	}
+}
+
+// Compile validates the ChartIR with chartir.Validate and converts it to
+// a Google Chart. Cartesian geometries (bar, line, area, scatter) can be
+// combined and compile to a ComboChart when mixed. Pie, gauge, treemap and
+// sankey geometries require a single mark. Funnel, radar and heatmap
+// geometries have no Google Charts equivalent and return an error.
+func (c *Compiler) Compile(ir *chartir.ChartIR) (*Chart, error) {
+	if err := chartir.Validate(ir); err != nil {
+		return nil, err
+	}
+
+	geometry := ir.Marks[0].Geometry
+	for _, mark := range ir.Marks[1:] {
+		if !geometry.IsCartesian() || !mark.Geometry.IsCartesian() {
+			if mark.Geometry != geometry {
+				return nil, fmt.Errorf("chartir: cannot combine geometries: %s, %s", geometry, mark.Geometry)
+			}
+			return nil, fmt.Errorf("chartir: geometry %s supports a single mark", geometry)
+		}
+	}
+
+	var chart *Chart
+	var err error
+	switch geometry {
+	case chartir.GeometryBar, chartir.GeometryLine, chartir.GeometryArea, chartir.GeometryScatter:
+		chart, err = c.compileCartesianChart(ir)
+	case chartir.GeometryPie:
+		chart, err = c.compilePieChart(ir)
+	case chartir.GeometryGauge:
+		chart, err = c.compileGaugeChart(ir)
+	case chartir.GeometryTreemap:
+		chart, err = c.compileTreemapChart(ir)
+	case chartir.GeometrySankey:
+		chart, err = c.compileSankeyChart(ir)
+	default:
+		return nil, fmt.Errorf("chartir: geometry not supported by Google Charts: %s", geometry)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chart.Title = ir.Title
+	chart.ChartDiv = c.ChartDiv
+	return chart, nil
+}
+
+// DataTable returns the Google Charts DataTable for the ChartIR.
+func (c *Compiler) DataTable(ir *chartir.ChartIR) (gcharts.DataTable, error) {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return nil, err
+	}
+	return chart.DataTable, nil
+}
+
+// Options returns the Google Charts options for the ChartIR, suitable
+// for JSON encoding and passing to `chart.draw()`.
+func (c *Compiler) Options(ir *chartir.ChartIR) (map[string]any, error) {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return nil, err
+	}
+	return chart.Options, nil
+}
+
+// RenderHTML renders the ChartIR as a standalone HTML page.
+func (c *Compiler) RenderHTML(ir *chartir.ChartIR, w io.Writer) error {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return err
+	}
+	WriteChartPage(w, chart)
+	return nil
+}
+
+// WriteHTMLFile renders the ChartIR as a standalone HTML page and writes it to a file.
+func (c *Compiler) WriteHTMLFile(ir *chartir.ChartIR, filename string, perm os.FileMode) error {
+	chart, err := c.Compile(ir)
+	if err != nil {
+		return err
+	}
+	return chart.WriteFilePageHTML(filename, perm)
+}
+
+// options returns the options shared by all chart types.
+func (c *Compiler) options(ir *chartir.ChartIR) map[string]any {
+	o := map[string]any{}
+	if ir.Title != "" {
+		o["title"] = ir.Title
+	}
+	if c.Width > 0 {
+		o["width"] = c.Width
+	}
+	if c.Height > 0 {
+		o["height"] = c.Height
+	}
+	if ir.Legend != nil {
+		if !ir.Legend.Show {
+			o["legend"] = map[string]any{"position": "none"}
+		} else if ir.Legend.Position != "" {
+			o["legend"] = map[string]any{"position": string(ir.Legend.Position)}
+		}
+	}
+	if ir.Tooltip != nil {
+		if !ir.Tooltip.Show || ir.Tooltip.Trigger == chartir.TooltipTriggerNone {
+			o["tooltip"] = map[string]any{"trigger": "none"}
+		} else {
+			o["tooltip"] = map[string]any{"trigger": "focus"}
+		}
+	}
+	if ir.Grid != nil {
+		chartArea := map[string]any{}
+		for k, v := range map[string]string{
+			"left":   ir.Grid.Left,
+			"right":  ir.Grid.Right,
+			"top":    ir.Grid.Top,
+			"bottom": ir.Grid.Bottom,
+			"width":  ir.Grid.Width,
+			"height": ir.Grid.Height,
+		} {
+			if v != "" {
+				chartArea[k] = v
+			}
+		}
+		if len(chartArea) > 0 {
+			o["chartArea"] = chartArea
+		}
+	}
+	return o
+}
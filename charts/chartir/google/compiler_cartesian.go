@@ -0,0 +1,307 @@
+package google
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	gcharts "github.com/grokify/gocharts/v2/charts/google"
+)
+
+// cartesianMark holds the resolved values for one mark, keyed by
+// category label or x value.
+type cartesianMark struct {
+	mark   chartir.Mark
+	keys   []string
+	values map[string]float64
+}
+
+// compileCartesianChart compiles Cartesian marks to a single DataTable with
+// the domain in the first column and one column per mark. Domain values
+// missing from a mark are set to null. Google Charts stacks all bar and
+// area series or none, so marks must share a single stack group.
+func (c *Compiler) compileCartesianChart(ir *chartir.ChartIR) (*Chart, error) {
+	var cms []cartesianMark
+	horizontal := false
+	hasBar := false
+	stack := ""
+	numericDomain := true
+	var domainCol string
+	for i, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		catCol, valCol, horiz := mark.CartesianColumns(dataset)
+		if i == 0 {
+			horizontal = horiz
+			domainCol = catCol
+		} else if mark.Geometry == chartir.GeometryBar && horiz != horizontal {
+			return nil, fmt.Errorf("chartir: cannot combine horizontal and vertical bars")
+		}
+		if mark.Geometry == chartir.GeometryBar {
+			hasBar = true
+		}
+		if mark.Stack != "" {
+			if stack != "" && mark.Stack != stack {
+				return nil, fmt.Errorf("chartir: multiple stack groups are not supported: %s, %s", stack, mark.Stack)
+			}
+			stack = mark.Stack
+		}
+		if !dataset.ColumnIs(catCol, chartir.ColumnTypeNumber) {
+			numericDomain = false
+		}
+
+		cm := cartesianMark{mark: mark, values: map[string]float64{}}
+		labels := dataset.GetStringValues(catCol)
+		values := dataset.GetFloat64Values(valCol)
+		rawValues := dataset.GetStringValues(valCol)
+		for j, label := range labels {
+			if _, ok := cm.values[label]; !ok {
+				cm.keys = append(cm.keys, label)
+			}
+			if rawValues[j] != "" {
+				cm.values[label] = values[j]
+			}
+		}
+		cms = append(cms, cm)
+	}
+	if horizontal {
+		for _, mark := range ir.Marks {
+			if mark.Geometry != chartir.GeometryBar {
+				return nil, fmt.Errorf("chartir: cannot combine horizontal bars with geometry: %s", mark.Geometry)
+			}
+		}
+	}
+
+	// The domain axis is the y-axis for horizontal bars and the x-axis otherwise.
+	domainAxis := ir.GetXAxis()
+	if horizontal {
+		domainAxis = leftYAxis(ir)
+	}
+	if hasBar || (domainAxis != nil && (domainAxis.Type == chartir.AxisTypeCategory || domainAxis.Type == chartir.AxisTypeTime)) {
+		numericDomain = false
+	}
+	sortDomain := numericDomain || (domainAxis != nil && domainAxis.Type == chartir.AxisTypeTime)
+	domain := cartesianDomain(cms, sortDomain, numericDomain)
+
+	header := []any{domainCol}
+	if domainAxis != nil && domainAxis.Name != "" {
+		header[0] = domainAxis.Name
+	}
+	for _, cm := range cms {
+		header = append(header, cm.mark.DisplayName())
+	}
+	dt := gcharts.DataTable{header}
+	for _, key := range domain {
+		var row []any
+		if numericDomain {
+			f, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return nil, fmt.Errorf("chartir: non-numeric x value: %s", key)
+			}
+			row = append(row, f)
+		} else {
+			row = append(row, key)
+		}
+		for _, cm := range cms {
+			if v, ok := cm.values[key]; ok {
+				row = append(row, v)
+			} else {
+				row = append(row, nil)
+			}
+		}
+		dt = append(dt, row)
+	}
+
+	chartType, combo := cartesianChartType(ir.Marks, horizontal, numericDomain)
+	o := c.options(ir)
+	if stack != "" {
+		o["isStacked"] = true
+	}
+
+	domainAxisKey, valueAxisKey := "hAxis", "vAxis"
+	if horizontal {
+		domainAxisKey, valueAxisKey = "vAxis", "hAxis"
+	}
+	if domainAxis != nil {
+		if ao := axisOptions(domainAxis); len(ao) > 0 {
+			o[domainAxisKey] = ao
+		}
+	}
+	valueAxis := leftYAxis(ir)
+	if horizontal {
+		valueAxis = ir.GetXAxis()
+	}
+
+	series := map[string]any{}
+	var rightAxis *chartir.Axis
+	for i, mark := range ir.Marks {
+		so := seriesOptions(mark, combo || (chartType == TypeLineChart && mark.Geometry == chartir.GeometryScatter))
+		if axis := ir.GetMarkYAxis(mark); !horizontal && axis != nil && axis.Position == chartir.AxisPositionRight {
+			if rightAxis != nil && rightAxis.ID != axis.ID {
+				return nil, fmt.Errorf("chartir: multiple secondary y-axes are not supported: %s, %s", rightAxis.ID, axis.ID)
+			}
+			rightAxis = axis
+			so["targetAxisIndex"] = 1
+		}
+		if mark.Smooth || (mark.Style != nil && mark.Style.Smooth) {
+			o["curveType"] = "function"
+		}
+		if len(so) > 0 {
+			series[strconv.Itoa(i)] = so
+		}
+	}
+	if len(series) > 0 {
+		o["series"] = series
+	}
+	if combo {
+		o["seriesType"] = comboSeriesType(ir.Marks[0].Geometry)
+	}
+	if rightAxis != nil {
+		vAxes := map[string]any{"1": axisOptions(rightAxis)}
+		if valueAxis != nil {
+			vAxes["0"] = axisOptions(valueAxis)
+		}
+		o["vAxes"] = vAxes
+	} else if valueAxis != nil {
+		if ao := axisOptions(valueAxis); len(ao) > 0 {
+			o[valueAxisKey] = ao
+		}
+	}
+
+	return &Chart{
+		Package:   PackageCoreChart,
+		Type:      chartType,
+		DataTable: dt,
+		Options:   o,
+	}, nil
+}
+
+// cartesianDomain returns the union of domain keys across marks in order
+// of first appearance, or sorted if sortDomain is set.
+func cartesianDomain(cms []cartesianMark, sortDomain, numeric bool) []string {
+	var domain []string
+	seen := map[string]bool{}
+	for _, cm := range cms {
+		for _, key := range cm.keys {
+			if !seen[key] {
+				seen[key] = true
+				domain = append(domain, key)
+			}
+		}
+	}
+	if sortDomain {
+		if numeric {
+			sort.SliceStable(domain, func(i, j int) bool {
+				fi, _ := strconv.ParseFloat(domain[i], 64)
+				fj, _ := strconv.ParseFloat(domain[j], 64)
+				return fi < fj
+			})
+		} else {
+			sort.Strings(domain)
+		}
+	}
+	return domain
+}
+
+// cartesianChartType returns the visualization class for the marks and
+// whether it is a ComboChart. Scatter marks on a discrete domain are
+// drawn as points on a LineChart.
+func cartesianChartType(marks []chartir.Mark, horizontal, numericDomain bool) (string, bool) {
+	geometry := marks[0].Geometry
+	for _, mark := range marks[1:] {
+		if mark.Geometry != geometry {
+			return TypeComboChart, true
+		}
+	}
+	switch geometry {
+	case chartir.GeometryBar:
+		if horizontal {
+			return TypeBarChart, false
+		}
+		return TypeColumnChart, false
+	case chartir.GeometryArea:
+		return TypeAreaChart, false
+	case chartir.GeometryScatter:
+		if numericDomain {
+			return TypeScatterChart, false
+		}
+		return TypeLineChart, false
+	default:
+		return TypeLineChart, false
+	}
+}
+
+func comboSeriesType(g chartir.Geometry) string {
+	switch g {
+	case chartir.GeometryBar:
+		return "bars"
+	case chartir.GeometryArea:
+		return "area"
+	default:
+		return "line"
+	}
+}
+
+// seriesOptions returns the per-series options for a mark. If points is
+// set, scatter marks are drawn as a line series without a line.
+func seriesOptions(mark chartir.Mark, points bool) map[string]any {
+	so := map[string]any{}
+	if points {
+		so["type"] = comboSeriesType(mark.Geometry)
+		if mark.Geometry == chartir.GeometryScatter {
+			so["lineWidth"] = 0
+			so["pointSize"] = 7
+		}
+	}
+	s := mark.Style
+	if s == nil {
+		return so
+	}
+	if s.Color != "" {
+		so["color"] = s.Color
+	}
+	if s.LineWidth != nil {
+		so["lineWidth"] = *s.LineWidth
+	}
+	if s.SymbolSize != nil {
+		so["pointSize"] = *s.SymbolSize
+	}
+	if s.AreaOpacity != nil {
+		so["areaOpacity"] = *s.AreaOpacity
+	}
+	return so
+}
+
+func axisOptions(axis *chartir.Axis) map[string]any {
+	ao := map[string]any{}
+	if axis.Name != "" {
+		ao["title"] = axis.Name
+	}
+	if axis.Type == chartir.AxisTypeLog {
+		ao["logScale"] = true
+	}
+	viewWindow := map[string]any{}
+	if axis.Min != nil {
+		viewWindow["min"] = *axis.Min
+	}
+	if axis.Max != nil {
+		viewWindow["max"] = *axis.Max
+	}
+	if len(viewWindow) > 0 {
+		ao["viewWindow"] = viewWindow
+	}
+	return ao
+}
+
+// leftYAxis returns the first vertical axis that is not right-positioned.
+func leftYAxis(ir *chartir.ChartIR) *chartir.Axis {
+	for i := range ir.Axes {
+		if ir.Axes[i].IsVertical() && ir.Axes[i].Position != chartir.AxisPositionRight {
+			return &ir.Axes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package google
+
+import (
+	"fmt"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	gcharts "github.com/grokify/gocharts/v2/charts/google"
+	"github.com/grokify/mogo/type/stringsutil"
+)
+
+// nameValueDataTable returns a two column DataTable of names and values.
+func nameValueDataTable(ir *chartir.ChartIR, mark chartir.Mark) (gcharts.DataTable, error) {
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	nameCol, valueCol := mark.Encode.NameValueColumns()
+	names := dataset.GetStringValues(nameCol)
+	values := dataset.GetFloat64Values(valueCol)
+	dt := gcharts.DataTable{{nameCol, valueCol}}
+	for i := range names {
+		dt = append(dt, []any{names[i], values[i]})
+	}
+	return dt, nil
+}
+
+func (c *Compiler) compilePieChart(ir *chartir.ChartIR) (*Chart, error) {
+	mark := ir.Marks[0]
+	dt, err := nameValueDataTable(ir, mark)
+	if err != nil {
+		return nil, err
+	}
+	o := c.options(ir)
+	if mark.Style != nil && mark.Style.BorderColor != "" {
+		o["pieSliceBorderColor"] = mark.Style.BorderColor
+	}
+	return &Chart{
+		Package:   PackageCoreChart,
+		Type:      TypePieChart,
+		DataTable: dt,
+		Options:   o,
+	}, nil
+}
+
+// compileGaugeChart compiles a gauge mark to one dial per row. Style.GaugeMin
+// and Style.GaugeMax set the dial range.
+func (c *Compiler) compileGaugeChart(ir *chartir.ChartIR) (*Chart, error) {
+	mark := ir.Marks[0]
+	dt, err := nameValueDataTable(ir, mark)
+	if err != nil {
+		return nil, err
+	}
+	o := c.options(ir)
+	if s := mark.Style; s != nil {
+		if s.GaugeMin != nil {
+			o["min"] = *s.GaugeMin
+		}
+		if s.GaugeMax != nil {
+			o["max"] = *s.GaugeMax
+		}
+	}
+	return &Chart{
+		Package:   PackageGauge,
+		Type:      TypeGauge,
+		DataTable: dt,
+		Options:   o,
+	}, nil
+}
+
+// compileTreemapChart compiles a treemap mark. Google Charts requires a
+// single root node, so one is added using the chart title or mark name.
+// If Encode.Category is set, its values become parent nodes of the leaves.
+// Node names must be unique across roots, parents and leaves.
+func (c *Compiler) compileTreemapChart(ir *chartir.ChartIR) (*Chart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	nameCol := stringsutil.FirstNonEmpty(mark.Encode.Name, mark.Encode.Category)
+	root := stringsutil.FirstNonEmpty(ir.Title, mark.DisplayName())
+	names := dataset.GetStringValues(nameCol)
+	values := dataset.GetFloat64Values(mark.Encode.Value)
+	var parents []string
+	if mark.Encode.Name != "" && mark.Encode.Category != "" {
+		parents = dataset.GetStringValues(mark.Encode.Category)
+	}
+
+	dt := gcharts.DataTable{{nameCol, "Parent", mark.Encode.Value}, {root, nil, 0}}
+	seen := map[string]bool{root: true}
+	for i, name := range names {
+		parent := root
+		if parents != nil && parents[i] != "" {
+			parent = parents[i]
+			if !seen[parent] {
+				seen[parent] = true
+				dt = append(dt, []any{parent, root, 0})
+			}
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("chartir: duplicate treemap node name: %s", name)
+		}
+		seen[name] = true
+		dt = append(dt, []any{name, parent, values[i]})
+	}
+	return &Chart{
+		Package:   PackageTreemap,
+		Type:      TypeTreeMap,
+		DataTable: dt,
+		Options:   c.options(ir),
+	}, nil
+}
+
+// compileSankeyChart compiles a sankey mark of source, target and value
+// columns. Google Charts does not support cycles.
+func (c *Compiler) compileSankeyChart(ir *chartir.ChartIR) (*Chart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	sources := dataset.GetStringValues(mark.Encode.Source)
+	targets := dataset.GetStringValues(mark.Encode.Target)
+	values := dataset.GetFloat64Values(mark.Encode.Value)
+	dt := gcharts.DataTable{{mark.Encode.Source, mark.Encode.Target, mark.Encode.Value}}
+	for i := range sources {
+		if sources[i] == targets[i] {
+			return nil, fmt.Errorf("chartir: sankey link cannot target its source: %s", sources[i])
+		}
+		dt = append(dt, []any{sources[i], targets[i], values[i]})
+	}
+	return &Chart{
+		Package:   PackageSankey,
+		Type:      TypeSankey,
+		DataTable: dt,
+		Options:   c.options(ir),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package google
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+func comboChart() *chartir.ChartIR {
+	return &chartir.ChartIR{
+		Title: "Revenue and Growth",
+		Datasets: []chartir.Dataset{
+			{
+				ID: "data",
+				Columns: []chartir.Column{
+					{Name: "quarter", Type: chartir.ColumnTypeString},
+					{Name: "revenue", Type: chartir.ColumnTypeNumber},
+					{Name: "growth", Type: chartir.ColumnTypeNumber},
+				},
+				Rows: [][]string{
+					{"Q1", "100", "5"},
+					{"Q2", "120", ""},
+				},
+			},
+		},
+		Marks: []chartir.Mark{
+			{ID: "revenue", DatasetID: "data", Geometry: chartir.GeometryBar, Encode: chartir.Encode{X: "quarter", Y: "revenue"}, Name: "Revenue"},
+			{ID: "growth", DatasetID: "data", Geometry: chartir.GeometryLine, Encode: chartir.Encode{X: "quarter", Y: "growth"}, Name: "Growth", YAxisID: "y2"},
+		},
+		Axes: []chartir.Axis{
+			{ID: "x", Type: chartir.AxisTypeCategory, Position: chartir.AxisPositionBottom},
+			{ID: "y", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionLeft, Name: "USD"},
+			{ID: "y2", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionRight, Name: "%"},
+		},
+	}
+}
+
+func TestCompileComboChart(t *testing.T) {
+	chart, err := NewCompiler().Compile(comboChart())
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	if chart.Type != TypeComboChart || chart.Options["seriesType"] != "bars" {
+		t.Errorf("Compile() chart type mismatch: got [%s] seriesType [%v]", chart.Type, chart.Options["seriesType"])
+	}
+	wantDataTable := `[["quarter","Revenue","Growth"],["Q1",100,5],["Q2",120,null]]`
+	if got := string(chart.DataTableJSON()); got != wantDataTable {
+		t.Errorf("DataTableJSON() mismatch: want [%s], got [%s]", wantDataTable, got)
+	}
+	series, ok := chart.Options["series"].(map[string]any)
+	if !ok {
+		t.Fatalf("Compile() series options missing")
+	}
+	if so, ok := series["1"].(map[string]any); !ok || so["type"] != "line" || so["targetAxisIndex"] != 1 {
+		t.Errorf("Compile() series 1 options mismatch: got [%v]", series["1"])
+	}
+	if _, ok := chart.Options["vAxes"]; !ok {
+		t.Error("Compile() vAxes options missing for secondary axis")
+	}
+
+	var buf bytes.Buffer
+	if err := NewCompiler().RenderHTML(comboChart(), &buf); err != nil {
+		t.Fatalf("RenderHTML() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "new google.visualization.ComboChart(") {
+		t.Error("RenderHTML() output missing ComboChart constructor")
+	}
+}
+
+func TestCompileUnsupportedGeometry(t *testing.T) {
+	ir := comboChart()
+	ir.Marks = []chartir.Mark{
+		{ID: "funnel", DatasetID: "data", Geometry: chartir.GeometryFunnel, Encode: chartir.Encode{Name: "quarter", Value: "revenue"}},
+	}
+	ir.Axes = nil
+	if _, err := NewCompiler().Compile(ir); err == nil {
+		t.Error("Compile() expected error for funnel geometry")
+	}
+}
@@ -1,5 +1,7 @@
 package chartir
 
+import "github.com/grokify/mogo/type/stringsutil"
+
 // Geometry defines the visual representation type.
 // This replaces polymorphic series.type with a simple enum.
 type Geometry string
@@ -35,6 +37,17 @@ func Geometries() []Geometry {
 	}
 }
 
+// IsCartesian returns true for geometries drawn on x and y axes, which
+// can be combined in a single chart.
+func (g Geometry) IsCartesian() bool {
+	switch g {
+	case GeometryBar, GeometryLine, GeometryArea, GeometryScatter:
+		return true
+	default:
+		return false
+	}
+}
+
 // CoordinateSystem defines the coordinate system type.
 type CoordinateSystem string
 
@@ -85,6 +98,11 @@ type Mark struct {
 
 	// Name is the display name for this mark in legends/tooltips.
 	Name string `json:"name,omitempty"`
+
+	// YAxisID references the vertical Axis this mark is plotted against
+	// for Cartesian geometries. If empty, the first vertical axis is used.
+	// Referencing a right-positioned axis places the mark on a secondary y-axis.
+	YAxisID string `json:"yAxisId,omitempty"`
 }
 
 // Encode maps data columns to visual channels.
@@ -123,3 +141,40 @@ type Encode struct {
 	// Heat maps to heat intensity (for heatmaps).
 	Heat string `json:"heat,omitempty"`
 }
+
+// DisplayName returns the display name for the mark in legends and
+// tooltips, falling back to its ID.
+func (m Mark) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.ID
+}
+
+// CartesianColumns returns the category (or x) column and the value (or y)
+// column for a Cartesian mark. horizontal is true when the category is on
+// the y-axis, which only applies to bars encoding a value with y, or a
+// number x column with a non-number y column.
+func (m Mark) CartesianColumns(dataset *Dataset) (catCol, valCol string, horizontal bool) {
+	enc := m.Encode
+	if m.Geometry == GeometryBar {
+		if enc.Value != "" {
+			if enc.X != "" {
+				return enc.X, enc.Value, false
+			}
+			return enc.Y, enc.Value, true
+		}
+		if enc.X != "" && enc.Y != "" &&
+			dataset.ColumnIs(enc.X, ColumnTypeNumber) &&
+			!dataset.ColumnIs(enc.Y, ColumnTypeNumber) {
+			return enc.Y, enc.X, true
+		}
+	}
+	return enc.X, stringsutil.FirstNonEmpty(enc.Y, enc.Value), false
+}
+
+// NameValueColumns returns the name and value columns for geometries with
+// one value per name, such as pie, funnel and gauge.
+func (e Encode) NameValueColumns() (nameCol, valueCol string) {
+	return stringsutil.FirstNonEmpty(e.Name, e.Category, e.X), stringsutil.FirstNonEmpty(e.Value, e.Y)
+}
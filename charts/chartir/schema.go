@@ -0,0 +1,145 @@
+package chartir
+
+//go:generate go run ./cmd/write_schema chartir.schema.json
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	// SchemaDraft is the JSON Schema dialect used by JSONSchema.
+	SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	// SchemaID is the `$id` of the ChartIR JSON Schema.
+	SchemaID = "https://github.com/grokify/gocharts/v2/charts/chartir/chartir.schema.json"
+)
+
+// JSONSchema returns the JSON Schema (draft 2020-12) for ChartIR. The schema
+// is generated from the Go type definitions and their `json` tags so it stays
+// in sync with the IR. Enum types use the values from AxisTypes(), Geometries(),
+// etc. Fields without `omitempty` are marked as required.
+func JSONSchema() map[string]any {
+	g := schemaGenerator{defs: map[string]any{}}
+	root := g.structSchema(reflect.TypeFor[ChartIR]())
+	root["$schema"] = SchemaDraft
+	root["$id"] = SchemaID
+	root["title"] = "ChartIR"
+	root["$defs"] = g.defs
+	return root
+}
+
+// JSONSchemaBytes returns the indented JSON encoding of JSONSchema.
+func JSONSchemaBytes() ([]byte, error) {
+	return json.MarshalIndent(JSONSchema(), "", "  ")
+}
+
+// schemaEnums maps enum types to their valid values.
+func schemaEnums() map[reflect.Type][]string {
+	return map[reflect.Type][]string{
+		reflect.TypeFor[AxisType]():         enumStrings(AxisTypes()),
+		reflect.TypeFor[AxisPosition]():     enumStrings(AxisPositions()),
+		reflect.TypeFor[ColumnType]():       enumStrings(ColumnTypes()),
+		reflect.TypeFor[CoordinateSystem](): enumStrings(CoordinateSystems()),
+		reflect.TypeFor[Geometry]():         enumStrings(Geometries()),
+		reflect.TypeFor[LegendPosition]():   enumStrings(LegendPositions()),
+		reflect.TypeFor[TooltipTrigger]():   enumStrings(TooltipTriggers()),
+	}
+}
+
+func enumStrings[S ~string](vals []S) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// structSchema returns an object schema for a struct type.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, omitempty := jsonFieldName(f)
+		if name == "-" {
+			continue
+		}
+		props[name] = g.typeSchema(f.Type)
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+	s := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// typeSchema returns the schema for a field type, registering struct
+// types in `$defs` and returning a `$ref` to them.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]any {
+	if vals, ok := schemaEnums()[t]; ok {
+		return map[string]any{"type": "string", "enum": vals}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Interface:
+		// Used for fields such as Style.BarWidth which accept
+		// either a pixel number or a percentage string.
+		return map[string]any{"type": []string{"number", "string"}}
+	default:
+		return map[string]any{}
+	}
+}
+
+// jsonFieldName returns the JSON property name for a struct field and
+// whether it is tagged `omitempty`.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = f.Name
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
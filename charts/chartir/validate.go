@@ -0,0 +1,279 @@
+package chartir
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a single validation failure. Path is a JSON
+// Pointer (RFC 6901) to the offending value in the ChartIR JSON document.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors is a list of validation failures.
+type ValidationErrors []ValidationError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Error()
+	}
+	return "chartir: invalid chart: " + strings.Join(msgs, "; ")
+}
+
+// GeometryCoordinateSystems returns the coordinate systems a geometry can be
+// drawn in. An empty CoordinateSystem on a Mark is always valid and means
+// the geometry's default.
+func GeometryCoordinateSystems(g Geometry) []CoordinateSystem {
+	switch g {
+	case GeometryLine, GeometryBar, GeometryScatter, GeometryArea:
+		return []CoordinateSystem{CoordinateCartesian2D, CoordinatePolar}
+	case GeometryHeatmap:
+		return []CoordinateSystem{CoordinateCartesian2D}
+	case GeometryPie, GeometryGauge, GeometryRadar:
+		return []CoordinateSystem{CoordinateRadial}
+	default:
+		return []CoordinateSystem{}
+	}
+}
+
+// Validate checks the ChartIR for structural and referential errors that
+// the JSON Schema cannot express, such as unknown dataset IDs, encode
+// columns missing from a dataset, non-numeric values in number columns,
+// and geometry/coordinate system mismatches. It returns nil or a
+// ValidationErrors value listing every failure found.
+func Validate(ir *ChartIR) error {
+	if ir == nil {
+		return ValidationErrors{{Path: "", Message: "chart cannot be nil"}}
+	}
+	v := validator{}
+	v.validateDatasets(ir.Datasets)
+	v.validateMarks(ir)
+	v.validateAxes(ir.Axes)
+	if ir.Legend != nil && ir.Legend.Position != "" && !slices.Contains(LegendPositions(), ir.Legend.Position) {
+		v.addf("/legend/position", "invalid legend position: %q", ir.Legend.Position)
+	}
+	if ir.Tooltip != nil && ir.Tooltip.Trigger != "" && !slices.Contains(TooltipTriggers(), ir.Tooltip.Trigger) {
+		v.addf("/tooltip/trigger", "invalid tooltip trigger: %q", ir.Tooltip.Trigger)
+	}
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) addf(path, format string, a ...any) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+func (v *validator) validateDatasets(datasets []Dataset) {
+	if len(datasets) == 0 {
+		v.addf("/datasets", "at least one dataset is required")
+	}
+	ids := map[string]bool{}
+	for i, ds := range datasets {
+		path := fmt.Sprintf("/datasets/%d", i)
+		if ds.ID == "" {
+			v.addf(path+"/id", "dataset id is required")
+		} else if ids[ds.ID] {
+			v.addf(path+"/id", "duplicate dataset id: %q", ds.ID)
+		}
+		ids[ds.ID] = true
+
+		names := map[string]bool{}
+		for j, col := range ds.Columns {
+			colPath := fmt.Sprintf("%s/columns/%d", path, j)
+			if col.Name == "" {
+				v.addf(colPath+"/name", "column name is required")
+			} else if names[col.Name] {
+				v.addf(colPath+"/name", "duplicate column name: %q", col.Name)
+			}
+			names[col.Name] = true
+			if !slices.Contains(ColumnTypes(), col.Type) {
+				v.addf(colPath+"/type", "invalid column type: %q", col.Type)
+			}
+		}
+
+		for j, row := range ds.Rows {
+			rowPath := fmt.Sprintf("%s/rows/%d", path, j)
+			if len(row) != len(ds.Columns) {
+				v.addf(rowPath, "row has %d values, expected %d", len(row), len(ds.Columns))
+			}
+			for k, val := range row {
+				if k >= len(ds.Columns) || ds.Columns[k].Type != ColumnTypeNumber || val == "" {
+					continue
+				}
+				if _, err := strconv.ParseFloat(val, 64); err != nil {
+					v.addf(fmt.Sprintf("%s/%d", rowPath, k), "non-numeric value %q in number column %q", val, ds.Columns[k].Name)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateMarks(ir *ChartIR) {
+	if len(ir.Marks) == 0 {
+		v.addf("/marks", "at least one mark is required")
+	}
+	ids := map[string]bool{}
+	for i, mark := range ir.Marks {
+		path := fmt.Sprintf("/marks/%d", i)
+		if mark.ID == "" {
+			v.addf(path+"/id", "mark id is required")
+		} else if ids[mark.ID] {
+			v.addf(path+"/id", "duplicate mark id: %q", mark.ID)
+		}
+		ids[mark.ID] = true
+
+		geometryOK := slices.Contains(Geometries(), mark.Geometry)
+		if !geometryOK {
+			v.addf(path+"/geometry", "invalid geometry: %q", mark.Geometry)
+		}
+		if mark.CoordinateSystem != "" {
+			if !slices.Contains(CoordinateSystems(), mark.CoordinateSystem) {
+				v.addf(path+"/coordinateSystem", "invalid coordinate system: %q", mark.CoordinateSystem)
+			} else if geometryOK && !slices.Contains(GeometryCoordinateSystems(mark.Geometry), mark.CoordinateSystem) {
+				v.addf(path+"/coordinateSystem", "geometry %q does not support coordinate system %q", mark.Geometry, mark.CoordinateSystem)
+			}
+		}
+
+		if mark.YAxisID != "" {
+			if axis := ir.GetAxis(mark.YAxisID); axis == nil {
+				v.addf(path+"/yAxisId", "unknown axis id: %q", mark.YAxisID)
+			} else if !axis.IsVertical() {
+				v.addf(path+"/yAxisId", "axis %q is not a vertical axis", mark.YAxisID)
+			}
+		}
+
+		ds := ir.GetDataset(mark.DatasetID)
+		if ds == nil {
+			v.addf(path+"/datasetId", "unknown dataset id: %q", mark.DatasetID)
+			continue
+		}
+		v.validateEncode(path+"/encode", mark.Encode, ds)
+		if geometryOK {
+			v.validateEncodeRequired(path+"/encode", mark.Geometry, mark.Encode)
+		}
+	}
+}
+
+// validateEncode checks that every encoded column exists in the dataset
+// and that value channels reference number columns.
+func (v *validator) validateEncode(path string, enc Encode, ds *Dataset) {
+	for _, ch := range encodeChannels(enc) {
+		if ch.column == "" {
+			continue
+		}
+		col := ds.GetColumn(ch.column)
+		if col == nil {
+			v.addf(path+"/"+ch.name, "column %q not found in dataset %q", ch.column, ds.ID)
+		} else if ch.numeric && col.Type != ColumnTypeNumber {
+			v.addf(path+"/"+ch.name, "column %q must be of type %q", ch.column, ColumnTypeNumber)
+		}
+	}
+}
+
+// validateEncodeRequired checks that the channels needed to draw the
+// geometry are set.
+func (v *validator) validateEncodeRequired(path string, g Geometry, enc Encode) {
+	require := func(name string, cols ...string) {
+		for _, col := range cols {
+			if col != "" {
+				return
+			}
+		}
+		v.addf(path, "geometry %q requires encode %s", g, name)
+	}
+	switch g {
+	case GeometryLine, GeometryArea, GeometryScatter:
+		require("x", enc.X)
+		require("y", enc.Y)
+	case GeometryBar:
+		n := 0
+		for _, col := range []string{enc.X, enc.Y, enc.Value} {
+			if col != "" {
+				n++
+			}
+		}
+		if n < 2 {
+			v.addf(path, "geometry %q requires two of encode x, y and value", g)
+		}
+	case GeometryPie, GeometryFunnel:
+		require("name or category", enc.Name, enc.Category, enc.X)
+		require("value", enc.Value, enc.Y)
+	case GeometryGauge:
+		require("value", enc.Value, enc.Y)
+	case GeometryRadar:
+		require("indicator", enc.Indicator, enc.Name, enc.Category)
+		require("value", enc.Value, enc.Y)
+	case GeometryHeatmap:
+		require("x", enc.X)
+		require("y", enc.Y)
+		require("heat", enc.Heat, enc.Value)
+	case GeometryTreemap:
+		require("name or category", enc.Name, enc.Category)
+		require("value", enc.Value)
+	case GeometrySankey:
+		require("source", enc.Source)
+		require("target", enc.Target)
+		require("value", enc.Value)
+	}
+}
+
+type encodeChannel struct {
+	name    string
+	column  string
+	numeric bool
+}
+
+func encodeChannels(enc Encode) []encodeChannel {
+	return []encodeChannel{
+		{name: "x", column: enc.X},
+		{name: "y", column: enc.Y},
+		{name: "value", column: enc.Value, numeric: true},
+		{name: "name", column: enc.Name},
+		{name: "size", column: enc.Size, numeric: true},
+		{name: "color", column: enc.Color},
+		{name: "category", column: enc.Category},
+		{name: "indicator", column: enc.Indicator},
+		{name: "source", column: enc.Source},
+		{name: "target", column: enc.Target},
+		{name: "heat", column: enc.Heat, numeric: true},
+	}
+}
+
+func (v *validator) validateAxes(axes []Axis) {
+	ids := map[string]bool{}
+	for i, axis := range axes {
+		path := fmt.Sprintf("/axes/%d", i)
+		if axis.ID == "" {
+			v.addf(path+"/id", "axis id is required")
+		} else if ids[axis.ID] {
+			v.addf(path+"/id", "duplicate axis id: %q", axis.ID)
+		}
+		ids[axis.ID] = true
+		if !slices.Contains(AxisTypes(), axis.Type) {
+			v.addf(path+"/type", "invalid axis type: %q", axis.Type)
+		}
+		if !slices.Contains(AxisPositions(), axis.Position) {
+			v.addf(path+"/position", "invalid axis position: %q", axis.Position)
+		}
+		if axis.Min != nil && axis.Max != nil && *axis.Min > *axis.Max {
+			v.addf(path+"/min", "axis min %v is greater than max %v", *axis.Min, *axis.Max)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package chartir
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func validChart() *ChartIR {
+	return &ChartIR{
+		Datasets: []Dataset{
+			{
+				ID: "data",
+				Columns: []Column{
+					{Name: "label", Type: ColumnTypeString},
+					{Name: "value", Type: ColumnTypeNumber},
+				},
+				Rows: [][]string{
+					{"A", "10"},
+					{"B", "20"},
+				},
+			},
+		},
+		Marks: []Mark{
+			{
+				ID:        "bars",
+				DatasetID: "data",
+				Geometry:  GeometryBar,
+				Encode:    Encode{X: "label", Y: "value"},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(validChart()); err != nil {
+		t.Fatalf("Validate() valid chart error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ir *ChartIR)
+		path   string
+	}{
+		{"unknown dataset", func(ir *ChartIR) { ir.Marks[0].DatasetID = "nope" }, "/marks/0/datasetId"},
+		{"missing column", func(ir *ChartIR) { ir.Marks[0].Encode.Y = "nope" }, "/marks/0/encode/y"},
+		{"non-numeric value", func(ir *ChartIR) { ir.Datasets[0].Rows[1][1] = "n/a" }, "/datasets/0/rows/1/1"},
+		{"coordinate mismatch", func(ir *ChartIR) { ir.Marks[0].CoordinateSystem = CoordinateRadial }, "/marks/0/coordinateSystem"},
+		{"invalid geometry", func(ir *ChartIR) { ir.Marks[0].Geometry = "donut" }, "/marks/0/geometry"},
+		{"missing encode", func(ir *ChartIR) { ir.Marks[0].Encode = Encode{X: "label"} }, "/marks/0/encode"},
+	}
+
+	for _, tt := range tests {
+		ir := validChart()
+		tt.modify(ir)
+		err := Validate(ir)
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Errorf("Validate(%s) expected ValidationErrors, got: %v", tt.name, err)
+			continue
+		}
+		if len(verrs) != 1 || verrs[0].Path != tt.path {
+			t.Errorf("Validate(%s) mismatch: want path [%s], got [%v]", tt.name, tt.path, verrs)
+		}
+	}
+}
+
+func TestJSONSchemaFile(t *testing.T) {
+	b, err := JSONSchemaBytes()
+	if err != nil {
+		t.Fatalf("JSONSchemaBytes() error: %v", err)
+	}
+	file, err := os.ReadFile("chartir.schema.json")
+	if err != nil {
+		t.Fatalf("os.ReadFile() error: %v", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(file), b) {
+		t.Error("chartir.schema.json is out of date, run `go generate`")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/go-analyze/charts/chartdraw"
 	"github.com/go-analyze/charts/chartdraw/drawing"
@@ -56,10 +57,21 @@ func (c *Compiler) RenderSVG(ir *chartir.ChartIR, w io.Writer) error {
 	return chart.Render(chartdraw.SVG, w)
 }
 
-// Compile converts ChartIR to a ChartType.
+// Compile validates the ChartIR with chartir.Validate and converts it to a ChartType.
+// Combinations of Cartesian marks, stacked marks, areas and marks on a
+// secondary y-axis are compiled to an OptionChart. Heatmap, funnel, radar,
+// treemap, sankey and gauge marks are compiled to a PainterChart. Geometry
+// combinations that cannot be drawn together return an error.
 func (c *Compiler) Compile(ir *chartir.ChartIR) (ChartType, error) {
-	if len(ir.Marks) == 0 {
-		return nil, fmt.Errorf("chartir: no marks defined")
+	if err := chartir.Validate(ir); err != nil {
+		return nil, err
+	}
+
+	if err := checkGeometries(ir); err != nil {
+		return nil, err
+	}
+	if isComboChart(ir) {
+		return c.compileComboChart(ir)
 	}
 
 	// Determine chart type from first mark's geometry
@@ -68,12 +80,24 @@ func (c *Compiler) Compile(ir *chartir.ChartIR) (ChartType, error) {
 	switch geometry {
 	case chartir.GeometryBar:
 		return c.compileBarChart(ir)
-	case chartir.GeometryLine, chartir.GeometryArea:
+	case chartir.GeometryLine:
 		return c.compileLineChart(ir)
 	case chartir.GeometryScatter:
 		return c.compileScatterChart(ir)
 	case chartir.GeometryPie:
 		return c.compilePieChart(ir)
+	case chartir.GeometryHeatmap:
+		return c.compileHeatmapChart(ir)
+	case chartir.GeometryFunnel:
+		return c.compileFunnelChart(ir)
+	case chartir.GeometryRadar:
+		return c.compileRadarChart(ir)
+	case chartir.GeometryTreemap:
+		return c.compileTreemapChart(ir)
+	case chartir.GeometrySankey:
+		return c.compileSankeyChart(ir)
+	case chartir.GeometryGauge:
+		return c.compileGaugeChart(ir)
 	default:
 		return nil, fmt.Errorf("chartir: unsupported geometry: %s", geometry)
 	}
@@ -81,25 +105,14 @@ func (c *Compiler) Compile(ir *chartir.ChartIR) (ChartType, error) {
 
 func (c *Compiler) compileBarChart(ir *chartir.ChartIR) (*chartdraw.BarChart, error) {
 	mark := ir.Marks[0]
-	dataset := ir.GetDataset(mark.DatasetID)
-	if dataset == nil {
-		return nil, fmt.Errorf("chartir: dataset not found: %s", mark.DatasetID)
-	}
-
-	// Get label and value columns
-	labelCol := mark.Encode.Y // For horizontal bar charts
-	if labelCol == "" {
-		labelCol = mark.Encode.X
-	}
-	valueCol := mark.Encode.X
-	if mark.Encode.Y != "" && mark.Encode.X != "" {
-		valueCol = mark.Encode.X
-		labelCol = mark.Encode.Y
-	}
-	if mark.Encode.Value != "" {
-		valueCol = mark.Encode.Value
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
 	}
 
+	// chartdraw draws vertical bars, so the categories of horizontal bars
+	// are also drawn on the x-axis.
+	labelCol, valueCol, _ := mark.CartesianColumns(dataset)
 	labels := dataset.GetStringValues(labelCol)
 	values := dataset.GetFloat64Values(valueCol)
 
@@ -137,6 +150,9 @@ func (c *Compiler) compileLineChart(ir *chartir.ChartIR) (*chartdraw.Chart, erro
 		ColorPalette: c.ColorPalette,
 	}
 
+	xAxis := ir.GetXAxis()
+	isTimeAxis := xAxis != nil && xAxis.Type == chartir.AxisTypeTime
+
 	// Add series for each mark
 	for _, mark := range ir.Marks {
 		dataset := ir.GetDataset(mark.DatasetID)
@@ -144,33 +160,46 @@ func (c *Compiler) compileLineChart(ir *chartir.ChartIR) (*chartdraw.Chart, erro
 			return nil, fmt.Errorf("chartir: dataset not found: %s", mark.DatasetID)
 		}
 
-		xValues := dataset.GetFloat64Values(mark.Encode.X)
 		yValues := dataset.GetFloat64Values(mark.Encode.Y)
+		var style chartdraw.Style
+		if mark.Style != nil && mark.Style.Color != "" {
+			style.StrokeColor = colorFromHex(mark.Style.Color)
+		}
 
+		if isTimeAxis {
+			xValues, err := parseTimeValues(dataset.GetStringValues(mark.Encode.X))
+			if err != nil {
+				return nil, err
+			}
+			chart.Series = append(chart.Series, chartdraw.TimeSeries{
+				Name:    mark.Name,
+				Style:   style,
+				XValues: xValues,
+				YValues: yValues,
+			})
+			continue
+		}
+
+		xValues := dataset.GetFloat64Values(mark.Encode.X)
 		if len(xValues) != len(yValues) {
 			return nil, fmt.Errorf("chartir: x/y value count mismatch")
 		}
-
-		series := chartdraw.ContinuousSeries{
+		chart.Series = append(chart.Series, chartdraw.ContinuousSeries{
 			Name:    mark.Name,
+			Style:   style,
 			XValues: xValues,
 			YValues: yValues,
-		}
-
-		if mark.Style != nil && mark.Style.Color != "" {
-			series.Style = chartdraw.Style{
-				StrokeColor: colorFromHex(mark.Style.Color),
-			}
-		}
-
-		chart.Series = append(chart.Series, series)
+		})
 	}
 
 	// Configure axes
-	if xAxis := ir.GetXAxis(); xAxis != nil {
+	if xAxis != nil {
 		chart.XAxis = chartdraw.XAxis{
 			Name: xAxis.Name,
 		}
+		if isTimeAxis {
+			chart.XAxis.ValueFormatter = chartdraw.TimeDateValueFormatter
+		}
 	}
 	if yAxis := ir.GetYAxis(); yAxis != nil {
 		chart.YAxis = chartdraw.YAxis{
@@ -181,6 +210,22 @@ func (c *Compiler) compileLineChart(ir *chartir.ChartIR) (*chartdraw.Chart, erro
 	return chart, nil
 }
 
+// parseTimeValues parses time axis values formatted as RFC 3339
+// date-times or dates.
+func parseTimeValues(values []string) ([]time.Time, error) {
+	times := make([]time.Time, len(values))
+	for i, v := range values {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, v); err != nil {
+				return nil, fmt.Errorf("chartir: invalid time value: %s", v)
+			}
+		}
+		times[i] = t
+	}
+	return times, nil
+}
+
 func (c *Compiler) compileScatterChart(ir *chartir.ChartIR) (*chartdraw.Chart, error) {
 	chart := &chartdraw.Chart{
 		Title:        ir.Title,
@@ -251,17 +296,12 @@ func colorFromHex(hex string) drawing.Color {
 
 func (c *Compiler) compilePieChart(ir *chartir.ChartIR) (*chartdraw.PieChart, error) {
 	mark := ir.Marks[0]
-	dataset := ir.GetDataset(mark.DatasetID)
-	if dataset == nil {
-		return nil, fmt.Errorf("chartir: dataset not found: %s", mark.DatasetID)
-	}
-
-	nameCol := mark.Encode.Name
-	if nameCol == "" {
-		nameCol = mark.Encode.Category
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
 	}
-	valueCol := mark.Encode.Value
 
+	nameCol, valueCol := mark.Encode.NameValueColumns()
 	names := dataset.GetStringValues(nameCol)
 	values := dataset.GetFloat64Values(valueCol)
 
@@ -0,0 +1,245 @@
+package wchart
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/go-analyze/charts"
+	"github.com/go-analyze/charts/chartdraw"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+// OptionChart adapts a go-analyze/charts ChartOption to ChartType. It is
+// used for charts that chartdraw cannot draw directly, such as bar and
+// line combinations, stacked series and secondary y-axes.
+type OptionChart struct {
+	Option charts.ChartOption
+}
+
+// Render renders the chart. The output format is selected by matching rp
+// against chartdraw.PNG, chartdraw.SVG and chartdraw.JPG, defaulting to PNG.
+func (oc *OptionChart) Render(rp chartdraw.RendererProvider, w io.Writer) error {
+	opt := oc.Option
+	opt.OutputFormat = outputFormat(rp)
+	p, err := charts.Render(opt)
+	if err != nil {
+		return err
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// outputFormat maps a chartdraw renderer provider to a go-analyze/charts
+// output format. Providers are functions which cannot be compared with
+// `==`, so their code pointers are compared instead.
+func outputFormat(rp chartdraw.RendererProvider) string {
+	switch reflect.ValueOf(rp).Pointer() {
+	case reflect.ValueOf(chartdraw.SVG).Pointer():
+		return charts.ChartOutputSVG
+	case reflect.ValueOf(chartdraw.JPG).Pointer():
+		return charts.ChartOutputJPG
+	default:
+		return charts.ChartOutputPNG
+	}
+}
+
+// checkGeometries returns an error for mark combinations that cannot be
+// drawn together. Only Cartesian geometries can be combined, except that
+// radar marks are drawn as multiple series.
+func checkGeometries(ir *chartir.ChartIR) error {
+	geometry := ir.Marks[0].Geometry
+	for _, mark := range ir.Marks[1:] {
+		if geometry == chartir.GeometryRadar && mark.Geometry == chartir.GeometryRadar {
+			continue
+		}
+		if !geometry.IsCartesian() || !mark.Geometry.IsCartesian() {
+			if mark.Geometry != geometry {
+				return fmt.Errorf("chartir: cannot combine geometries: %s, %s", geometry, mark.Geometry)
+			}
+			return fmt.Errorf("chartir: geometry %s supports a single mark", geometry)
+		}
+	}
+	return nil
+}
+
+// isComboChart returns true if the Cartesian marks require the
+// go-analyze/charts renderer: mixed geometries, multiple bar marks,
+// areas, stacking or a secondary y-axis.
+func isComboChart(ir *chartir.ChartIR) bool {
+	bars := 0
+	for _, mark := range ir.Marks {
+		if !mark.Geometry.IsCartesian() {
+			return false
+		}
+		if mark.Geometry != ir.Marks[0].Geometry ||
+			mark.Geometry == chartir.GeometryArea ||
+			mark.Stack != "" ||
+			isSecondaryYAxis(ir, mark) {
+			return true
+		}
+		if mark.Geometry == chartir.GeometryBar {
+			bars++
+		}
+	}
+	return bars > 1
+}
+
+func isSecondaryYAxis(ir *chartir.ChartIR, mark chartir.Mark) bool {
+	axis := ir.GetMarkYAxis(mark)
+	return axis != nil && axis.Position == chartir.AxisPositionRight
+}
+
+// compileComboChart compiles Cartesian marks on a shared category x-axis.
+// go-analyze/charts stacks all series or none and fills all lines or none,
+// so marks must share a single stack group and cannot mix lines and areas.
+// Marks referencing a right-positioned axis are drawn on a secondary y-axis.
+func (c *Compiler) compileComboChart(ir *chartir.ChartIR) (*OptionChart, error) {
+	stack := ""
+	hasLine, hasArea := false, false
+	var rightAxis *chartir.Axis
+	for _, mark := range ir.Marks {
+		if mark.Stack != "" {
+			if stack != "" && mark.Stack != stack {
+				return nil, fmt.Errorf("chartir: multiple stack groups are not supported: %s, %s", stack, mark.Stack)
+			}
+			stack = mark.Stack
+		}
+		switch mark.Geometry {
+		case chartir.GeometryLine:
+			hasLine = true
+		case chartir.GeometryArea:
+			hasArea = true
+		}
+		if isSecondaryYAxis(ir, mark) {
+			axis := ir.GetMarkYAxis(mark)
+			if rightAxis != nil && rightAxis.ID != axis.ID {
+				return nil, fmt.Errorf("chartir: multiple secondary y-axes are not supported: %s, %s", rightAxis.ID, axis.ID)
+			}
+			rightAxis = axis
+		}
+	}
+	if hasLine && hasArea {
+		return nil, fmt.Errorf("chartir: cannot combine geometries: %s, %s", chartir.GeometryLine, chartir.GeometryArea)
+	}
+	if stack != "" {
+		for _, mark := range ir.Marks {
+			if mark.Stack != stack && mark.Geometry != chartir.GeometryScatter {
+				return nil, fmt.Errorf("chartir: mark %s must be in stack group %s as stacking applies to all series", mark.ID, stack)
+			}
+		}
+	}
+
+	// Build the union of category labels across marks, in order of first appearance.
+	var categories []string
+	catIndex := map[string]int{}
+	type markValues struct {
+		labels []string
+		values []float64
+	}
+	var mvs []markValues
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		catCol, valCol, _ := mark.CartesianColumns(dataset)
+		mv := markValues{
+			labels: dataset.GetStringValues(catCol),
+			values: dataset.GetFloat64Values(valCol),
+		}
+		if len(mv.labels) != len(mv.values) {
+			return nil, fmt.Errorf("chartir: label/value count mismatch")
+		}
+		for _, label := range mv.labels {
+			if _, ok := catIndex[label]; !ok {
+				catIndex[label] = len(categories)
+				categories = append(categories, label)
+			}
+		}
+		mvs = append(mvs, mv)
+	}
+
+	var seriesList charts.GenericSeriesList
+	var names []string
+	for i, mark := range ir.Marks {
+		values := make([]float64, len(categories))
+		for j := range values {
+			values[j] = charts.GetNullValue()
+		}
+		for j, label := range mvs[i].labels {
+			values[catIndex[label]] = mvs[i].values[j]
+		}
+		series := charts.GenericSeries{
+			Type:   comboSeriesType(mark.Geometry),
+			Name:   mark.DisplayName(),
+			Values: values,
+		}
+		if isSecondaryYAxis(ir, mark) {
+			series.YAxisIndex = 1
+		}
+		seriesList = append(seriesList, series)
+		names = append(names, mark.DisplayName())
+	}
+
+	opt := charts.ChartOption{
+		Width:      c.Width,
+		Height:     c.Height,
+		Title:      charts.TitleOption{Text: ir.Title},
+		SeriesList: seriesList,
+		XAxis:      charts.XAxisOption{Labels: categories},
+		Legend:     charts.LegendOption{SeriesNames: names},
+	}
+	if stack != "" {
+		opt.StackSeries = charts.Ptr(true)
+	}
+	if hasArea {
+		opt.FillArea = charts.Ptr(true)
+	}
+	if xAxis := ir.GetXAxis(); xAxis != nil {
+		opt.XAxis.Title = xAxis.Name
+	}
+	if ir.Legend != nil && !ir.Legend.Show {
+		opt.Legend.Show = charts.Ptr(false)
+	}
+
+	var leftAxis *chartir.Axis
+	for i := range ir.Axes {
+		if ir.Axes[i].IsVertical() && ir.Axes[i].Position != chartir.AxisPositionRight {
+			leftAxis = &ir.Axes[i]
+			break
+		}
+	}
+	opt.YAxis = []charts.YAxisOption{comboYAxisOption(leftAxis)}
+	if rightAxis != nil {
+		opt.YAxis = append(opt.YAxis, comboYAxisOption(rightAxis))
+	}
+
+	return &OptionChart{Option: opt}, nil
+}
+
+func comboSeriesType(g chartir.Geometry) string {
+	switch g {
+	case chartir.GeometryBar:
+		return charts.ChartTypeBar
+	case chartir.GeometryScatter:
+		return charts.ChartTypeScatter
+	default:
+		return charts.ChartTypeLine
+	}
+}
+
+func comboYAxisOption(axis *chartir.Axis) charts.YAxisOption {
+	if axis == nil {
+		return charts.YAxisOption{}
+	}
+	return charts.YAxisOption{
+		Title: axis.Name,
+		Min:   axis.Min,
+		Max:   axis.Max,
+	}
+}
@@ -0,0 +1,109 @@
+package wchart
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/go-analyze/charts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+const (
+	gaugeStartAngle = 225.0
+	gaugeEndAngle   = -45.0
+	gaugeArcSteps   = 64
+)
+
+// compileGaugeChart compiles a gauge mark with one dial per row, laid out
+// side by side. Style.GaugeMin and Style.GaugeMax set the dial range,
+// defaulting to 0 and 100. Style.StartAngle and Style.EndAngle set the arc
+// in degrees counterclockwise from 3 o'clock, as in ECharts, defaulting to
+// 225 and -45.
+func (c *Compiler) compileGaugeChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	nameCol, valueCol := mark.Encode.NameValueColumns()
+	names := dataset.GetStringValues(nameCol)
+	values := dataset.GetFloat64Values(valueCol)
+
+	lo, hi := 0.0, 100.0
+	start, end := gaugeStartAngle, gaugeEndAngle
+	if s := mark.Style; s != nil {
+		if s.GaugeMin != nil {
+			lo = *s.GaugeMin
+		}
+		if s.GaugeMax != nil {
+			hi = *s.GaugeMax
+		}
+		if s.StartAngle != nil {
+			start = *s.StartAngle
+		}
+		if s.EndAngle != nil {
+			end = *s.EndAngle
+		}
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw: func(p *charts.Painter) error {
+			top := c.drawBackground(p, ir.Title)
+			if len(values) == 0 {
+				return nil
+			}
+			palette := c.palette()
+			track := palette.TextColor().WithAlpha(32)
+			cellW := float64(p.Width()-2*nativePadding) / float64(len(values))
+			cellH := float64(p.Height() - top - nativePadding)
+			radius := min(cellW, cellH)/2 - nativePadding
+			if radius <= 0 {
+				return nil
+			}
+			thickness := max(radius/5, 4)
+			for i, v := range values {
+				cx := nativePadding + cellW*(float64(i)+0.5)
+				cy := float64(top) + cellH/2
+				ratio := min(max((v-lo)/(hi-lo), 0), 1)
+				p.FillArea(gaugeArc(cx, cy, radius, thickness, start, end), track)
+				p.FillArea(gaugeArc(cx, cy, radius, thickness, start, start+(end-start)*ratio), palette.GetSeriesColor(i))
+
+				fs := charts.FontStyle{FontSize: max(radius/4, 10), FontColor: palette.TextColor()}
+				label := strconv.FormatFloat(v, 'f', -1, 64)
+				box := p.MeasureText(label, 0, fs)
+				p.Text(label, int(cx)-box.Width()/2, int(cy)+box.Height()/2, 0, fs)
+				if i < len(names) && names[i] != "" {
+					fs.FontSize = max(radius/8, 10)
+					nameBox := p.MeasureText(names[i], 0, fs)
+					p.Text(names[i], int(cx)-nameBox.Width()/2, int(cy)+box.Height()+nameBox.Height(), 0, fs)
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// gaugeArc returns the outline of an arc band of the given outer radius
+// and thickness between two angles in degrees.
+func gaugeArc(cx, cy, r, thickness, startDeg, endDeg float64) []charts.Point {
+	point := func(radius, deg float64) charts.Point {
+		rad := deg * math.Pi / 180
+		return charts.Point{
+			X: int(math.Round(cx + radius*math.Cos(rad))),
+			Y: int(math.Round(cy - radius*math.Sin(rad))),
+		}
+	}
+	points := make([]charts.Point, 0, 2*(gaugeArcSteps+1)+1)
+	for i := 0; i <= gaugeArcSteps; i++ {
+		points = append(points, point(r, startDeg+(endDeg-startDeg)*float64(i)/gaugeArcSteps))
+	}
+	for i := gaugeArcSteps; i >= 0; i-- {
+		points = append(points, point(r-thickness, startDeg+(endDeg-startDeg)*float64(i)/gaugeArcSteps))
+	}
+	return append(points, points[0])
+}
@@ -0,0 +1,235 @@
+package wchart
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/go-analyze/charts"
+	"github.com/go-analyze/charts/chartdraw"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/mogo/type/stringsutil"
+)
+
+// PainterChart adapts a function drawing on a go-analyze/charts Painter to
+// ChartType. It is used for geometries that chartdraw does not provide:
+// heatmap, funnel, radar, treemap, sankey and gauge.
+type PainterChart struct {
+	Width  int
+	Height int
+	Draw   func(p *charts.Painter) error
+}
+
+// Render renders the chart. The output format is selected as for OptionChart.
+func (pc *PainterChart) Render(rp chartdraw.RendererProvider, w io.Writer) error {
+	p := charts.NewPainter(charts.PainterOptions{
+		OutputFormat: outputFormat(rp),
+		Width:        pc.Width,
+		Height:       pc.Height,
+	})
+	if err := pc.Draw(p); err != nil {
+		return err
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func legendOption(ir *chartir.ChartIR, names []string) charts.LegendOption {
+	legend := charts.LegendOption{SeriesNames: names}
+	if ir.Legend != nil && !ir.Legend.Show {
+		legend.Show = charts.Ptr(false)
+	}
+	return legend
+}
+
+// compileHeatmapChart compiles a heatmap mark to a matrix with x values as
+// columns and y values as rows, in order of first appearance. Cells without
+// a row in the dataset are left empty.
+func (c *Compiler) compileHeatmapChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	xLabels := dataset.GetStringValues(mark.Encode.X)
+	yLabels := dataset.GetStringValues(mark.Encode.Y)
+	heats := dataset.GetFloat64Values(stringsutil.FirstNonEmpty(mark.Encode.Heat, mark.Encode.Value))
+
+	var xCats, yCats []string
+	xIndex, yIndex := map[string]int{}, map[string]int{}
+	for i := range xLabels {
+		if _, ok := xIndex[xLabels[i]]; !ok {
+			xIndex[xLabels[i]] = len(xCats)
+			xCats = append(xCats, xLabels[i])
+		}
+		if _, ok := yIndex[yLabels[i]]; !ok {
+			yIndex[yLabels[i]] = len(yCats)
+			yCats = append(yCats, yLabels[i])
+		}
+	}
+	values := make([][]float64, len(yCats))
+	for y := range values {
+		values[y] = make([]float64, len(xCats))
+		for x := range values[y] {
+			values[y][x] = charts.GetNullValue()
+		}
+	}
+	for i := range heats {
+		values[yIndex[yLabels[i]]][xIndex[xLabels[i]]] = heats[i]
+	}
+
+	opt := charts.HeatMapOption{
+		Title:       charts.TitleOption{Text: ir.Title},
+		Values:      values,
+		XAxis:       charts.HeatMapAxis{Labels: xCats},
+		YAxis:       charts.HeatMapAxis{Labels: yCats},
+		ValuesLabel: charts.SeriesLabel{Show: charts.Ptr(true)},
+	}
+	if xAxis := ir.GetXAxis(); xAxis != nil {
+		opt.XAxis.Title = xAxis.Name
+	}
+	if yAxis := ir.GetYAxis(); yAxis != nil {
+		opt.YAxis.Title = yAxis.Name
+	}
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw:   func(p *charts.Painter) error { return p.HeatMapChart(opt) },
+	}, nil
+}
+
+// compileFunnelChart compiles a funnel mark with one section per row.
+// Style.FunnelSort of "ascending" or "descending" sorts the sections,
+// otherwise dataset order is kept.
+func (c *Compiler) compileFunnelChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	nameCol, valueCol := mark.Encode.NameValueColumns()
+	names := dataset.GetStringValues(nameCol)
+	values := dataset.GetFloat64Values(valueCol)
+
+	if mark.Style != nil && (mark.Style.FunnelSort == "ascending" || mark.Style.FunnelSort == "descending") {
+		idx := make([]int, len(values))
+		for i := range idx {
+			idx[i] = i
+		}
+		slices.SortStableFunc(idx, func(a, b int) int {
+			if mark.Style.FunnelSort == "ascending" {
+				return cmp.Compare(values[a], values[b])
+			}
+			return cmp.Compare(values[b], values[a])
+		})
+		sortedNames := make([]string, len(idx))
+		sortedValues := make([]float64, len(idx))
+		for i, j := range idx {
+			sortedNames[i], sortedValues[i] = names[j], values[j]
+		}
+		names, values = sortedNames, sortedValues
+	}
+
+	opt := charts.FunnelChartOption{
+		Title:      charts.TitleOption{Text: ir.Title},
+		SeriesList: charts.NewSeriesListFunnel(values, charts.FunnelSeriesOption{Names: names}),
+		Legend:     legendOption(ir, names),
+	}
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw:   func(p *charts.Painter) error { return p.FunnelChart(opt) },
+	}, nil
+}
+
+// compileRadarChart compiles radar marks with one series per mark. The
+// indicators are the union of indicator values across marks, and each
+// indicator's maximum is the largest value across marks.
+func (c *Compiler) compileRadarChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	var indicators []string
+	indicatorIndex := map[string]int{}
+	type markValues struct {
+		names  []string
+		values []float64
+	}
+	var mvs []markValues
+	var seriesNames []string
+	for _, mark := range ir.Marks {
+		dataset, err := ir.MarkDataset(mark)
+		if err != nil {
+			return nil, err
+		}
+		mv := markValues{
+			names:  dataset.GetStringValues(stringsutil.FirstNonEmpty(mark.Encode.Indicator, mark.Encode.Name, mark.Encode.Category)),
+			values: dataset.GetFloat64Values(stringsutil.FirstNonEmpty(mark.Encode.Value, mark.Encode.Y)),
+		}
+		for _, name := range mv.names {
+			if _, ok := indicatorIndex[name]; !ok {
+				indicatorIndex[name] = len(indicators)
+				indicators = append(indicators, name)
+			}
+		}
+		mvs = append(mvs, mv)
+		seriesNames = append(seriesNames, mark.DisplayName())
+	}
+	if len(indicators) < 3 {
+		return nil, fmt.Errorf("chartir: radar geometry requires at least 3 indicators, got: %d", len(indicators))
+	}
+
+	maxes := make([]float64, len(indicators))
+	values := make([][]float64, len(mvs))
+	for i, mv := range mvs {
+		values[i] = make([]float64, len(indicators))
+		for j, name := range mv.names {
+			k := indicatorIndex[name]
+			values[i][k] = mv.values[j]
+			maxes[k] = max(maxes[k], mv.values[j])
+		}
+	}
+	for i := range maxes {
+		if maxes[i] <= 0 {
+			maxes[i] = 1
+		}
+	}
+
+	opt := charts.RadarChartOption{
+		Title:           charts.TitleOption{Text: ir.Title},
+		SeriesList:      charts.NewSeriesListRadar(values, charts.RadarSeriesOption{Names: seriesNames}),
+		RadarIndicators: charts.NewRadarIndicators(indicators, maxes),
+		Legend:          legendOption(ir, seriesNames),
+	}
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw:   func(p *charts.Painter) error { return p.RadarChart(opt) },
+	}, nil
+}
+
+// nativePadding is the padding in pixels for natively drawn charts.
+const nativePadding = 20
+
+func (c *Compiler) palette() chartdraw.ColorPalette {
+	if c.ColorPalette != nil {
+		return c.ColorPalette
+	}
+	return chartdraw.DefaultColorPalette
+}
+
+// drawBackground fills the painter with the palette background color and
+// draws the centered title. It returns the top of the area below the title.
+func (c *Compiler) drawBackground(p *charts.Painter, title string) int {
+	bg := c.palette().BackgroundColor()
+	p.FilledRect(0, 0, p.Width(), p.Height(), bg, bg, 0)
+	if title == "" {
+		return nativePadding
+	}
+	fs := charts.FontStyle{FontSize: 16, FontColor: c.palette().TextColor()}
+	box := p.MeasureText(title, 0, fs)
+	p.Text(title, (p.Width()-box.Width())/2, nativePadding+box.Height(), 0, fs)
+	return 2*nativePadding + box.Height()
+}
@@ -0,0 +1,244 @@
+package wchart
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/go-analyze/charts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+const (
+	sankeyNodeWidth = 14
+	sankeyNodeGap   = 10
+	sankeyCurveStep = 16
+)
+
+type sankeyLink struct {
+	source, target int
+	value          float64
+}
+
+type sankeyNode struct {
+	name    string
+	column  int
+	value   float64
+	y, h    float64
+	in, out []int // link indexes
+}
+
+// compileSankeyChart compiles a sankey mark. Nodes are placed in columns
+// by their longest path from a source node and links are drawn as curved
+// bands. Links must not form a cycle.
+func (c *Compiler) compileSankeyChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	sources := dataset.GetStringValues(mark.Encode.Source)
+	targets := dataset.GetStringValues(mark.Encode.Target)
+	values := dataset.GetFloat64Values(mark.Encode.Value)
+
+	var nodes []sankeyNode
+	nodeIndex := map[string]int{}
+	nodeID := func(name string) int {
+		if i, ok := nodeIndex[name]; ok {
+			return i
+		}
+		nodeIndex[name] = len(nodes)
+		nodes = append(nodes, sankeyNode{name: name})
+		return len(nodes) - 1
+	}
+	var links []sankeyLink
+	for i := range sources {
+		if sources[i] == targets[i] {
+			return nil, fmt.Errorf("chartir: sankey link cannot target its source: %s", sources[i])
+		}
+		if values[i] <= 0 {
+			continue
+		}
+		link := sankeyLink{source: nodeID(sources[i]), target: nodeID(targets[i]), value: values[i]}
+		nodes[link.source].out = append(nodes[link.source].out, len(links))
+		nodes[link.target].in = append(nodes[link.target].in, len(links))
+		links = append(links, link)
+	}
+	columns, err := sankeyColumns(nodes, links)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes {
+		in, out := 0.0, 0.0
+		for _, li := range nodes[i].in {
+			in += links[li].value
+		}
+		for _, li := range nodes[i].out {
+			out += links[li].value
+		}
+		nodes[i].value = max(in, out)
+	}
+
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw: func(p *charts.Painter) error {
+			top := c.drawBackground(p, ir.Title)
+			c.drawSankey(p, nodes, links, columns, treemapRect{
+				x: nativePadding,
+				y: float64(top),
+				w: float64(p.Width() - 2*nativePadding),
+				h: float64(p.Height() - top - nativePadding),
+			})
+			return nil
+		},
+	}, nil
+}
+
+// sankeyColumns sets each node's column to the length of the longest path
+// from a source node and returns the number of columns. It returns an
+// error if the links contain a cycle.
+func sankeyColumns(nodes []sankeyNode, links []sankeyLink) (int, error) {
+	// Kahn's algorithm gives a topological order for the longest path.
+	inDegree := make([]int, len(nodes))
+	for _, link := range links {
+		inDegree[link.target]++
+	}
+	var queue []int
+	for i := range nodes {
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	visited, columns := 0, 0
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		visited++
+		columns = max(columns, nodes[i].column+1)
+		for _, li := range nodes[i].out {
+			t := links[li].target
+			nodes[t].column = max(nodes[t].column, nodes[i].column+1)
+			if inDegree[t]--; inDegree[t] == 0 {
+				queue = append(queue, t)
+			}
+		}
+	}
+	if visited != len(nodes) {
+		return 0, fmt.Errorf("chartir: sankey links contain a cycle")
+	}
+	return columns, nil
+}
+
+func (c *Compiler) drawSankey(p *charts.Painter, nodes []sankeyNode, links []sankeyLink, columns int, area treemapRect) {
+	if len(nodes) == 0 {
+		return
+	}
+	// Scale node heights so the fullest column fits the area.
+	colSums := make([]float64, columns)
+	colCounts := make([]int, columns)
+	for _, n := range nodes {
+		colSums[n.column] += n.value
+		colCounts[n.column]++
+	}
+	scale := -1.0
+	for i := range colSums {
+		if colSums[i] <= 0 {
+			continue
+		}
+		s := (area.h - float64(sankeyNodeGap*(colCounts[i]-1))) / colSums[i]
+		if scale < 0 || s < scale {
+			scale = s
+		}
+	}
+	if scale <= 0 {
+		return
+	}
+
+	colX := func(col int) float64 {
+		if columns == 1 {
+			return area.x
+		}
+		return area.x + float64(col)*(area.w-sankeyNodeWidth)/float64(columns-1)
+	}
+	colY := make([]float64, columns)
+	for i := range colY {
+		colY[i] = area.y
+	}
+	for i := range nodes {
+		n := &nodes[i]
+		n.h = n.value * scale
+		n.y = colY[n.column]
+		colY[n.column] += n.h + sankeyNodeGap
+	}
+
+	// Order each node's links by the position of the node at the other end
+	// so bands do not cross at the node.
+	for i := range nodes {
+		slices.SortStableFunc(nodes[i].out, func(a, b int) int {
+			return cmp.Compare(nodes[links[a].target].y, nodes[links[b].target].y)
+		})
+		slices.SortStableFunc(nodes[i].in, func(a, b int) int {
+			return cmp.Compare(nodes[links[a].source].y, nodes[links[b].source].y)
+		})
+	}
+	linkSourceY := make([]float64, len(links))
+	linkTargetY := make([]float64, len(links))
+	for _, n := range nodes {
+		y := n.y
+		for _, li := range n.out {
+			linkSourceY[li] = y
+			y += links[li].value * scale
+		}
+		y = n.y
+		for _, li := range n.in {
+			linkTargetY[li] = y
+			y += links[li].value * scale
+		}
+	}
+
+	palette := c.palette()
+	for li, link := range links {
+		x0 := colX(nodes[link.source].column) + sankeyNodeWidth
+		x1 := colX(nodes[link.target].column)
+		h := link.value * scale
+		color := palette.GetSeriesColor(link.source).WithAlpha(96)
+		p.FillArea(sankeyBand(x0, linkSourceY[li], x1, linkTargetY[li], h), color)
+	}
+
+	fs := charts.FontStyle{FontSize: 10, FontColor: palette.TextColor()}
+	for i, n := range nodes {
+		x := colX(n.column)
+		color := palette.GetSeriesColor(i)
+		p.FilledRect(int(x), int(n.y), int(x+sankeyNodeWidth), int(n.y+n.h), color, color, 0)
+		box := p.MeasureText(n.name, 0, fs)
+		labelX := int(x) + sankeyNodeWidth + 4
+		if n.column == columns-1 && columns > 1 {
+			labelX = int(x) - 4 - box.Width()
+		}
+		p.Text(n.name, labelX, int(n.y+n.h/2)+box.Height()/2, 0, fs)
+	}
+}
+
+// sankeyBand returns the outline of a link band of height h from (x0, y0)
+// to (x1, y1), with the top and bottom edges drawn as cubic curves.
+func sankeyBand(x0, y0, x1, y1, h float64) []charts.Point {
+	curve := func(ya, yb float64, t float64) (float64, float64) {
+		// Cubic Bezier with control points at the horizontal midpoint.
+		xm := (x0 + x1) / 2
+		u := 1 - t
+		x := u*u*u*x0 + 3*u*u*t*xm + 3*u*t*t*xm + t*t*t*x1
+		y := u*u*u*ya + 3*u*u*t*ya + 3*u*t*t*yb + t*t*t*yb
+		return x, y
+	}
+	points := make([]charts.Point, 0, 2*(sankeyCurveStep+1)+1)
+	for i := 0; i <= sankeyCurveStep; i++ {
+		x, y := curve(y0, y1, float64(i)/sankeyCurveStep)
+		points = append(points, charts.Point{X: int(x), Y: int(y)})
+	}
+	for i := sankeyCurveStep; i >= 0; i-- {
+		x, y := curve(y0+h, y1+h, float64(i)/sankeyCurveStep)
+		points = append(points, charts.Point{X: int(x), Y: int(y)})
+	}
+	return append(points, points[0])
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/go-analyze/charts/chartdraw"
 	"github.com/grokify/gocharts/v2/charts/chartir"
 )
 
@@ -58,6 +59,27 @@ func TestCompileBarChart(t *testing.T) {
 			}
 		}
 	}
+
+	// Vertical and horizontal encodings resolve to the same bars, as in
+	// combo charts and the other compilers.
+	for _, enc := range []chartir.Encode{
+		{X: "label", Y: "value"},
+		{X: "value", Y: "label"},
+		{X: "label", Value: "value"},
+	} {
+		ir.Marks[0].Encode = enc
+		chart, err := compiler.Compile(ir)
+		if err != nil {
+			t.Fatalf("Compile failed for encode %v: %v", enc, err)
+		}
+		bar, ok := chart.(*chartdraw.BarChart)
+		if !ok {
+			t.Fatalf("Compile expected *chartdraw.BarChart, got %T", chart)
+		}
+		if len(bar.Bars) != 3 || bar.Bars[1].Label != "B" || bar.Bars[1].Value != 20 {
+			t.Errorf("Compile bars mismatch for encode %v: got %v", enc, bar.Bars)
+		}
+	}
 }
 
 func TestCompileScatterChart(t *testing.T) {
@@ -149,4 +171,195 @@ func TestCompilePieChart(t *testing.T) {
 	if buf.Len() == 0 {
 		t.Error("RenderPNG produced empty output")
 	}
+
+	// Validate accepts x and y as the name and value columns.
+	ir.Marks[0].Encode = chartir.Encode{X: "category", Y: "value"}
+	if err := chartir.Validate(ir); err != nil {
+		t.Fatalf("Validate failed for x/y pie: %v", err)
+	}
+	chart, err := compiler.Compile(ir)
+	if err != nil {
+		t.Fatalf("Compile failed for x/y pie: %v", err)
+	}
+	pie, ok := chart.(*chartdraw.PieChart)
+	if !ok {
+		t.Fatalf("Compile expected *chartdraw.PieChart, got %T", chart)
+	}
+	if len(pie.Values) != 3 || pie.Values[1].Label != "Blue" || pie.Values[1].Value != 50 {
+		t.Errorf("Compile x/y pie values mismatch: got %v", pie.Values)
+	}
+}
+
+func TestCompileComboChart(t *testing.T) {
+	ir := &chartir.ChartIR{
+		Title: "Test Combo Chart",
+		Datasets: []chartir.Dataset{
+			{
+				ID: "data",
+				Columns: []chartir.Column{
+					{Name: "month", Type: chartir.ColumnTypeString},
+					{Name: "sales", Type: chartir.ColumnTypeNumber},
+					{Name: "margin", Type: chartir.ColumnTypeNumber},
+				},
+				Rows: [][]string{
+					{"Jan", "100", "0.2"},
+					{"Feb", "150", "0.3"},
+					{"Mar", "120", "0.25"},
+				},
+			},
+		},
+		Marks: []chartir.Mark{
+			{
+				ID:        "sales",
+				DatasetID: "data",
+				Geometry:  chartir.GeometryBar,
+				Encode:    chartir.Encode{X: "month", Y: "sales"},
+			},
+			{
+				ID:        "margin",
+				DatasetID: "data",
+				Geometry:  chartir.GeometryLine,
+				Encode:    chartir.Encode{X: "month", Y: "margin"},
+				YAxisID:   "y2",
+			},
+		},
+		Axes: []chartir.Axis{
+			{ID: "x", Type: chartir.AxisTypeCategory, Position: chartir.AxisPositionBottom},
+			{ID: "y", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionLeft},
+			{ID: "y2", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionRight},
+		},
+	}
+
+	compiler := NewCompiler()
+	chart, err := compiler.Compile(ir)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+	oc, ok := chart.(*OptionChart)
+	if !ok {
+		t.Fatalf("Compile expected *OptionChart, got %T", chart)
+	}
+	if len(oc.Option.YAxis) != 2 || oc.Option.SeriesList[1].YAxisIndex != 1 {
+		t.Error("Compile expected margin series on secondary y-axis")
+	}
+	if got := oc.Option.Legend.SeriesNames; len(got) != 2 || got[0] != "sales" || oc.Option.SeriesList[1].Name != "margin" {
+		t.Errorf("Compile expected series names to fall back to mark IDs, got [%v]", got)
+	}
+
+	var buf bytes.Buffer
+	if err := compiler.RenderSVG(ir, &buf); err != nil {
+		t.Fatalf("RenderSVG failed: %v", err)
+	}
+	if content := buf.String(); len(content) == 0 || content[0] != '<' {
+		t.Error("RenderSVG output doesn't look like SVG")
+	}
+
+	// Lines cannot be stacked separately from bars.
+	ir.Marks[0].Stack = "total"
+	if _, err := compiler.Compile(ir); err == nil {
+		t.Error("Compile expected error for partially stacked marks")
+	}
+}
+
+func TestCompileTimeAxisLineChart(t *testing.T) {
+	ir := &chartir.ChartIR{
+		Title: "Test Time Axis Line Chart",
+		Datasets: []chartir.Dataset{
+			{
+				ID: "data",
+				Columns: []chartir.Column{
+					{Name: "time", Type: chartir.ColumnTypeString},
+					{Name: "value", Type: chartir.ColumnTypeNumber},
+				},
+				Rows: [][]string{
+					{"2024-01-01T00:00:00Z", "10"},
+					{"2024-02-01T00:00:00Z", "20"},
+					{"2024-03-01", "15"},
+				},
+			},
+		},
+		Marks: []chartir.Mark{
+			{
+				ID:        "series",
+				DatasetID: "data",
+				Geometry:  chartir.GeometryLine,
+				Encode:    chartir.Encode{X: "time", Y: "value"},
+			},
+		},
+		Axes: []chartir.Axis{
+			{ID: "x", Type: chartir.AxisTypeTime, Position: chartir.AxisPositionBottom},
+			{ID: "y", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionLeft},
+		},
+	}
+
+	compiler := NewCompiler()
+	var buf bytes.Buffer
+	if err := compiler.RenderSVG(ir, &buf); err != nil {
+		t.Fatalf("RenderSVG failed: %v", err)
+	}
+
+	ir.Datasets[0].Rows[2][0] = "March"
+	if err := compiler.RenderSVG(ir, &buf); err == nil {
+		t.Error("RenderSVG expected error for invalid time value")
+	}
+}
+
+func TestCompilePainterCharts(t *testing.T) {
+	dataset := func(rows ...[]string) []chartir.Dataset {
+		return []chartir.Dataset{{
+			ID: "data",
+			Columns: []chartir.Column{
+				{Name: "a", Type: chartir.ColumnTypeString},
+				{Name: "b", Type: chartir.ColumnTypeString},
+				{Name: "v", Type: chartir.ColumnTypeNumber},
+			},
+			Rows: rows,
+		}}
+	}
+	rows := dataset(
+		[]string{"A", "X", "10"},
+		[]string{"A", "Y", "20"},
+		[]string{"B", "X", "15"},
+		[]string{"C", "Y", "5"},
+	)
+	tests := []struct {
+		name     string
+		geometry chartir.Geometry
+		encode   chartir.Encode
+		datasets []chartir.Dataset
+		wantErr  bool
+	}{
+		{"heatmap", chartir.GeometryHeatmap, chartir.Encode{X: "a", Y: "b", Heat: "v"}, rows, false},
+		{"funnel", chartir.GeometryFunnel, chartir.Encode{Name: "a", Value: "v"}, rows, false},
+		{"radar", chartir.GeometryRadar, chartir.Encode{Indicator: "a", Value: "v"}, rows, false},
+		{"treemap", chartir.GeometryTreemap, chartir.Encode{Name: "a", Category: "b", Value: "v"}, rows, false},
+		{"sankey", chartir.GeometrySankey, chartir.Encode{Source: "a", Target: "b", Value: "v"}, rows, false},
+		{"gauge", chartir.GeometryGauge, chartir.Encode{Name: "a", Value: "v"}, rows, false},
+		{"sankey cycle", chartir.GeometrySankey, chartir.Encode{Source: "a", Target: "b", Value: "v"},
+			dataset([]string{"A", "B", "1"}, []string{"B", "A", "1"}), true},
+	}
+
+	compiler := NewCompiler()
+	for _, tt := range tests {
+		ir := &chartir.ChartIR{
+			Title:    tt.name,
+			Datasets: tt.datasets,
+			Marks: []chartir.Mark{
+				{ID: "mark", DatasetID: "data", Geometry: tt.geometry, Encode: tt.encode},
+			},
+		}
+		var buf bytes.Buffer
+		err := compiler.RenderSVG(ir, &buf)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("RenderSVG(%s) expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("RenderSVG(%s) failed: %v", tt.name, err)
+		} else if content := buf.String(); len(content) == 0 || content[0] != '<' {
+			t.Errorf("RenderSVG(%s) output doesn't look like SVG", tt.name)
+		}
+	}
 }
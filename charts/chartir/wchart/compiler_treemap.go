@@ -0,0 +1,197 @@
+package wchart
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/go-analyze/charts"
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/mogo/type/stringsutil"
+)
+
+// treemapRect is a rectangle in painter coordinates.
+type treemapRect struct {
+	x, y, w, h float64
+}
+
+// treemapNode is a treemap group or leaf.
+type treemapNode struct {
+	name     string
+	value    float64
+	children []treemapNode
+}
+
+// compileTreemapChart compiles a treemap mark using a squarified layout.
+// If Encode.Name and Encode.Category are both set, category values group
+// the leaves and each group is drawn in its own color with a header.
+// Otherwise each row is a leaf. Rows with non-positive values are skipped.
+func (c *Compiler) compileTreemapChart(ir *chartir.ChartIR) (*PainterChart, error) {
+	mark := ir.Marks[0]
+	dataset, err := ir.MarkDataset(mark)
+	if err != nil {
+		return nil, err
+	}
+	names := dataset.GetStringValues(stringsutil.FirstNonEmpty(mark.Encode.Name, mark.Encode.Category))
+	values := dataset.GetFloat64Values(mark.Encode.Value)
+	var parents []string
+	if mark.Encode.Name != "" && mark.Encode.Category != "" {
+		parents = dataset.GetStringValues(mark.Encode.Category)
+	}
+
+	var nodes []treemapNode
+	groupIndex := map[string]int{}
+	for i, name := range names {
+		if values[i] <= 0 {
+			continue
+		}
+		leaf := treemapNode{name: name, value: values[i]}
+		if parents == nil {
+			nodes = append(nodes, leaf)
+			continue
+		}
+		gi, ok := groupIndex[parents[i]]
+		if !ok {
+			gi = len(nodes)
+			groupIndex[parents[i]] = gi
+			nodes = append(nodes, treemapNode{name: parents[i]})
+		}
+		nodes[gi].value += leaf.value
+		nodes[gi].children = append(nodes[gi].children, leaf)
+	}
+
+	return &PainterChart{
+		Width:  c.Width,
+		Height: c.Height,
+		Draw: func(p *charts.Painter) error {
+			top := c.drawBackground(p, ir.Title)
+			area := treemapRect{
+				x: nativePadding,
+				y: float64(top),
+				w: float64(p.Width() - 2*nativePadding),
+				h: float64(p.Height() - top - nativePadding),
+			}
+			c.drawTreemapNodes(p, nodes, area, parents != nil)
+			return nil
+		},
+	}, nil
+}
+
+func (c *Compiler) drawTreemapNodes(p *charts.Painter, nodes []treemapNode, area treemapRect, grouped bool) {
+	values := make([]float64, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value
+	}
+	palette := c.palette()
+	border := palette.BackgroundColor()
+	labelStyle := charts.FontStyle{FontSize: 10, FontColor: border}
+	for i, r := range squarify(values, area) {
+		color := palette.GetSeriesColor(i)
+		if !grouped {
+			drawTreemapRect(p, r, nodes[i].name, color, border, labelStyle)
+			continue
+		}
+		// Draw the group header, then the leaves in lighter shades of the group color.
+		header := treemapRect{x: r.x, y: r.y, w: r.w, h: min(18, r.h)}
+		drawTreemapRect(p, header, nodes[i].name, color, border, labelStyle)
+		inner := treemapRect{x: r.x, y: r.y + header.h, w: r.w, h: r.h - header.h}
+		childValues := make([]float64, len(nodes[i].children))
+		for j, child := range nodes[i].children {
+			childValues[j] = child.value
+		}
+		for j, cr := range squarify(childValues, inner) {
+			shade := color.WithAdjustHSL(0, 0, 0.08*float64(j%3))
+			drawTreemapRect(p, cr, nodes[i].children[j].name, shade, border, labelStyle)
+		}
+	}
+}
+
+// drawTreemapRect draws a filled rectangle with the label in its top-left
+// corner when the label fits.
+func drawTreemapRect(p *charts.Painter, r treemapRect, label string, fill, border charts.Color, fs charts.FontStyle) {
+	if r.w < 1 || r.h < 1 {
+		return
+	}
+	x1, y1, x2, y2 := int(r.x), int(r.y), int(r.x+r.w), int(r.y+r.h)
+	p.FilledRect(x1, y1, x2, y2, fill, border, 1)
+	box := p.MeasureText(label, 0, fs)
+	if box.Width()+8 <= x2-x1 && box.Height()+6 <= y2-y1 {
+		p.Text(label, x1+4, y1+3+box.Height(), 0, fs)
+	}
+}
+
+// squarify lays out values in the area using the squarified treemap
+// algorithm of Bruls, Huizing and van Wijk. It returns one rectangle per
+// value in input order. Non-positive values get an empty rectangle.
+func squarify(values []float64, area treemapRect) []treemapRect {
+	rects := make([]treemapRect, len(values))
+	var idx []int
+	sum := 0.0
+	for i, v := range values {
+		if v > 0 {
+			idx = append(idx, i)
+			sum += v
+		}
+	}
+	if sum <= 0 || area.w <= 0 || area.h <= 0 {
+		return rects
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return cmp.Compare(values[b], values[a])
+	})
+	scale := area.w * area.h / sum
+	areas := make([]float64, len(idx))
+	for i, j := range idx {
+		areas[i] = values[j] * scale
+	}
+
+	r := area
+	for i := 0; i < len(areas); {
+		side := min(r.w, r.h)
+		j := i + 1
+		for j < len(areas) && worstRatio(areas[i:j+1], side) <= worstRatio(areas[i:j], side) {
+			j++
+		}
+		rowSum := 0.0
+		for _, a := range areas[i:j] {
+			rowSum += a
+		}
+		if r.w >= r.h {
+			// Lay out the row as a column on the left.
+			colW := rowSum / r.h
+			y := r.y
+			for k := i; k < j; k++ {
+				h := areas[k] / colW
+				rects[idx[k]] = treemapRect{x: r.x, y: y, w: colW, h: h}
+				y += h
+			}
+			r.x += colW
+			r.w -= colW
+		} else {
+			// Lay out the row along the top.
+			rowH := rowSum / r.w
+			x := r.x
+			for k := i; k < j; k++ {
+				w := areas[k] / rowH
+				rects[idx[k]] = treemapRect{x: x, y: r.y, w: w, h: rowH}
+				x += w
+			}
+			r.y += rowH
+			r.h -= rowH
+		}
+		i = j
+	}
+	return rects
+}
+
+// worstRatio returns the worst aspect ratio of a row of areas laid out
+// along a side of the given length.
+func worstRatio(row []float64, side float64) float64 {
+	sum, lo, hi := 0.0, row[0], row[0]
+	for _, a := range row {
+		sum += a
+		lo = min(lo, a)
+		hi = max(hi, a)
+	}
+	s2, sum2 := side*side, sum*sum
+	return max(s2*hi/sum2, sum2/(s2*lo))
+}
@@ -0,0 +1,370 @@
+// Package xlsx compiles ChartIR to native Excel charts using excelize.
+// Datasets are written to worksheet cells which the chart series reference,
+// so charts remain editable in Excel.
+package xlsx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/mogo/type/stringsutil"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// DefaultDataSheet is the name of the worksheet ChartIR datasets are written to.
+const DefaultDataSheet = "Chart Data"
+
+// Compiler converts ChartIR to excelize charts.
+type Compiler struct {
+	// Width is the chart width in pixels. Default: 600.
+	Width uint
+
+	// Height is the chart height in pixels. Default: 400.
+	Height uint
+
+	// DataSheet is the worksheet datasets are written to by AddChart.
+	// Default: DefaultDataSheet.
+	DataSheet string
+
+	// HideDataSheet hides the data worksheet.
+	HideDataSheet bool
+}
+
+// NewCompiler creates a new compiler with default settings.
+func NewCompiler() *Compiler {
+	return &Compiler{
+		Width:     600,
+		Height:    400,
+		DataSheet: DefaultDataSheet,
+	}
+}
+
+// DatasetRange is the location of a dataset in a worksheet, with a header row
+// of column names followed by `RowCount` data rows.
+type DatasetRange struct {
+	Sheet    string
+	StartCol uint32 // zero-based column index of the first column
+	StartRow uint32 // zero-based row index of the header row
+	Columns  []string
+	RowCount int
+}
+
+// HeaderRef returns the absolute reference of a column header cell, such as
+// `'Sales'!$B$1`.
+func (dr DatasetRange) HeaderRef(colName string) (string, error) {
+	colIdx, err := dr.columnIndex(colName)
+	if err != nil {
+		return "", err
+	}
+	cell, err := excelize.CoordinatesToCellName(int(dr.StartCol)+colIdx+1, int(dr.StartRow)+1, true)
+	if err != nil {
+		return "", err
+	}
+	return quoteSheet(dr.Sheet) + "!" + cell, nil
+}
+
+// ColumnRef returns the absolute reference of the data cells of a column,
+// such as `'Sales'!$B$2:$B$13`.
+func (dr DatasetRange) ColumnRef(colName string) (string, error) {
+	colIdx, err := dr.columnIndex(colName)
+	if err != nil {
+		return "", err
+	}
+	col := int(dr.StartCol) + colIdx + 1
+	first, err := excelize.CoordinatesToCellName(col, int(dr.StartRow)+2, true)
+	if err != nil {
+		return "", err
+	}
+	last, err := excelize.CoordinatesToCellName(col, int(dr.StartRow)+1+max(dr.RowCount, 1), true)
+	if err != nil {
+		return "", err
+	}
+	return quoteSheet(dr.Sheet) + "!" + first + ":" + last, nil
+}
+
+func (dr DatasetRange) columnIndex(colName string) (int, error) {
+	for i, c := range dr.Columns {
+		if c == colName {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("column not found [%s]", colName)
+}
+
+func quoteSheet(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+}
+
+// AddChart writes the ChartIR datasets to the data worksheet of `f` and adds
+// the chart to `sheet` with its top left corner at `cell`.
+func (c *Compiler) AddChart(f *excelize.File, sheet, cell string, ir *chartir.ChartIR) error {
+	if err := chartir.Validate(ir); err != nil {
+		return err
+	}
+	ranges, err := c.WriteDatasets(f, ir)
+	if err != nil {
+		return err
+	}
+	charts, err := c.Compile(ir, ranges)
+	if err != nil {
+		return err
+	}
+	return f.AddChart(sheet, cell, charts[0], charts[1:]...)
+}
+
+// WriteDatasets appends the ChartIR datasets to the data worksheet, creating
+// it if needed, and returns their locations by dataset ID. Values in number
+// columns are written as numbers.
+func (c *Compiler) WriteDatasets(f *excelize.File, ir *chartir.ChartIR) (map[string]DatasetRange, error) {
+	dataSheet := c.DataSheet
+	if dataSheet == "" {
+		dataSheet = DefaultDataSheet
+	}
+	idx, err := f.GetSheetIndex(dataSheet)
+	if err != nil {
+		return nil, err
+	}
+	startRow := uint32(0)
+	if idx == -1 {
+		if _, err := f.NewSheet(dataSheet); err != nil {
+			return nil, err
+		}
+		if c.HideDataSheet {
+			if err := f.SetSheetVisible(dataSheet, false); err != nil {
+				return nil, err
+			}
+		}
+	} else if rows, err := f.GetRows(dataSheet); err != nil {
+		return nil, err
+	} else if len(rows) > 0 {
+		startRow = uint32(len(rows)) + 1 //nolint:gosec // G115: row count is bounded by Excel
+	}
+
+	ranges := map[string]DatasetRange{}
+	for _, ds := range ir.Datasets {
+		dr := DatasetRange{Sheet: dataSheet, StartRow: startRow, RowCount: len(ds.Rows)}
+		header := make([]any, len(ds.Columns))
+		for i, col := range ds.Columns {
+			dr.Columns = append(dr.Columns, col.Name)
+			header[i] = col.Name
+		}
+		if err := setRow(f, dataSheet, startRow, header); err != nil {
+			return nil, err
+		}
+		for i, row := range ds.Rows {
+			vals := make([]any, len(row))
+			for j, val := range row {
+				vals[j] = val
+				if j < len(ds.Columns) && ds.Columns[j].Type == chartir.ColumnTypeNumber {
+					if v, err := strconv.ParseFloat(val, 64); err == nil {
+						vals[j] = v
+					}
+				}
+			}
+			if err := setRow(f, dataSheet, startRow+uint32(i)+1, vals); err != nil { //nolint:gosec // G115: row count is bounded by Excel
+				return nil, err
+			}
+		}
+		ranges[ds.ID] = dr
+		startRow += uint32(len(ds.Rows)) + 2 //nolint:gosec // G115: row count is bounded by Excel
+	}
+	return ranges, nil
+}
+
+func setRow(f *excelize.File, sheet string, rowIdx uint32, vals []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, int(rowIdx)+1)
+	if err != nil {
+		return err
+	}
+	return f.SetSheetRow(sheet, cell, &vals)
+}
+
+// chartGroup is the marks compiled to one excelize chart. Excel combo charts
+// require one chart per chart type and y-axis.
+type chartGroup struct {
+	chartType excelize.ChartType
+	secondary bool
+	marks     []chartir.Mark
+}
+
+// Compile converts the ChartIR to excelize charts with series referencing
+// the datasets in `ranges`. The first chart is the primary chart and the
+// rest are combo charts for `excelize.File.AddChart()`. Line, area, bar,
+// scatter, pie and radar geometries are supported.
+func (c *Compiler) Compile(ir *chartir.ChartIR, ranges map[string]DatasetRange) ([]*excelize.Chart, error) {
+	if ir == nil || len(ir.Marks) == 0 {
+		return nil, fmt.Errorf("chart must have at least one mark")
+	}
+	var groups []*chartGroup
+	for _, mark := range ir.Marks {
+		chartType, err := markChartType(ir, mark)
+		if err != nil {
+			return nil, err
+		}
+		secondary := false
+		if axis := ir.GetMarkYAxis(mark); axis != nil && axis.Position == chartir.AxisPositionRight {
+			secondary = true
+		}
+		var group *chartGroup
+		for _, g := range groups {
+			if g.chartType == chartType && g.secondary == secondary {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &chartGroup{chartType: chartType, secondary: secondary}
+			groups = append(groups, group)
+		}
+		group.marks = append(group.marks, mark)
+	}
+
+	var charts []*excelize.Chart
+	for i, g := range groups {
+		chart := &excelize.Chart{Type: g.chartType}
+		for _, mark := range g.marks {
+			series, err := markSeries(ir, mark, ranges)
+			if err != nil {
+				return nil, err
+			}
+			chart.Series = append(chart.Series, series)
+		}
+		chart.YAxis.Secondary = g.secondary
+		if i == 0 {
+			c.setChartOptions(ir, chart)
+		}
+		charts = append(charts, chart)
+	}
+	return charts, nil
+}
+
+func (c *Compiler) setChartOptions(ir *chartir.ChartIR, chart *excelize.Chart) {
+	chart.Dimension = excelize.ChartDimension{Width: c.Width, Height: c.Height}
+	if chart.Dimension.Width == 0 {
+		chart.Dimension.Width = 600
+	}
+	if chart.Dimension.Height == 0 {
+		chart.Dimension.Height = 400
+	}
+	if ir.Title != "" {
+		chart.Title = excelize.ChartTitle{Paragraph: []excelize.RichTextRun{{Text: ir.Title}}}
+	}
+	chart.Legend.Position = "bottom"
+	if ir.Legend != nil {
+		if !ir.Legend.Show {
+			chart.Legend.Position = "none"
+		} else if ir.Legend.Position != "" {
+			chart.Legend.Position = string(ir.Legend.Position)
+		}
+	}
+	if x := ir.GetXAxis(); x != nil {
+		chart.XAxis = chartAxis(x)
+	}
+	if y := ir.GetYAxis(); y != nil {
+		chart.YAxis = chartAxis(y)
+		chart.YAxis.MajorGridLines = true
+	}
+}
+
+func chartAxis(axis *chartir.Axis) excelize.ChartAxis {
+	ca := excelize.ChartAxis{Minimum: axis.Min, Maximum: axis.Max}
+	if axis.Name != "" {
+		ca.Title = excelize.ChartTitle{Paragraph: []excelize.RichTextRun{{Text: axis.Name}}}
+	}
+	if axis.Type == chartir.AxisTypeLog {
+		ca.LogBase = 10
+	}
+	return ca
+}
+
+// markChartType returns the excelize chart type for a mark. Bars with a
+// number x column and a string y column are horizontal.
+func markChartType(ir *chartir.ChartIR, mark chartir.Mark) (excelize.ChartType, error) {
+	stacked := mark.Stack != ""
+	switch mark.Geometry {
+	case chartir.GeometryLine:
+		return excelize.Line, nil
+	case chartir.GeometryArea:
+		if stacked {
+			return excelize.AreaStacked, nil
+		}
+		return excelize.Area, nil
+	case chartir.GeometryBar:
+		_, _, horizontal := markColumns(ir.GetDataset(mark.DatasetID), mark)
+		switch {
+		case horizontal && stacked:
+			return excelize.BarStacked, nil
+		case horizontal:
+			return excelize.Bar, nil
+		case stacked:
+			return excelize.ColStacked, nil
+		default:
+			return excelize.Col, nil
+		}
+	case chartir.GeometryScatter:
+		return excelize.Scatter, nil
+	case chartir.GeometryPie:
+		return excelize.Pie, nil
+	case chartir.GeometryRadar:
+		return excelize.Radar, nil
+	default:
+		return 0, fmt.Errorf("geometry not supported for xlsx charts [%s]", mark.Geometry)
+	}
+}
+
+// markColumns returns the category column and the value column for a mark.
+// horizontal is true when the category is on the y-axis, which only applies
+// to bar geometries.
+func markColumns(dataset *chartir.Dataset, mark chartir.Mark) (catCol, valCol string, horizontal bool) {
+	enc := mark.Encode
+	switch mark.Geometry {
+	case chartir.GeometryPie:
+		catCol, valCol = enc.NameValueColumns()
+		return catCol, valCol, false
+	case chartir.GeometryRadar:
+		return stringsutil.FirstNonEmpty(enc.Indicator, enc.Category, enc.X), stringsutil.FirstNonEmpty(enc.Value, enc.Y), false
+	}
+	return mark.CartesianColumns(dataset)
+}
+
+func markSeries(ir *chartir.ChartIR, mark chartir.Mark, ranges map[string]DatasetRange) (excelize.ChartSeries, error) {
+	dr, ok := ranges[mark.DatasetID]
+	if !ok {
+		return excelize.ChartSeries{}, fmt.Errorf("dataset range not found [%s]", mark.DatasetID)
+	}
+	catCol, valCol, _ := markColumns(ir.GetDataset(mark.DatasetID), mark)
+	series := excelize.ChartSeries{}
+	var err error
+	if catCol != "" {
+		if series.Categories, err = dr.ColumnRef(catCol); err != nil {
+			return series, err
+		}
+	}
+	if series.Values, err = dr.ColumnRef(valCol); err != nil {
+		return series, err
+	}
+	if mark.Geometry == chartir.GeometryScatter && mark.Encode.Size != "" {
+		if series.Sizes, err = dr.ColumnRef(mark.Encode.Size); err != nil {
+			return series, err
+		}
+	}
+	if mark.Name != "" {
+		series.Name = mark.Name
+	} else if series.Name, err = dr.HeaderRef(valCol); err != nil {
+		return series, err
+	}
+	if s := mark.Style; s != nil {
+		if color := strings.TrimPrefix(s.Color, "#"); len(color) == 6 {
+			fill := excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}
+			series.Fill = fill
+			series.Line.Fill = fill
+		}
+		if s.LineWidth != nil {
+			series.Line.Width = *s.LineWidth
+		}
+	}
+	series.Line.Smooth = mark.Smooth || (mark.Style != nil && mark.Style.Smooth)
+	return series, nil
+}
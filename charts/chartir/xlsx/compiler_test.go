@@ -0,0 +1,90 @@
+package xlsx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+func salesChartIR() *chartir.ChartIR {
+	return &chartir.ChartIR{
+		Title: "Sales",
+		Datasets: []chartir.Dataset{{
+			ID: "sales",
+			Columns: []chartir.Column{
+				{Name: "month", Type: chartir.ColumnTypeString},
+				{Name: "revenue", Type: chartir.ColumnTypeNumber},
+				{Name: "margin", Type: chartir.ColumnTypeNumber},
+			},
+			Rows: [][]string{{"Jan", "100", "0.2"}, {"Feb", "120", "0.25"}, {"Mar", "90", "0.18"}},
+		}},
+		Marks: []chartir.Mark{
+			{ID: "revenue", DatasetID: "sales", Geometry: chartir.GeometryBar, Encode: chartir.Encode{X: "month", Y: "revenue"}},
+			{ID: "margin", DatasetID: "sales", Geometry: chartir.GeometryLine, Name: "Margin", YAxisID: "y2",
+				Encode: chartir.Encode{X: "month", Y: "margin"}},
+		},
+		Axes: []chartir.Axis{
+			{ID: "x", Type: chartir.AxisTypeCategory, Position: chartir.AxisPositionBottom},
+			{ID: "y", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionLeft, Name: "Revenue"},
+			{ID: "y2", Type: chartir.AxisTypeValue, Position: chartir.AxisPositionRight},
+		},
+	}
+}
+
+func TestCompileComboChart(t *testing.T) {
+	ir := salesChartIR()
+	ranges := map[string]DatasetRange{
+		"sales": {Sheet: "Q1 Sales", StartCol: 1, StartRow: 2, Columns: []string{"month", "revenue", "margin"}, RowCount: 3},
+	}
+	charts, err := NewCompiler().Compile(ir, ranges)
+	if err != nil {
+		t.Fatalf("Compiler.Compile() error: [%v]", err)
+	}
+	if len(charts) != 2 {
+		t.Fatalf("Compiler.Compile() mismatch: want [%d] charts got [%d]", 2, len(charts))
+	}
+	tests := []struct {
+		chart      *excelize.Chart
+		chartType  excelize.ChartType
+		secondary  bool
+		name       string
+		categories string
+		values     string
+	}{
+		{charts[0], excelize.Col, false, "'Q1 Sales'!$C$3", "'Q1 Sales'!$B$4:$B$6", "'Q1 Sales'!$C$4:$C$6"},
+		{charts[1], excelize.Line, true, "Margin", "'Q1 Sales'!$B$4:$B$6", "'Q1 Sales'!$D$4:$D$6"},
+	}
+	for _, tt := range tests {
+		s := tt.chart.Series[0]
+		if tt.chart.Type != tt.chartType || tt.chart.YAxis.Secondary != tt.secondary ||
+			s.Name != tt.name || s.Categories != tt.categories || s.Values != tt.values {
+			t.Errorf("Compiler.Compile() mismatch: want [%v %v %s %s %s] got [%v %v %s %s %s]",
+				tt.chartType, tt.secondary, tt.name, tt.categories, tt.values,
+				tt.chart.Type, tt.chart.YAxis.Secondary, s.Name, s.Categories, s.Values)
+		}
+	}
+}
+
+func TestAddChart(t *testing.T) {
+	f := excelize.NewFile()
+	c := NewCompiler()
+	c.HideDataSheet = true
+	if err := c.AddChart(f, "Sheet1", "B2", salesChartIR()); err != nil {
+		t.Fatalf("Compiler.AddChart() error: [%v]", err)
+	}
+	if got, err := f.GetCellValue(DefaultDataSheet, "B3"); err != nil || got != "120" {
+		t.Errorf("Compiler.AddChart() data mismatch: want [%s] got [%s]", "120", got)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("excelize.File.Write() error: [%v]", err)
+	}
+	if _, err := NewCompiler().Compile(&chartir.ChartIR{
+		Datasets: []chartir.Dataset{{ID: "d"}},
+		Marks:    []chartir.Mark{{ID: "m", DatasetID: "d", Geometry: chartir.GeometrySankey}},
+	}, nil); err == nil {
+		t.Error("Compiler.Compile() expected error for sankey geometry")
+	}
+}
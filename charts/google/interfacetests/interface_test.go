@@ -4,6 +4,7 @@ package interfacetests
 import (
 	"testing"
 
+	chartirgoogle "github.com/grokify/gocharts/v2/charts/chartir/google"
 	"github.com/grokify/gocharts/v2/charts/google"
 	"github.com/grokify/gocharts/v2/charts/google/barchart"
 	"github.com/grokify/gocharts/v2/charts/google/linechart"
@@ -17,6 +18,7 @@ var chartInterfaceTests = []struct {
 	{&barchart.Chart{Title: "foobar"}, "foobar"},
 	{&linechart.Chart{Title: "foobar"}, "foobar"},
 	{&piechart.Chart{Title: "foobar"}, "foobar"},
+	{&chartirgoogle.Chart{Title: "foobar"}, "foobar"},
 }
 
 // TestChartInterface tests interface functions for `HistogramAny` interface.`
@@ -0,0 +1,60 @@
+package sts2wchart
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-analyze/charts/chartdraw"
+	"github.com/grokify/mogo/strconv/strconvutil"
+	"github.com/grokify/mogo/time/month"
+	"github.com/grokify/mogo/time/quarter"
+	"github.com/grokify/mogo/time/timeutil"
+
+	"github.com/grokify/gocharts/v2/charts/wchart"
+	"github.com/grokify/gocharts/v2/data/timeseries"
+)
+
+// AnomaliesToAnnotations returns an annotation series marking each anomaly
+// with its value. X values match `wchart.TimeSeriesToContinuousSeries` for
+// series with `interval`.
+func AnomaliesToAnnotations(anomalies []timeseries.Anomaly, interval timeutil.Interval) (chartdraw.AnnotationSeries, error) {
+	annoSeries := chartdraw.AnnotationSeries{
+		Annotations: []chartdraw.Value2{},
+		Style: chartdraw.Style{
+			StrokeWidth: float64(2),
+			StrokeColor: wchart.MustParseColor("red")},
+	}
+	for _, a := range anomalies {
+		var xValue float64
+		switch interval {
+		case timeutil.IntervalMonth:
+			dtMC, err := month.TimeToMonthContinuous(a.Time)
+			if err != nil {
+				return annoSeries, err
+			}
+			xValue = float64(dtMC)
+		case timeutil.IntervalQuarter:
+			dtQC, err := quarter.TimeToQuarterContinuous(a.Time)
+			if err != nil {
+				return annoSeries, err
+			}
+			xValue = float64(dtQC)
+		default:
+			xValue = float64(a.Time.Unix())
+		}
+		annoSeries.Annotations = append(annoSeries.Annotations, chartdraw.Value2{
+			XValue: xValue,
+			YValue: a.Float64(),
+			Label:  "! " + anomalyValueLabel(a)})
+	}
+	return annoSeries, nil
+}
+
+// anomalyValueLabel abbreviates large values and shows float values below
+// 1,000 with up to two decimal places.
+func anomalyValueLabel(a timeseries.Anomaly) string {
+	if !a.IsFloat || math.Abs(a.ValueFloat) >= 1000 {
+		return strconvutil.Int64Abbreviation(int64(math.Round(a.Float64())))
+	}
+	return strings.TrimSuffix(strings.TrimRight(strconvutil.FormatDecimal(a.ValueFloat, 2), "0"), ".")
+}
@@ -0,0 +1,51 @@
+package sts2wchart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grokify/mogo/time/month"
+	"github.com/grokify/mogo/time/timeutil"
+
+	"github.com/grokify/gocharts/v2/data/timeseries"
+)
+
+func TestAnomaliesToAnnotations(t *testing.T) {
+	dt := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	anomalies := []timeseries.Anomaly{
+		{TimeItem: timeseries.TimeItem{Time: dt, IsFloat: true, ValueFloat: 0.375}},
+		{TimeItem: timeseries.TimeItem{Time: dt, IsFloat: true, ValueFloat: 12.5}},
+		{TimeItem: timeseries.TimeItem{Time: dt, IsFloat: true, ValueFloat: 2549.6}},
+		{TimeItem: timeseries.TimeItem{Time: dt, Value: 1500}},
+		{TimeItem: timeseries.TimeItem{Time: dt, Value: 42}},
+	}
+	wantLabels := []string{"! 0.38", "! 12.5", "! 2.5K", "! 1.5K", "! 42"}
+
+	annos, err := AnomaliesToAnnotations(anomalies, timeutil.IntervalMonth)
+	if err != nil {
+		t.Fatalf("AnomaliesToAnnotations() error: %v", err)
+	}
+	if len(annos.Annotations) != len(wantLabels) {
+		t.Fatalf("AnomaliesToAnnotations() count mismatch: want [%d] got [%d]", len(wantLabels), len(annos.Annotations))
+	}
+	wantX, err := month.TimeToMonthContinuous(dt)
+	if err != nil {
+		t.Fatalf("month.TimeToMonthContinuous() error: %v", err)
+	}
+	for i, anno := range annos.Annotations {
+		if anno.Label != wantLabels[i] {
+			t.Errorf("AnomaliesToAnnotations() label mismatch: want [%s] got [%s]", wantLabels[i], anno.Label)
+		}
+		if anno.XValue != float64(wantX) || anno.YValue != anomalies[i].Float64() {
+			t.Errorf("AnomaliesToAnnotations() value mismatch: want [%v, %v] got [%v, %v]", wantX, anomalies[i].Float64(), anno.XValue, anno.YValue)
+		}
+	}
+
+	annos, err = AnomaliesToAnnotations(anomalies[:1], timeutil.IntervalDay)
+	if err != nil {
+		t.Fatalf("AnomaliesToAnnotations() error: %v", err)
+	}
+	if got := annos.Annotations[0].XValue; got != float64(dt.Unix()) {
+		t.Errorf("AnomaliesToAnnotations() day x mismatch: want [%d] got [%v]", dt.Unix(), got)
+	}
+}
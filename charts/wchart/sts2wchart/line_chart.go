@@ -43,6 +43,7 @@ type LineChartOpts struct {
 	Height                      uint64
 	AspectRatio                 float64
 	Interval                    timeutil.Interval
+	Anomalies                   []timeseries.Anomaly // annotated on the chart, such as from `TimeSeriesSet.Anomalies`
 }
 
 func (opts *LineChartOpts) WantAnnotations() bool {
@@ -259,6 +260,13 @@ func TimeSeriesSetToLineChart(tset timeseries.TimeSeriesSet, opts *LineChartOpts
 			}
 		}
 	}
+	if len(opts.Anomalies) > 0 {
+		annoSeries, err := AnomaliesToAnnotations(opts.Anomalies, tset.Interval)
+		if err != nil {
+			return graph, err
+		}
+		graph.Series = append(graph.Series, annoSeries)
+	}
 	return graph, nil
 }
 
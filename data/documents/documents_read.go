@@ -4,12 +4,37 @@ import (
 	"io"
 	"os"
 
+	"github.com/grokify/gocharts/v2/data/table"
 	"github.com/grokify/mogo/encoding/csvutil"
 	"github.com/grokify/mogo/encoding/jsonutil"
 	"github.com/grokify/mogo/type/stringsutil"
 )
 
 func ReadMergeFilterCSVFiles(inPaths []string, outPath string, inComma rune, andFilter map[string]stringsutil.MatchInfo) (DocumentsSet, error) {
+	return readMergeCSVFiles(inPaths, inComma, func(csvHeader csvutil.CSVHeader) (func(line []string) (bool, error), error) {
+		return func(line []string) (bool, error) {
+			return csvHeader.RecordMatch(line, andFilter)
+		}, nil
+	})
+}
+
+// ReadMergeTableFilterCSVFiles reads CSV files into a `DocumentsSet` with the
+// records matching a `table.Filter`, such as one parsed with `table.ParseFilter`.
+func ReadMergeTableFilterCSVFiles(inPaths []string, inComma rune, filter table.Filter) (DocumentsSet, error) {
+	return readMergeCSVFiles(inPaths, inComma, func(csvHeader csvutil.CSVHeader) (func(line []string) (bool, error), error) {
+		match, err := filter.Matcher(csvHeader.Columns)
+		if err != nil {
+			return nil, err
+		}
+		return func(line []string) (bool, error) {
+			return match(line), nil
+		}, nil
+	})
+}
+
+// readMergeCSVFiles reads CSV files into a `DocumentsSet`. The `matcher` func
+// is called with the header of each file and returns the record filter.
+func readMergeCSVFiles(inPaths []string, inComma rune, matcher func(csvHeader csvutil.CSVHeader) (func(line []string) (bool, error), error)) (DocumentsSet, error) {
 	//data := JsonRecordsInfo{Records: []map[string]string{}}
 	data := NewDocumentsSet()
 
@@ -20,6 +45,7 @@ func ReadMergeFilterCSVFiles(inPaths []string, outPath string, inComma rune, and
 		}
 
 		csvHeader := csvutil.CSVHeader{}
+		var match func(line []string) (bool, error)
 		j := -1
 
 		for {
@@ -33,13 +59,16 @@ func ReadMergeFilterCSVFiles(inPaths []string, outPath string, inComma rune, and
 
 			if j == 0 {
 				csvHeader.Columns = line
+				if match, err = matcher(csvHeader); err != nil {
+					return data, err
+				}
 				continue
 			}
-			match, err := csvHeader.RecordMatch(line, andFilter)
+			ok, err := match(line)
 			if err != nil {
 				return data, err
 			}
-			if !match {
+			if !ok {
 				continue
 			}
 
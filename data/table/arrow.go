@@ -0,0 +1,391 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+const (
+	// ArrowMetadataKeySchema is the Arrow schema metadata key of the stored
+	// table `Schema`.
+	ArrowMetadataKeySchema = metadataKeySchema
+
+	ArrowExt = ".arrow"
+
+	defaultArrowBatchSize = 65536
+)
+
+// ArrowOptions configures Arrow IPC writing.
+type ArrowOptions struct {
+	BatchSize   int    // maximum rows per record batch, default 65,536
+	Compression string // "none" (default), "lz4" or "zstd"
+}
+
+// BatchSizeOrDefault returns the batch size, or the default if not set.
+func (opts *ArrowOptions) BatchSizeOrDefault() int {
+	if opts == nil || opts.BatchSize <= 0 {
+		return defaultArrowBatchSize
+	}
+	return opts.BatchSize
+}
+
+// IPCOptions returns the `ipc.Option` values for the compression.
+func (opts *ArrowOptions) IPCOptions() ([]ipc.Option, error) {
+	name := ""
+	if opts != nil {
+		name = strings.ToLower(strings.TrimSpace(opts.Compression))
+	}
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "lz4":
+		return []ipc.Option{ipc.WithLZ4()}, nil
+	case "zstd":
+		return []ipc.Option{ipc.WithZstd()}, nil
+	}
+	return nil, fmt.Errorf("unknown arrow compression [%s]", opts.Compression)
+}
+
+// ArrowSchema returns the Arrow schema for the table. Columns are typed by
+// `Schema`, using the same mapping as `WriteParquet`: ints as int64, floats
+// and percents as float64, decimals and currencies as decimal128(18,
+// precision), dates as date32, datetimes as UTC microsecond timestamps,
+// durations as nanosecond durations and other columns as strings. The table
+// `Schema` is stored in the metadata under `ArrowMetadataKeySchema`.
+func (tbl *Table) ArrowSchema() (*arrow.Schema, error) {
+	sch := tbl.typedSchema()
+	fields := make([]arrow.Field, len(sch.Columns))
+	for i, cs := range sch.Columns {
+		dt, err := arrowDataType(cs)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{Name: cs.Name, Type: dt, Nullable: true}
+	}
+	metadata, err := json.Marshal(sch)
+	if err != nil {
+		return nil, err
+	}
+	md := arrow.NewMetadata([]string{ArrowMetadataKeySchema}, []string{string(metadata)})
+	return arrow.NewSchema(fields, &md), nil
+}
+
+func arrowDataType(cs ColumnSchema) (arrow.DataType, error) {
+	switch cs.Type {
+	case ColumnTypeString, "":
+		return arrow.BinaryTypes.String, nil
+	case ColumnTypeInt:
+		return arrow.PrimitiveTypes.Int64, nil
+	case ColumnTypeDuration:
+		return arrow.FixedWidthTypes.Duration_ns, nil
+	case ColumnTypeFloat, ColumnTypePercent:
+		return arrow.PrimitiveTypes.Float64, nil
+	case ColumnTypeDecimal, ColumnTypeCurrency:
+		return &arrow.Decimal128Type{Precision: decimalDigits, Scale: int32(cs.precision())}, nil //nolint:gosec // G115: precision is small
+	case ColumnTypeBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case ColumnTypeDate:
+		return arrow.FixedWidthTypes.Date32, nil
+	case ColumnTypeDatetime:
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	}
+	return nil, fmt.Errorf("unknown column type [%s]", cs.Type)
+}
+
+// ArrowRecordBatch returns the table rows as an Arrow record batch with the
+// schema from `ArrowSchema`. Empty cells are written as nulls. The caller
+// must call `Release` on the record batch.
+func (tbl *Table) ArrowRecordBatch(mem memory.Allocator) (arrow.RecordBatch, error) {
+	schema, err := tbl.ArrowSchema()
+	if err != nil {
+		return nil, err
+	}
+	return tbl.arrowRecordBatch(mem, schema, 0, len(tbl.Rows))
+}
+
+func (tbl *Table) arrowRecordBatch(mem memory.Allocator, schema *arrow.Schema, start, end int) (arrow.RecordBatch, error) {
+	if tbl.IsFloat64 {
+		return nil, errors.New("cannot write float table as arrow")
+	}
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	sch := tbl.typedSchema()
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Reserve(end - start)
+	for y := start; y < end; y++ {
+		row := tbl.Rows[y]
+		for x := range sch.Columns {
+			val := ""
+			if x < len(row) {
+				val = row[x]
+			}
+			if err := appendArrowValue(b.Field(x), &sch.Columns[x], val); err != nil {
+				return nil, fmt.Errorf("row [%d] column [%s]: %w", y, sch.Columns[x].Name, err)
+			}
+		}
+	}
+	return b.NewRecordBatch(), nil
+}
+
+// appendArrowValue appends a cell to an Arrow builder for the column type.
+func appendArrowValue(b array.Builder, cs *ColumnSchema, val string) error {
+	v, err := cs.Parse(val)
+	if err != nil {
+		return err
+	} else if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	switch bb := b.(type) {
+	case *array.StringBuilder:
+		bb.Append(v.(string))
+	case *array.Int64Builder:
+		bb.Append(int64(v.(int)))
+	case *array.DurationBuilder:
+		bb.Append(arrow.Duration(v.(time.Duration)))
+	case *array.Float64Builder:
+		bb.Append(v.(float64))
+	case *array.Decimal128Builder:
+		unscaled, err := cs.unscaledDecimal(val)
+		if err != nil {
+			return err
+		}
+		bb.Append(decimal128.FromI64(unscaled))
+	case *array.BooleanBuilder:
+		bb.Append(v.(bool))
+	case *array.Date32Builder:
+		t := v.(time.Time)
+		bb.Append(arrow.Date32FromTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)))
+	case *array.TimestampBuilder:
+		bb.Append(arrow.Timestamp(v.(time.Time).UnixMicro()))
+	default:
+		return fmt.Errorf("unknown column type [%s]", cs.Type)
+	}
+	return nil
+}
+
+// FromArrow converts Arrow record batches with `schema` to a `Table`. Arrow
+// types are mapped to a `Schema`: integers to int, floats to float, decimals
+// to decimal, dates to date, timestamps to datetime, durations to duration
+// and booleans to bool. Other types are read as strings. A table `Schema`
+// stored under `ArrowMetadataKeySchema` is applied when compatible. Nulls are
+// read as empty strings.
+func FromArrow(schema *arrow.Schema, recs ...arrow.RecordBatch) (Table, error) {
+	tbl := NewTable("")
+	if schema == nil {
+		return tbl, errors.New("arrow schema cannot be nil")
+	}
+	var storedSchema *Schema
+	if v, ok := schema.Metadata().GetValue(ArrowMetadataKeySchema); ok {
+		storedSchema = parseStoredSchema(v)
+	}
+	tbl.Schema = &Schema{}
+	convs := make([]func(arr arrow.Array, i int) string, schema.NumFields())
+	for x, field := range schema.Fields() {
+		cs, conv := arrowColumnSchema(field.Type)
+		cs.Name = field.Name
+		if storedSchema != nil {
+			if scs := storedSchema.Column(field.Name); scs != nil {
+				var format func(string) string
+				if cs, format = storedColumnSchema(*scs, cs); format != nil {
+					fieldConv := conv
+					conv = func(arr arrow.Array, i int) string { return format(fieldConv(arr, i)) }
+				}
+			}
+		}
+		tbl.Columns = append(tbl.Columns, field.Name)
+		tbl.Schema.Columns = append(tbl.Schema.Columns, cs)
+		convs[x] = conv
+	}
+	for _, rec := range recs {
+		if !rec.Schema().Equal(schema) {
+			return tbl, errors.New("arrow record batch schema mismatch")
+		}
+		cols := rec.Columns()
+		for y := range int(rec.NumRows()) {
+			row := make([]string, len(cols))
+			for x, col := range cols {
+				if !col.IsNull(y) {
+					row[x] = convs[x](col, y)
+				}
+			}
+			tbl.Rows = append(tbl.Rows, row)
+		}
+	}
+	return tbl, nil
+}
+
+// arrowColumnSchema returns the column schema and value formatter for an
+// Arrow data type. Formatters are only called for non-null values.
+func arrowColumnSchema(dt arrow.DataType) (ColumnSchema, func(arrow.Array, int) string) {
+	switch t := dt.(type) {
+	case *arrow.BooleanType:
+		return ColumnSchema{Type: ColumnTypeBool}, func(arr arrow.Array, i int) string {
+			return strconv.FormatBool(arr.(*array.Boolean).Value(i))
+		}
+	case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type:
+		return ColumnSchema{Type: ColumnTypeInt}, func(arr arrow.Array, i int) string {
+			return arr.ValueStr(i)
+		}
+	case *arrow.Float32Type:
+		return ColumnSchema{Type: ColumnTypeFloat}, func(arr arrow.Array, i int) string {
+			return strconv.FormatFloat(float64(arr.(*array.Float32).Value(i)), 'f', -1, 32)
+		}
+	case *arrow.Float64Type:
+		return ColumnSchema{Type: ColumnTypeFloat}, func(arr arrow.Array, i int) string {
+			return strconv.FormatFloat(arr.(*array.Float64).Value(i), 'f', -1, 64)
+		}
+	case *arrow.Decimal128Type:
+		scale := int(t.Scale)
+		return ColumnSchema{Type: ColumnTypeDecimal, Precision: &scale}, func(arr arrow.Array, i int) string {
+			return arr.(*array.Decimal128).Value(i).ToString(t.Scale)
+		}
+	case *arrow.Date32Type:
+		return ColumnSchema{Type: ColumnTypeDate}, func(arr arrow.Array, i int) string {
+			return arr.(*array.Date32).Value(i).ToTime().Format(time.DateOnly)
+		}
+	case *arrow.Date64Type:
+		return ColumnSchema{Type: ColumnTypeDate}, func(arr arrow.Array, i int) string {
+			return arr.(*array.Date64).Value(i).ToTime().Format(time.DateOnly)
+		}
+	case *arrow.TimestampType:
+		return ColumnSchema{Type: ColumnTypeDatetime}, func(arr arrow.Array, i int) string {
+			return arr.(*array.Timestamp).Value(i).ToTime(t.Unit).Format(time.RFC3339Nano)
+		}
+	case *arrow.DurationType:
+		return ColumnSchema{Type: ColumnTypeDuration}, func(arr arrow.Array, i int) string {
+			return (time.Duration(arr.(*array.Duration).Value(i)) * t.Unit.Multiplier()).String()
+		}
+	}
+	return ColumnSchema{Type: ColumnTypeString}, func(arr arrow.Array, i int) string {
+		return arr.ValueStr(i)
+	}
+}
+
+// WriteArrowIPC writes the table in the Arrow IPC file format, also known as
+// Feather V2, in record batches of up to `ArrowOptions.BatchSize` rows.
+func (tbl *Table) WriteArrowIPC(w io.Writer, opts *ArrowOptions) error {
+	schema, err := tbl.ArrowSchema()
+	if err != nil {
+		return err
+	}
+	ipcOpts, err := opts.IPCOptions()
+	if err != nil {
+		return err
+	}
+	fw, err := ipc.NewFileWriter(w, append(ipcOpts, ipc.WithSchema(schema))...)
+	if err != nil {
+		return err
+	}
+	batchSize := opts.BatchSizeOrDefault()
+	for start := 0; start < len(tbl.Rows); start += batchSize {
+		rec, err := tbl.arrowRecordBatch(memory.DefaultAllocator, schema, start, min(start+batchSize, len(tbl.Rows)))
+		if err != nil {
+			fw.Close()
+			return err
+		}
+		err = fw.Write(rec)
+		rec.Release()
+		if err != nil {
+			fw.Close()
+			return err
+		}
+	}
+	return fw.Close()
+}
+
+// WriteFileArrow writes the table to an Arrow IPC file.
+func (tbl *Table) WriteFileArrow(filename string, opts *ArrowOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := tbl.WriteArrowIPC(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteFilesArrow writes each table to an Arrow IPC file in `dir` named after
+// the table, such as "Sales.arrow".
+func (ts *TableSet) WriteFilesArrow(dir string, opts *ArrowOptions) error {
+	for name, tbl := range ts.TableMap {
+		filename := filepath.Join(dir, tableFileName(name)+ArrowExt)
+		if err := tbl.WriteFileArrow(filename, opts); err != nil {
+			return fmt.Errorf("table [%s]: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ReadArrowIPC reads an Arrow IPC file into a `Table`. See `FromArrow`.
+func ReadArrowIPC(r ipc.ReadAtSeeker) (Table, error) {
+	fr, err := ipc.NewFileReader(r)
+	if err != nil {
+		return Table{}, err
+	}
+	defer fr.Close()
+	recs := make([]arrow.RecordBatch, 0, fr.NumRecords())
+	defer func() {
+		for _, rec := range recs {
+			rec.Release()
+		}
+	}()
+	for i := range fr.NumRecords() {
+		rec, err := fr.RecordBatch(i)
+		if err != nil {
+			return Table{}, err
+		}
+		rec.Retain()
+		recs = append(recs, rec)
+	}
+	return FromArrow(fr.Schema(), recs...)
+}
+
+// ReadFileArrow reads an Arrow IPC file into a `Table` named after the file.
+func ReadFileArrow(filename string) (Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Table{}, err
+	}
+	tbl, err := ReadArrowIPC(f)
+	if err != nil {
+		f.Close()
+		return tbl, err
+	}
+	tbl.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return tbl, f.Close()
+}
+
+// ReadFilesArrow reads Arrow IPC files into a `TableSet`, one table per file,
+// named after the file.
+func ReadFilesArrow(filenames ...string) (*TableSet, error) {
+	ts := NewTableSet("")
+	for _, filename := range filenames {
+		tbl, err := ReadFileArrow(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := ts.Add(&tbl); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
@@ -0,0 +1,60 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestArrowRoundTrip(t *testing.T) {
+	tbl := NewTable("Sales")
+	tbl.Columns = []string{"region", "day", "units", "revenue", "margin", "open"}
+	tbl.Rows = [][]string{
+		{"West", "2024-01-15", "12", "1200.50", "25%", "true"},
+		{"East", "2024-02-01", "", "300.00", "10%", "false"},
+		{"North", "2024-03-10", "7", "", "", ""},
+		{"South", "2024-03-11", "", "-9999999999999999.99", "", ""},
+	}
+	tbl.Schema = &Schema{Columns: []ColumnSchema{
+		{Name: "region", Type: ColumnTypeString},
+		{Name: "day", Type: ColumnTypeDate},
+		{Name: "units", Type: ColumnTypeInt},
+		{Name: "revenue", Type: ColumnTypeCurrency},
+		{Name: "margin", Type: ColumnTypePercent},
+		{Name: "open", Type: ColumnTypeBool},
+	}}
+
+	rec, err := tbl.ArrowRecordBatch(memory.NewGoAllocator())
+	if err != nil {
+		t.Fatalf("Table.ArrowRecordBatch() error: [%v]", err)
+	}
+	defer rec.Release()
+	if rec.NumRows() != 4 || rec.Column(2).DataType().ID() != arrow.INT64 || rec.Column(2).NullN() != 2 {
+		t.Errorf("Table.ArrowRecordBatch() mismatch: want [4 rows int64 2 null] got [%d rows %s %d null]",
+			rec.NumRows(), rec.Column(2).DataType(), rec.Column(2).NullN())
+	}
+
+	var buf bytes.Buffer
+	if err := tbl.WriteArrowIPC(&buf, &ArrowOptions{BatchSize: 2, Compression: "zstd"}); err != nil {
+		t.Fatalf("Table.WriteArrowIPC() error: [%v]", err)
+	}
+	tbl2, err := ReadArrowIPC(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadArrowIPC() error: [%v]", err)
+	}
+	if len(tbl2.Rows) != len(tbl.Rows) {
+		t.Fatalf("ReadArrowIPC() rows mismatch: want [%d] got [%d]", len(tbl.Rows), len(tbl2.Rows))
+	}
+	for y, row := range tbl.Rows {
+		for x, want := range row {
+			if got := tbl2.Rows[y][x]; got != want {
+				t.Errorf("ReadArrowIPC() cell [%d,%d] mismatch: want [%s] got [%s]", x, y, want, got)
+			}
+		}
+	}
+	if cs := tbl2.Schema.Column("revenue"); cs == nil || cs.Type != ColumnTypeCurrency {
+		t.Errorf("ReadArrowIPC() schema mismatch: want [%s] got [%v]", ColumnTypeCurrency, cs)
+	}
+}
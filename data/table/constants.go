@@ -1,13 +1,14 @@
 package table
 
 const (
-	FormatDate    = "date"
-	FormatFloat   = "float"
-	FormatInt     = "int"
-	FormatPercent = "percent"
-	FormatString  = "string"
-	FormatTime    = "time"
-	FormatURL     = "url"
+	FormatCurrency = "currency"
+	FormatDate     = "date"
+	FormatFloat    = "float"
+	FormatInt      = "int"
+	FormatPercent  = "percent"
+	FormatString   = "string"
+	FormatTime     = "time"
+	FormatURL      = "url"
 
 	StyleSimple = "border:1px solid #000;border-collapse:collapse"
 )
@@ -0,0 +1,97 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/grokify/gocharts/v2/data/table/expr"
+)
+
+// AddExprColumn appends a column computed from an expression over each row,
+// such as `revenue / users * 100` or `if(status == "200", "ok", "err")`. See
+// the `expr` package for the expression syntax and functions.
+func (tbl *Table) AddExprColumn(colName, expression string) error {
+	if tbl.IsFloat64 {
+		return errors.New("cannot evaluate expressions on float table")
+	}
+	if tbl.Columns.Index(colName) >= 0 {
+		return fmt.Errorf("column name already exists [%s]", colName)
+	}
+	e, vars, err := tbl.parseExpr(expression)
+	if err != nil {
+		return err
+	}
+	vals := make([]string, len(tbl.Rows))
+	for y, row := range tbl.Rows {
+		vars.row = row
+		if vals[y], err = e.EvalString(vars); err != nil {
+			return fmt.Errorf("row [%d]: %w", y, err)
+		}
+	}
+	colIdx := len(tbl.Columns)
+	tbl.Columns = append(tbl.Columns, colName)
+	for y, row := range tbl.Rows {
+		for len(row) < colIdx {
+			row = append(row, "")
+		}
+		tbl.Rows[y] = append(row, vals[y])
+	}
+	return nil
+}
+
+// FilterExpr returns a Table with the rows for which the expression is true,
+// such as `region == "EMEA" and revenue > 1000`.
+func (tbl *Table) FilterExpr(expression string) (*Table, error) {
+	if tbl.IsFloat64 {
+		return nil, errors.New("cannot evaluate expressions on float table")
+	}
+	e, vars, err := tbl.parseExpr(expression)
+	if err != nil {
+		return nil, err
+	}
+	out := tbl.Clone(false)
+	for y, row := range tbl.Rows {
+		vars.row = row
+		if ok, err := e.EvalBool(vars); err != nil {
+			return nil, fmt.Errorf("row [%d]: %w", y, err)
+		} else if ok {
+			out.Rows = append(out.Rows, slices.Clone(row))
+		}
+	}
+	return out, nil
+}
+
+// parseExpr parses an expression and checks that its columns exist.
+func (tbl *Table) parseExpr(expression string) (*expr.Expr, *rowVars, error) {
+	e, err := expr.Parse(expression)
+	if err != nil {
+		return nil, nil, err
+	}
+	vars := &rowVars{colIdxs: map[string]int{}}
+	for _, colName := range e.Columns() {
+		colIdx := tbl.Columns.Index(colName)
+		if colIdx < 0 {
+			return nil, nil, fmt.Errorf("column not found [%s]", colName)
+		}
+		vars.colIdxs[colName] = colIdx
+	}
+	return e, vars, nil
+}
+
+// rowVars provides the values of a row to an expression. Cells missing from
+// short rows are empty.
+type rowVars struct {
+	colIdxs map[string]int
+	row     []string
+}
+
+func (v *rowVars) Value(name string) (string, bool) {
+	colIdx, ok := v.colIdxs[name]
+	if !ok {
+		return "", false
+	} else if colIdx < len(v.row) {
+		return v.row[colIdx], true
+	}
+	return "", true
+}
@@ -0,0 +1,316 @@
+package expr
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vars provides column values for evaluation.
+type Vars interface {
+	Value(name string) (string, bool)
+}
+
+// MapVars is a `Vars` backed by a map of column names to values.
+type MapVars map[string]string
+
+// Value returns the value for a column name.
+func (m MapVars) Value(name string) (string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// Eval evaluates the expression. The result is `nil`, `float64`, `string`,
+// `bool` or `time.Time`. Column values are strings which are converted as
+// needed: arithmetic parses numbers and treats empty values as null, and null
+// operands or division by zero give a null result.
+func (e *Expr) Eval(vars Vars) (any, error) {
+	return e.root.eval(vars)
+}
+
+// EvalString evaluates the expression and formats the result with `FormatValue`.
+func (e *Expr) EvalString(vars Vars) (string, error) {
+	v, err := e.Eval(vars)
+	if err != nil {
+		return "", err
+	}
+	return FormatValue(v), nil
+}
+
+// EvalBool evaluates the expression and returns its truth value with `Truthy`.
+func (e *Expr) EvalBool(vars Vars) (bool, error) {
+	v, err := e.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return Truthy(v), nil
+}
+
+// FormatValue formats an evaluation result as a cell value. Null is formatted
+// as an empty string, whole numbers without decimals and times at midnight UTC
+// as `time.DateOnly`.
+func FormatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		if val.Location() == time.UTC && val.Equal(val.Truncate(24*time.Hour)) {
+			return val.Format(time.DateOnly)
+		}
+		return val.Format(time.RFC3339)
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// Truthy returns the truth value of an evaluation result. Null, false, zero,
+// the zero time, empty strings and strings parsed as false by
+// `strconv.ParseBool` are false.
+func Truthy(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case time.Time:
+		return !val.IsZero()
+	case string:
+		val = strings.TrimSpace(val)
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+		return val != ""
+	default:
+		return true
+	}
+}
+
+// toNumber converts a value to a number. It returns `ok` as false for null
+// and empty values.
+func toNumber(v any) (f float64, ok bool, err error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return val, true, nil
+	case bool:
+		if val {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	case string:
+		val = strings.TrimSpace(val)
+		if val == "" {
+			return 0, false, nil
+		}
+		f, err := strconv.ParseFloat(strings.ReplaceAll(val, ",", ""), 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("cannot convert %q to number", val)
+		}
+		return f, true, nil
+	default:
+		return 0, false, fmt.Errorf("cannot convert %v to number", v)
+	}
+}
+
+// toTime converts a value to a time, parsing strings as `time.DateOnly`,
+// `time.RFC3339` or `time.DateTime`. It returns `ok` as false for null and
+// empty values.
+func toTime(v any) (t time.Time, ok bool, err error) {
+	switch val := v.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return val, true, nil
+	case string:
+		val = strings.TrimSpace(val)
+		if val == "" {
+			return time.Time{}, false, nil
+		}
+		for _, layout := range []string{time.DateOnly, time.RFC3339, time.DateTime} {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t, true, nil
+			}
+		}
+		return time.Time{}, false, fmt.Errorf("cannot convert %q to date", val)
+	default:
+		return time.Time{}, false, fmt.Errorf("cannot convert %v to date", v)
+	}
+}
+
+func isNull(v any) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// compareValues compares two non-null values as numbers if both are numeric,
+// as times if either is a time, and otherwise as strings.
+func compareValues(a, b any) int {
+	if fa, ok, err := toNumber(a); ok && err == nil {
+		if fb, ok, err := toNumber(b); ok && err == nil {
+			return cmp.Compare(fa, fb)
+		}
+	}
+	_, aTime := a.(time.Time)
+	_, bTime := b.(time.Time)
+	if aTime || bTime {
+		if ta, ok, err := toTime(a); ok && err == nil {
+			if tb, ok, err := toTime(b); ok && err == nil {
+				return ta.Compare(tb)
+			}
+		}
+	}
+	return strings.Compare(FormatValue(a), FormatValue(b))
+}
+
+// equalValues reports whether two values are both null or compare as equal.
+func equalValues(a, b any) bool {
+	return isNull(a) && isNull(b) ||
+		!isNull(a) && !isNull(b) && compareValues(a, b) == 0
+}
+
+func (n *literalNode) eval(Vars) (any, error) {
+	return n.val, nil
+}
+
+func (n *columnNode) eval(vars Vars) (any, error) {
+	if vars == nil {
+		return nil, fmt.Errorf("column not found [%s]", n.name)
+	}
+	v, ok := vars.Value(n.name)
+	if !ok {
+		return nil, fmt.Errorf("column not found [%s]", n.name)
+	}
+	return v, nil
+}
+
+func (n *unaryNode) eval(vars Vars) (any, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !Truthy(v), nil
+	}
+	f, ok, err := toNumber(v)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if n.op == "-" {
+		return -f, nil
+	}
+	return f, nil
+}
+
+func (n *binaryNode) eval(vars Vars) (any, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "&&":
+		if !Truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(vars)
+		return Truthy(right), err
+	case "||":
+		if Truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(vars)
+		return Truthy(right), err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		return equalValues(left, right) == (n.op == "=="), nil
+	case "<", "<=", ">", ">=":
+		if isNull(left) || isNull(right) {
+			return false, nil
+		}
+		c := compareValues(left, right)
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+
+	a, aOK, err := toNumber(left)
+	if err != nil {
+		return nil, fmt.Errorf("operator %s: %w", n.op, err)
+	}
+	b, bOK, err := toNumber(right)
+	if err != nil {
+		return nil, fmt.Errorf("operator %s: %w", n.op, err)
+	}
+	if !aOK || !bOK {
+		return nil, nil
+	}
+	switch n.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, nil
+		}
+		return a / b, nil
+	case "%":
+		if b == 0 {
+			return nil, nil
+		}
+		return math.Mod(a, b), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func (n *callNode) eval(vars Vars) (any, error) {
+	var v any
+	var err error
+	if n.fn.lazy != nil {
+		v, err = n.fn.lazy(vars, n.args)
+	} else {
+		args := make([]any, len(n.args))
+		for i, arg := range n.args {
+			if args[i], err = arg.eval(vars); err != nil {
+				return nil, err
+			}
+		}
+		v, err = n.fn.call(args)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", n.name, err)
+	}
+	return v, nil
+}
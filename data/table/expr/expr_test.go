@@ -0,0 +1,108 @@
+package expr
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+var evalTests = []struct {
+	expr string
+	want string
+}{
+	{`revenue / users * 100`, "25"},
+	{`[Unit Price] * 2 + 1`, "5.5"},
+	{`-(2 + 3) % 3`, "-2"},
+	{`revenue / zero`, ""},
+	{`if(status == "200", "ok", "err")`, "ok"},
+	{`if(status <> 200 or users > 5000, "err", "ok")`, "ok"},
+	{`not isempty(empty) || upper(substr(name, 2, 3)) == "LIC"`, "true"},
+	{`concat(lower(name), "-", len(name))`, "alice-5"},
+	{`coalesce(empty, name)`, "Alice"},
+	{`round(revenue / 3, 2)`, "83.33"},
+	{`datediff(dateadd(day, 1, "month"), "2024-01-31")`, "29"},
+	{`dateformat(date("03/02/2024", "01/02/2006"), "Jan 2")`, "Mar 2"},
+	{`year(day) * 100 + month(day)`, "202401"},
+	{`in(status, "404", 200) and not in(empty, "x")`, "true"},
+	{`between(users, 1000, null) and not between(day, null, date("2024-01-30"))`, "true"},
+	{`matches(name, "^A.i") and not matches(empty, ".")`, "true"},
+}
+
+func TestEval(t *testing.T) {
+	vars := MapVars{
+		"revenue":    "250",
+		"users":      "1,000",
+		"zero":       "0",
+		"status":     "200",
+		"name":       "Alice",
+		"empty":      "",
+		"day":        "2024-01-31",
+		"Unit Price": "2.25",
+	}
+	for _, tt := range evalTests {
+		e, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%s) error: [%v]", tt.expr, err)
+		}
+		got, err := e.EvalString(vars)
+		if err != nil {
+			t.Fatalf("Expr.EvalString(%s) error: [%v]", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Expr.EvalString(%s) mismatch: want [%s] got [%s]", tt.expr, tt.want, got)
+		}
+	}
+	cols := MustParse(`if(status == "200", revenue, [Unit Price] + revenue)`).Columns()
+	if want := []string{"status", "revenue", "Unit Price"}; !slices.Equal(cols, want) {
+		t.Errorf("Expr.Columns() mismatch: want [%v] got [%v]", want, cols)
+	}
+}
+
+var parseErrorTests = []struct {
+	expr string
+	pos  int
+}{
+	{`revenue / `, 10},
+	{`revenue = 1`, 8},
+	{`(a + b`, 6},
+	{`foo(a)`, 0},
+	{`if(a, b)`, 0},
+	{`"abc`, 0},
+	{`a b`, 2},
+}
+
+func TestParseError(t *testing.T) {
+	for _, tt := range parseErrorTests {
+		_, err := Parse(tt.expr)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("Parse(%s) expected ParseError, got [%v]", tt.expr, err)
+		}
+		if perr.Pos != tt.pos {
+			t.Errorf("Parse(%s) position mismatch: want [%d] got [%d] (%v)", tt.expr, tt.pos, perr.Pos, err)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	e := MustParse(`not in([Unit Price], 'a"b', -1) || x.y >= null`)
+	root := e.Root()
+	if root.Kind != NodeBinary || root.Op != "||" || len(root.Args) != 2 {
+		t.Fatalf("Expr.Root() mismatch: got [%v]", root)
+	}
+	if call := root.Args[0].Args[0]; call.Kind != NodeCall || call.Name != "in" || call.Args[0].Name != "Unit Price" {
+		t.Errorf("Expr.Root() call mismatch: got [%v]", call)
+	}
+	want := `!in([Unit Price], "a\"b", -1) || (x.y >= null)`
+	if got := root.String(); got != want {
+		t.Errorf("Node.String() mismatch: want [%s] got [%s]", want, got)
+	}
+	if got := MustParse(root.String()).Root().String(); got != want {
+		t.Errorf("Node.String() round trip mismatch: want [%s] got [%s]", want, got)
+	}
+	for name, want := range map[string]string{"revenue": "revenue", "_a.1": "_a.1", "Unit Price": "[Unit Price]", "1st": "[1st]", "Or": "[Or]", "": "[]"} {
+		if got := QuoteColumn(name); got != want {
+			t.Errorf("QuoteColumn(%s) mismatch: want [%s] got [%s]", name, want, got)
+		}
+	}
+}
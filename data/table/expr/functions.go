@@ -0,0 +1,319 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type function struct {
+	minArgs int
+	maxArgs int // -1 for variadic
+	call    func(args []any) (any, error)
+	lazy    func(vars Vars, args []node) (any, error) // evaluates its own arguments
+}
+
+func (fn function) arity() string {
+	switch {
+	case fn.minArgs == 1 && fn.maxArgs == 1:
+		return "1 argument"
+	case fn.minArgs == fn.maxArgs:
+		return fmt.Sprintf("%d arguments", fn.minArgs)
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", fn.minArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", fn.minArgs, fn.maxArgs)
+	}
+}
+
+// Functions returns the names of the supported functions:
+//
+//   - Numeric: abs(x), ceil(x), floor(x), round(x[, digits]), sqrt(x),
+//     pow(x, y), min(x, ...), max(x, ...), number(x).
+//   - String: concat(s, ...), contains(s, substr), endswith(s, suffix),
+//     len(s), lower(s), matches(s, pattern) with a Go regular expression,
+//     replace(s, old, new), startswith(s, prefix), string(x),
+//     substr(s, start[, length]) with 1-based start, trim(s), upper(s).
+//   - Date: date(s[, layout]) with a Go time layout, dateadd(d, n[, unit])
+//     with unit "day" (default), "month" or "year" clamped to month end,
+//     datediff(a, b) in days, dateformat(d, layout), day(d), month(d),
+//     weekday(d) with Sunday as 0, year(d).
+//   - Conditional: between(x, lo, hi) with inclusive bounds and null
+//     unbounded, coalesce(x, ...) returns the first non-empty value,
+//     if(cond, then, else) evaluates only the chosen branch, in(x, value, ...)
+//     compares like `==`, isempty(x).
+//
+// Numeric and date functions return null for null or empty arguments.
+func Functions() []string {
+	var names []string
+	for name := range functions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"abs":   numFunc1(math.Abs),
+		"ceil":  numFunc1(math.Ceil),
+		"floor": numFunc1(math.Floor),
+		"sqrt":  numFunc1(math.Sqrt),
+		"round": {minArgs: 1, maxArgs: 2, call: func(args []any) (any, error) {
+			nums, ok, err := numbers(args)
+			if err != nil || !ok {
+				return nil, err
+			}
+			digits := 0.0
+			if len(nums) > 1 {
+				digits = nums[1]
+			}
+			p := math.Pow(10, digits)
+			return math.Round(nums[0]*p) / p, nil
+		}},
+		"pow": {minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+			nums, ok, err := numbers(args)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return math.Pow(nums[0], nums[1]), nil
+		}},
+		"min": {minArgs: 1, maxArgs: -1, call: func(args []any) (any, error) {
+			nums, ok, err := numbers(args)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return slices.Min(nums), nil
+		}},
+		"max": {minArgs: 1, maxArgs: -1, call: func(args []any) (any, error) {
+			nums, ok, err := numbers(args)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return slices.Max(nums), nil
+		}},
+		"number": numFunc1(func(f float64) float64 { return f }),
+
+		"concat": {minArgs: 1, maxArgs: -1, call: func(args []any) (any, error) {
+			var b strings.Builder
+			for _, arg := range args {
+				b.WriteString(FormatValue(arg))
+			}
+			return b.String(), nil
+		}},
+		"contains":   strFunc2(func(s, t string) any { return strings.Contains(s, t) }),
+		"endswith":   strFunc2(func(s, t string) any { return strings.HasSuffix(s, t) }),
+		"startswith": strFunc2(func(s, t string) any { return strings.HasPrefix(s, t) }),
+		"len": strFunc1(func(s string) any {
+			return float64(utf8.RuneCountInString(s))
+		}),
+		"matches": {minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+			rx, err := compileRegexp(FormatValue(args[1]))
+			if err != nil {
+				return nil, err
+			}
+			return rx.MatchString(FormatValue(args[0])), nil
+		}},
+		"lower":  strFunc1(func(s string) any { return strings.ToLower(s) }),
+		"upper":  strFunc1(func(s string) any { return strings.ToUpper(s) }),
+		"trim":   strFunc1(func(s string) any { return strings.TrimSpace(s) }),
+		"string": strFunc1(func(s string) any { return s }),
+		"replace": {minArgs: 3, maxArgs: 3, call: func(args []any) (any, error) {
+			return strings.ReplaceAll(FormatValue(args[0]), FormatValue(args[1]), FormatValue(args[2])), nil
+		}},
+		"substr": {minArgs: 2, maxArgs: 3, call: func(args []any) (any, error) {
+			runes := []rune(FormatValue(args[0]))
+			start, ok, err := toNumber(args[1])
+			if err != nil || !ok {
+				return nil, err
+			}
+			from := min(max(int(start)-1, 0), len(runes))
+			to := len(runes)
+			if len(args) > 2 {
+				length, ok, err := toNumber(args[2])
+				if err != nil || !ok {
+					return nil, err
+				}
+				to = min(from+max(int(length), 0), len(runes))
+			}
+			return string(runes[from:to]), nil
+		}},
+
+		"date": {minArgs: 1, maxArgs: 2, call: func(args []any) (any, error) {
+			if len(args) == 1 {
+				t, ok, err := toTime(args[0])
+				if err != nil || !ok {
+					return nil, err
+				}
+				return t, nil
+			}
+			s := strings.TrimSpace(FormatValue(args[0]))
+			if s == "" {
+				return nil, nil
+			}
+			return time.Parse(FormatValue(args[1]), s)
+		}},
+		"dateadd": {minArgs: 2, maxArgs: 3, call: func(args []any) (any, error) {
+			t, ok, err := toTime(args[0])
+			if err != nil || !ok {
+				return nil, err
+			}
+			n, ok, err := toNumber(args[1])
+			if err != nil || !ok {
+				return nil, err
+			}
+			unit := "day"
+			if len(args) > 2 {
+				unit = strings.ToLower(FormatValue(args[2]))
+			}
+			switch unit {
+			case "day", "days":
+				return t.AddDate(0, 0, int(n)), nil
+			case "month", "months":
+				return addMonths(t, int(n)), nil
+			case "year", "years":
+				return addMonths(t, int(n)*12), nil
+			}
+			return nil, fmt.Errorf("unknown unit %q", unit)
+		}},
+		"datediff": {minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+			a, aOK, err := toTime(args[0])
+			if err != nil {
+				return nil, err
+			}
+			b, bOK, err := toTime(args[1])
+			if err != nil || !aOK || !bOK {
+				return nil, err
+			}
+			return a.Sub(b).Hours() / 24, nil
+		}},
+		"dateformat": {minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+			t, ok, err := toTime(args[0])
+			if err != nil || !ok {
+				return nil, err
+			}
+			return t.Format(FormatValue(args[1])), nil
+		}},
+		"year":    dateFunc1(func(t time.Time) float64 { return float64(t.Year()) }),
+		"month":   dateFunc1(func(t time.Time) float64 { return float64(t.Month()) }),
+		"day":     dateFunc1(func(t time.Time) float64 { return float64(t.Day()) }),
+		"weekday": dateFunc1(func(t time.Time) float64 { return float64(t.Weekday()) }),
+
+		"isempty": {minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+			return isNull(args[0]), nil
+		}},
+		"in": {minArgs: 2, maxArgs: -1, call: func(args []any) (any, error) {
+			return slices.ContainsFunc(args[1:], func(v any) bool { return equalValues(args[0], v) }), nil
+		}},
+		"between": {minArgs: 3, maxArgs: 3, call: func(args []any) (any, error) {
+			if isNull(args[0]) {
+				return false, nil
+			}
+			return (isNull(args[1]) || compareValues(args[0], args[1]) >= 0) &&
+				(isNull(args[2]) || compareValues(args[0], args[2]) <= 0), nil
+		}},
+		"coalesce": {minArgs: 1, maxArgs: -1, lazy: func(vars Vars, args []node) (any, error) {
+			for _, arg := range args {
+				v, err := arg.eval(vars)
+				if err != nil {
+					return nil, err
+				}
+				if !isNull(v) {
+					return v, nil
+				}
+			}
+			return nil, nil
+		}},
+		"if": {minArgs: 3, maxArgs: 3, lazy: func(vars Vars, args []node) (any, error) {
+			cond, err := args[0].eval(vars)
+			if err != nil {
+				return nil, err
+			}
+			if Truthy(cond) {
+				return args[1].eval(vars)
+			}
+			return args[2].eval(vars)
+		}},
+	}
+}
+
+// regexps caches compiled `matches` patterns, which are usually literals
+// evaluated once per row.
+var regexps sync.Map
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if rx, ok := regexps.Load(pattern); ok {
+		return rx.(*regexp.Regexp), nil
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexps.Store(pattern, rx)
+	return rx, nil
+}
+
+// addMonths adds months to a time, clamping the day to the end of the month
+// so that 2024-01-31 plus one month is 2024-02-29.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// numbers converts all arguments to numbers. It returns `ok` as false if any
+// argument is null or empty.
+func numbers(args []any) ([]float64, bool, error) {
+	nums := make([]float64, len(args))
+	for i, arg := range args {
+		f, ok, err := toNumber(arg)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		nums[i] = f
+	}
+	if len(nums) == 0 {
+		return nil, false, errors.New("no arguments")
+	}
+	return nums, true, nil
+}
+
+func numFunc1(fn func(float64) float64) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+		f, ok, err := toNumber(args[0])
+		if err != nil || !ok {
+			return nil, err
+		}
+		return fn(f), nil
+	}}
+}
+
+func strFunc1(fn func(string) any) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+		return fn(FormatValue(args[0])), nil
+	}}
+}
+
+func strFunc2(fn func(s, t string) any) function {
+	return function{minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+		return fn(FormatValue(args[0]), FormatValue(args[1])), nil
+	}}
+}
+
+func dateFunc1(fn func(time.Time) float64) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+		t, ok, err := toTime(args[0])
+		if err != nil || !ok {
+			return nil, err
+		}
+		return fn(t), nil
+	}}
+}
@@ -0,0 +1,200 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string // operator, identifier or literal value
+	pos  int    // byte offset in the expression
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// ParseError describes an invalid expression, including the byte position of
+// the error.
+type ParseError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("expr: %s at position %d in [%s]", e.Msg, e.Pos, e.Expr)
+}
+
+// twoCharOps are operators of two characters, checked before single characters.
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||", "<>"}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			toks = append(toks, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{kind: tokString, text: s, pos: i})
+			i += n
+		case c == '[':
+			end := strings.IndexByte(src[i:], ']')
+			if end < 0 {
+				return nil, &ParseError{Expr: src, Pos: i, Msg: "unterminated column name"}
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i+1 : i+end], pos: i})
+			i += end + 1
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+					i++
+				}
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], pos: start})
+		case c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			start := i
+			for i < len(src) {
+				r := rune(src[i])
+				if r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r) || src[i] >= 0x80 {
+					i++
+				} else {
+					break
+				}
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			op := ""
+			for _, two := range twoCharOps {
+				if strings.HasPrefix(src[i:], two) {
+					op = two
+					break
+				}
+			}
+			if op == "" && strings.ContainsRune("+-*/%<>!=", rune(c)) {
+				op = string(c)
+			}
+			if op == "" || op == "=" {
+				return nil, &ParseError{Expr: src, Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexString reads a quoted string starting at `src[start]` and returns the
+// unescaped value and the number of bytes consumed.
+func lexString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if i+1 >= len(src) {
+				return "", 0, &ParseError{Expr: src, Pos: i, Msg: "unterminated escape"}
+			}
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(src[i])
+			}
+		case quote:
+			return b.String(), i - start + 1, nil
+		default:
+			b.WriteByte(src[i])
+		}
+	}
+	return "", 0, &ParseError{Expr: src, Pos: start, Msg: "unterminated string"}
+}
+
+// QuoteString returns `s` as a double-quoted string literal.
+func QuoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteColumn returns the column name as a bare identifier if possible and
+// otherwise in square brackets.
+func QuoteColumn(name string) string {
+	switch strings.ToLower(name) {
+	case "", "and", "or", "not", "true", "false", "null":
+		return "[" + name + "]"
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80 ||
+			i > 0 && (c == '.' || unicode.IsDigit(rune(c)))) {
+			return "[" + name + "]"
+		}
+	}
+	return name
+}
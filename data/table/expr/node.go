@@ -0,0 +1,85 @@
+package expr
+
+import (
+	"strings"
+)
+
+// NodeKind is the kind of an expression tree node.
+type NodeKind int
+
+const (
+	NodeLiteral NodeKind = iota
+	NodeColumn
+	NodeUnary
+	NodeBinary
+	NodeCall
+)
+
+// Node is a read-only view of a parsed expression tree, for callers which
+// translate expressions into another form, such as `table.ParseFilter`.
+type Node struct {
+	Kind  NodeKind
+	Op    string // normalized operator of unary and binary nodes, such as "!" or "&&"
+	Name  string // column name or lowercase function name
+	Value any    // literal value: nil, bool, float64 or string
+	Args  []Node // operand, left and right operands, or function arguments
+}
+
+// Root returns the expression tree.
+func (e *Expr) Root() Node {
+	return newNode(e.root)
+}
+
+func newNode(n node) Node {
+	switch n := n.(type) {
+	case *literalNode:
+		return Node{Kind: NodeLiteral, Value: n.val}
+	case *columnNode:
+		return Node{Kind: NodeColumn, Name: n.name}
+	case *unaryNode:
+		return Node{Kind: NodeUnary, Op: n.op, Args: []Node{newNode(n.operand)}}
+	case *binaryNode:
+		return Node{Kind: NodeBinary, Op: n.op, Args: []Node{newNode(n.left), newNode(n.right)}}
+	case *callNode:
+		args := make([]Node, len(n.args))
+		for i, arg := range n.args {
+			args[i] = newNode(arg)
+		}
+		return Node{Kind: NodeCall, Name: n.name, Args: args}
+	}
+	return Node{}
+}
+
+// String returns the node in the expression syntax, with binary operands in
+// parentheses.
+func (n Node) String() string {
+	switch n.Kind {
+	case NodeLiteral:
+		if s, ok := n.Value.(string); ok {
+			return QuoteString(s)
+		} else if n.Value == nil {
+			return "null"
+		}
+		return FormatValue(n.Value)
+	case NodeColumn:
+		return QuoteColumn(n.Name)
+	case NodeUnary:
+		return n.Op + n.Args[0].operandString()
+	case NodeBinary:
+		return n.Args[0].operandString() + " " + n.Op + " " + n.Args[1].operandString()
+	case NodeCall:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = arg.String()
+		}
+		return n.Name + "(" + strings.Join(args, ", ") + ")"
+	}
+	return ""
+}
+
+func (n Node) operandString() string {
+	if n.Kind == NodeBinary {
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
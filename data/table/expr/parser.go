@@ -0,0 +1,324 @@
+// Package expr parses and evaluates expressions over table rows, such as
+// `revenue / users * 100` or `if(status == "200", "ok", "err")`, for
+// computed columns and row filters.
+package expr
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	eval(vars Vars) (any, error)
+}
+
+type literalNode struct {
+	val any
+}
+
+type columnNode struct {
+	name string
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+// Expr is a parsed expression. It is safe for concurrent use.
+type Expr struct {
+	src     string
+	root    node
+	columns []string
+}
+
+// Parse parses an expression. Column names are bare identifiers, such as
+// `revenue`, or in square brackets when they contain spaces or symbols, such
+// as `[Unit Price]`. Strings use double or single quotes. The operators, from
+// lowest to highest precedence, are `||` (`or`), `&&` (`and`), `!` (`not`),
+// comparisons `==`, `!=`, `<`, `<=`, `>` and `>=`, `+` and `-`, and `*`, `/`
+// and `%`. See `Functions` for the supported functions.
+func Parse(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Expr{src: src, root: root, columns: p.columns}, nil
+}
+
+// MustParse is like `Parse` but panics on error.
+func MustParse(src string) *Expr {
+	e, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the expression source.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Columns returns the column names referenced by the expression in order of
+// first appearance.
+func (e *Expr) Columns() []string {
+	return slices.Clone(e.columns)
+}
+
+type parser struct {
+	src     string
+	toks    []token
+	pos     int
+	columns []string
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &ParseError{Expr: p.src, Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// matchOp consumes the next token if it is one of the operators or keywords
+// and returns the normalized operator.
+func (p *parser) matchOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return "", false
+	}
+	op := t.text
+	if t.kind == tokIdent {
+		switch strings.ToLower(op) {
+		case "and":
+			op = "&&"
+		case "or":
+			op = "||"
+		case "not":
+			op = "!"
+		default:
+			return "", false
+		}
+	}
+	if !slices.Contains(ops, op) {
+		return "", false
+	}
+	p.next()
+	return op, true
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.matchOp("||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.matchOp("&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if op, ok := p.matchOp("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.matchOp("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdd() (node, error) {
+	left, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.matchOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMul() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.matchOp("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.matchOp("-", "+"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %q", t.text)
+		}
+		return &literalNode{val: f}, nil
+	case tokString:
+		return &literalNode{val: t.text}, nil
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, p.errorf(r, "expected \")\" but found %s", r)
+		}
+		return n, nil
+	case tokIdent:
+		if p.peek().kind == tokLParen && p.src[t.pos] != '[' {
+			return p.parseCall(t)
+		}
+		if p.src[t.pos] != '[' {
+			switch strings.ToLower(t.text) {
+			case "true":
+				return &literalNode{val: true}, nil
+			case "false":
+				return &literalNode{val: false}, nil
+			case "null":
+				return &literalNode{val: nil}, nil
+			}
+		}
+		if !slices.Contains(p.columns, t.text) {
+			p.columns = append(p.columns, t.text)
+		}
+		return &columnNode{name: t.text}, nil
+	case tokEOF:
+		return nil, p.errorf(t, "unexpected end of expression")
+	default:
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fnName := strings.ToLower(name.text)
+	fn, ok := functions[fnName]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %q", name.text)
+	}
+	p.next() // (
+	var args []node
+	if p.peek().kind == tokRParen {
+		p.next()
+	} else {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			t := p.next()
+			if t.kind == tokRParen {
+				break
+			} else if t.kind != tokComma {
+				return nil, p.errorf(t, "expected \",\" or \")\" but found %s", t)
+			}
+		}
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, p.errorf(name, "function %s expects %s, got %d", fnName, fn.arity(), len(args))
+	}
+	return &callNode{name: fnName, fn: fn, args: args}, nil
+}
@@ -0,0 +1,34 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"status", "revenue", "users"}
+	tbl.Rows = [][]string{{"200", "250", "1000"}, {"500", "90", "0"}, {"200", "40"}}
+	if err := tbl.AddExprColumn("pct", "revenue / users * 100"); err != nil {
+		t.Fatalf("Table.AddExprColumn() error: [%v]", err)
+	}
+	if err := tbl.AddExprColumn("result", `if(status == "200", "ok", "err")`); err != nil {
+		t.Fatalf("Table.AddExprColumn() error: [%v]", err)
+	}
+	want := []string{"200,250,1000,25,ok", "500,90,0,,err", "200,40,,,ok"}
+	for i, row := range tbl.Rows {
+		if got := strings.Join(row, ","); got != want[i] {
+			t.Errorf("Table.AddExprColumn() row [%d] mismatch: want [%s] got [%s]", i, want[i], got)
+		}
+	}
+	out, err := tbl.FilterExpr(`result == "ok" and revenue >= 100`)
+	if err != nil {
+		t.Fatalf("Table.FilterExpr() error: [%v]", err)
+	}
+	if len(out.Rows) != 1 || out.Rows[0][1] != "250" {
+		t.Errorf("Table.FilterExpr() mismatch: want [1] row got [%v]", out.Rows)
+	}
+	if _, err := tbl.FilterExpr("missing > 1"); err == nil {
+		t.Error("Table.FilterExpr() expected error for unknown column")
+	}
+}
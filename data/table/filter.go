@@ -45,35 +45,45 @@ func (tbl *Table) FilterColumnValuesTable(wantColNameValues map[string][]string)
 // any of which can match the desired rows.
 func (tbl *Table) FilterColumnValuesRows(wantColNameValues map[string][]string) ([][]string, error) {
 	data := [][]string{}
-	wantColIndexes := map[string]int{}
+	match, err := columnValuesMatcher(tbl.Columns, wantColNameValues)
+	if err != nil {
+		return data, err
+	}
+	for _, row := range tbl.Rows {
+		if match(row) {
+			data = append(data, row)
+		}
+	}
+	return data, nil
+}
+
+// columnValuesMatcher returns a function reporting whether a row matches any of
+// the desired values for every column in `wantColNameValues`. Rows too short to
+// contain every column do not match.
+func columnValuesMatcher(cols Columns, wantColNameValues map[string][]string) (func(row []string) bool, error) {
+	wantColIndexes := map[int][]string{}
 	maxIdx := -1
-	for wantColName := range wantColNameValues {
-		wantColIdx := tbl.Columns.Index(wantColName)
+	for wantColName, wantColValues := range wantColNameValues {
+		wantColIdx := cols.Index(wantColName)
 		if wantColIdx < 0 {
-			return data, fmt.Errorf("column not found [%v]", wantColName)
+			return nil, fmt.Errorf("column not found [%v]", wantColName)
 		}
 		if wantColIdx > maxIdx {
 			maxIdx = wantColIdx
 		}
-		wantColIndexes[wantColName] = wantColIdx
+		wantColIndexes[wantColIdx] = wantColValues
 	}
-ROWS:
-	for _, row := range tbl.Rows {
-		if len(row) > maxIdx {
-			for wantColName, wantColIdx := range wantColIndexes {
-				colValue := row[wantColIdx]
-				wantColValues, ok := wantColNameValues[wantColName]
-				if !ok {
-					return data, fmt.Errorf("column name (%s) has no desired value", wantColName)
-				}
-				if !slices.Contains(wantColValues, colValue) {
-					continue ROWS
-				}
+	return func(row []string) bool {
+		if len(row) <= maxIdx {
+			return false
+		}
+		for wantColIdx, wantColValues := range wantColIndexes {
+			if !slices.Contains(wantColValues, row[wantColIdx]) {
+				return false
 			}
-			data = append(data, row)
 		}
-	}
-	return data, nil
+		return true
+	}, nil
 }
 
 func (tbl *Table) FilterNonEmptyRows() (*Table, error) {
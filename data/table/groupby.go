@@ -0,0 +1,298 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AggFunc is an aggregation function used by `GroupBy.Agg`.
+type AggFunc string
+
+const (
+	AggCount         AggFunc = "count"          // rows, or non-empty values if `Aggregation.Column` is set
+	AggCountDistinct AggFunc = "count_distinct" // distinct non-empty values
+	AggSum           AggFunc = "sum"
+	AggMean          AggFunc = "mean"
+	AggMin           AggFunc = "min"
+	AggMax           AggFunc = "max"
+	AggMedian        AggFunc = "median"
+	AggPercentile    AggFunc = "percentile" // uses `Aggregation.Percentile`
+	AggFirst         AggFunc = "first"      // first non-empty value
+	AggLast          AggFunc = "last"       // last non-empty value
+	AggStringJoin    AggFunc = "string_join"
+)
+
+// Aggregation describes one output column of `GroupBy.Agg`. Numeric
+// aggregations ignore empty values and return an error for values which
+// cannot be parsed as numbers. If the table has a `Schema`, numeric values
+// are parsed with the column's `ColumnSchema`, so that values such as
+// currencies and percents can be aggregated.
+type Aggregation struct {
+	Name       string  // output column name, default "<func>_<column>" or "count"
+	Column     string  // input column name, optional for `AggCount`
+	Func       AggFunc // aggregation function
+	Percentile float64 // percentile from 0 to 100 for `AggPercentile`
+	Separator  string  // separator for `AggStringJoin`, default ", "
+	Distinct   bool    // join distinct values only for `AggStringJoin`
+}
+
+// OutputName returns the output column name.
+func (agg Aggregation) OutputName() string {
+	if agg.Name != "" {
+		return agg.Name
+	} else if agg.Column == "" {
+		return string(agg.Func)
+	}
+	return string(agg.Func) + "_" + agg.Column
+}
+
+func (agg Aggregation) isNumeric() bool {
+	switch agg.Func {
+	case AggSum, AggMean, AggMin, AggMax, AggMedian, AggPercentile:
+		return true
+	default:
+		return false
+	}
+}
+
+// GroupBy groups the rows of a `Table` by the values of one or more columns.
+// It is created with `Table.GroupBy` and evaluated with `GroupBy.Agg`.
+type GroupBy struct {
+	tbl      *Table
+	colNames []string
+	colIdxs  []int
+	err      error
+}
+
+// GroupBy returns a `GroupBy` for the supplied column names. Without column
+// names, all rows are in a single group. Errors, such as unknown column names,
+// are returned by `GroupBy.Agg`.
+func (tbl *Table) GroupBy(colNames ...string) *GroupBy {
+	gb := &GroupBy{tbl: tbl, colNames: colNames}
+	if tbl == nil {
+		gb.err = ErrTableCannotBeNil
+		return gb
+	}
+	for _, colName := range colNames {
+		colIdx := tbl.Columns.Index(colName)
+		if colIdx < 0 {
+			gb.err = fmt.Errorf("group by column not found [%s]", colName)
+			return gb
+		}
+		gb.colIdxs = append(gb.colIdxs, colIdx)
+	}
+	return gb
+}
+
+type group struct {
+	key  []string
+	rows [][]string
+}
+
+// groups returns the groups in order of first appearance.
+func (gb *GroupBy) groups() []*group {
+	var groups []*group
+	groupsByKey := map[string]*group{}
+	for _, row := range gb.tbl.Rows {
+		key := make([]string, len(gb.colIdxs))
+		for i, colIdx := range gb.colIdxs {
+			if colIdx < len(row) {
+				key[i] = row[colIdx]
+			}
+		}
+		keyStr := strings.Join(key, "\x1f")
+		g, ok := groupsByKey[keyStr]
+		if !ok {
+			g = &group{key: key}
+			groupsByKey[keyStr] = g
+			groups = append(groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+	return groups
+}
+
+// Agg returns a new `Table` with one row per group, in order of first
+// appearance. The columns are the group by columns followed by one column
+// per aggregation. Count columns are formatted as `FormatInt` and other
+// numeric columns as `FormatFloat`.
+func (gb *GroupBy) Agg(aggs ...Aggregation) (*Table, error) {
+	if gb.err != nil {
+		return nil, gb.err
+	}
+	if len(aggs) == 0 {
+		return nil, errors.New("no aggregations provided")
+	}
+	out := NewTable(gb.tbl.Name)
+	out.Columns = slices.Clone(gb.colNames)
+
+	aggColIdxs := make([]int, len(aggs))
+	parsers := make([]func(string) (float64, error), len(aggs))
+	for i, agg := range aggs {
+		aggColIdxs[i] = -1
+		if agg.Column != "" {
+			if aggColIdxs[i] = gb.tbl.Columns.Index(agg.Column); aggColIdxs[i] < 0 {
+				return nil, fmt.Errorf("aggregation column not found [%s]", agg.Column)
+			}
+		} else if agg.Func != AggCount {
+			return nil, fmt.Errorf("aggregation [%s] requires a column", agg.Func)
+		}
+		if agg.Func == AggPercentile && (agg.Percentile < 0 || agg.Percentile > 100) {
+			return nil, fmt.Errorf("percentile must be between 0 and 100, got [%v]", agg.Percentile)
+		}
+		colOutIdx := len(out.Columns)
+		if slices.Contains(out.Columns, agg.OutputName()) {
+			return nil, fmt.Errorf("duplicate output column name [%s]", agg.OutputName())
+		}
+		out.Columns = append(out.Columns, agg.OutputName())
+		switch {
+		case agg.Func == AggCount || agg.Func == AggCountDistinct:
+			out.FormatMap[colOutIdx] = FormatInt
+		case agg.isNumeric():
+			out.FormatMap[colOutIdx] = FormatFloat
+			parsers[i] = gb.tbl.float64Parser(agg.Column)
+		}
+	}
+
+	for _, g := range gb.groups() {
+		row := slices.Clone(g.key)
+		for i, agg := range aggs {
+			val, err := aggregate(agg, g.rows, aggColIdxs[i], parsers[i])
+			if err != nil {
+				return nil, fmt.Errorf("aggregation [%s] for group [%s]: %w", agg.OutputName(), strings.Join(g.key, ","), err)
+			}
+			row = append(row, val)
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return &out, nil
+}
+
+// float64Parser returns a function parsing values of `colName` as numbers,
+// using the table's `Schema` for numeric column types if available.
+func (tbl *Table) float64Parser(colName string) func(string) (float64, error) {
+	if tbl.Schema != nil {
+		return schemaFloat64Parser(tbl.Schema.Column(colName))
+	}
+	return schemaFloat64Parser(nil)
+}
+
+// schemaFloat64Parser returns a number parser for a column schema, which can
+// be nil.
+func schemaFloat64Parser(cs *ColumnSchema) func(string) (float64, error) {
+	if cs != nil && cs.Type.IsNumeric() {
+		return func(s string) (float64, error) {
+			v, err := cs.Parse(s)
+			if err != nil {
+				return 0, err
+			}
+			switch n := v.(type) {
+			case int:
+				return float64(n), nil
+			case float64:
+				return n, nil
+			}
+			return 0, fmt.Errorf("value is not numeric [%s]", s)
+		}
+	}
+	return func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	}
+}
+
+func aggregate(agg Aggregation, rows [][]string, colIdx int, parse func(string) (float64, error)) (string, error) {
+	if agg.Func == AggCount && colIdx < 0 {
+		return strconv.Itoa(len(rows)), nil
+	}
+	var vals []string
+	for _, row := range rows {
+		if colIdx < len(row) {
+			if v := strings.TrimSpace(row[colIdx]); v != "" {
+				vals = append(vals, v)
+			}
+		}
+	}
+	switch agg.Func {
+	case AggCount:
+		return strconv.Itoa(len(vals)), nil
+	case AggCountDistinct:
+		slices.Sort(vals)
+		return strconv.Itoa(len(slices.Compact(vals))), nil
+	case AggFirst:
+		if len(vals) == 0 {
+			return "", nil
+		}
+		return vals[0], nil
+	case AggLast:
+		if len(vals) == 0 {
+			return "", nil
+		}
+		return vals[len(vals)-1], nil
+	case AggStringJoin:
+		if agg.Distinct {
+			var distinct []string
+			for _, v := range vals {
+				if !slices.Contains(distinct, v) {
+					distinct = append(distinct, v)
+				}
+			}
+			vals = distinct
+		}
+		sep := agg.Separator
+		if sep == "" {
+			sep = ", "
+		}
+		return strings.Join(vals, sep), nil
+	}
+	if !agg.isNumeric() {
+		return "", fmt.Errorf("unknown aggregation function [%s]", agg.Func)
+	}
+
+	nums := make([]float64, len(vals))
+	for i, v := range vals {
+		n, err := parse(v)
+		if err != nil {
+			return "", fmt.Errorf("cannot parse number [%s]: %w", v, err)
+		}
+		nums[i] = n
+	}
+	if len(nums) == 0 {
+		if agg.Func == AggSum {
+			return "0", nil
+		}
+		return "", nil
+	}
+	var result float64
+	switch agg.Func {
+	case AggSum, AggMean:
+		for _, n := range nums {
+			result += n
+		}
+		if agg.Func == AggMean {
+			result /= float64(len(nums))
+		}
+	case AggMin:
+		result = slices.Min(nums)
+	case AggMax:
+		result = slices.Max(nums)
+	case AggMedian:
+		result = percentile(nums, 50)
+	case AggPercentile:
+		result = percentile(nums, agg.Percentile)
+	}
+	return strconv.FormatFloat(result, 'f', -1, 64), nil
+}
+
+// percentile returns the p-th percentile, 0 to 100, of non-empty `nums` using
+// linear interpolation between closest ranks. `nums` is sorted in place.
+func percentile(nums []float64, p float64) float64 {
+	slices.Sort(nums)
+	rank := p / 100 * float64(len(nums)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return nums[lo] + (nums[hi]-nums[lo])*(rank-float64(lo))
+}
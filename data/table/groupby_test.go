@@ -0,0 +1,51 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupByAgg(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"team", "user", "amount"}
+	tbl.Rows = [][]string{
+		{"a", "alice", "10"},
+		{"b", "bob", "4"},
+		{"a", "carol", "30"},
+		{"a", "alice", ""},
+		{"b", "dave", "6"},
+	}
+	out, err := tbl.GroupBy("team").Agg(
+		Aggregation{Func: AggCount},
+		Aggregation{Func: AggCountDistinct, Column: "user", Name: "users"},
+		Aggregation{Func: AggSum, Column: "amount"},
+		Aggregation{Func: AggMean, Column: "amount"},
+		Aggregation{Func: AggMedian, Column: "amount"},
+		Aggregation{Func: AggPercentile, Column: "amount", Percentile: 75, Name: "p75"},
+		Aggregation{Func: AggLast, Column: "user"},
+		Aggregation{Func: AggStringJoin, Column: "user", Separator: "|", Distinct: true},
+	)
+	if err != nil {
+		t.Fatalf("GroupBy.Agg() error: [%v]", err)
+	}
+	wantCols := "team,count,users,sum_amount,mean_amount,median_amount,p75,last_user,string_join_user"
+	if cols := strings.Join(out.Columns, ","); cols != wantCols {
+		t.Errorf("GroupBy.Agg() columns mismatch: want [%s] got [%s]", wantCols, cols)
+	}
+	wantRows := []string{
+		"a,3,2,40,20,20,25,alice,alice|carol",
+		"b,2,2,10,5,5,5.5,dave,bob|dave",
+	}
+	for i, want := range wantRows {
+		if got := strings.Join(out.Rows[i], ","); got != want {
+			t.Errorf("GroupBy.Agg() row [%d] mismatch: want [%s] got [%s]", i, want, got)
+		}
+	}
+
+	if _, err := tbl.GroupBy("team").Agg(Aggregation{Func: AggSum, Column: "user"}); err == nil {
+		t.Error("GroupBy.Agg() expected error for non-numeric sum")
+	}
+	if _, err := tbl.GroupBy("missing").Agg(Aggregation{Func: AggCount}); err == nil {
+		t.Error("GroupBy.Agg() expected error for missing column")
+	}
+}
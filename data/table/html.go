@@ -33,7 +33,8 @@ func (tbl *Table) ToDocuments() []map[string]any {
 	return docs
 }
 
-// ToHTML converts `*TableData` to HTML.
+// ToHTML converts `*TableData` to HTML. If `Schema` is set, numeric schema columns are
+// right aligned.
 func (tbl *Table) ToHTML(escapeHTML bool) string {
 	tHTML := "<table>"
 	tbl.ID = strings.TrimSpace(tbl.ID)
@@ -66,23 +67,31 @@ func (tbl *Table) ToHTML(escapeHTML bool) string {
 	if len(tbl.Rows) > 0 {
 		tHTML += "<tbody>"
 		fmtFunc := tbl.FormatterFuncHTML()
+		var schemaCols []*ColumnSchema
+		if tbl.Schema != nil {
+			schemaCols = tbl.Schema.tableColumns(tbl.Columns)
+		}
 		for _, row := range tbl.Rows {
 			tHTML += "<tr>"
 			for x := uint32(0); int(x) < len(row); x++ {
 				cell := row[x]
 				// for x, cell := range row {
+				td := "<td>"
+				if int(x) < len(schemaCols) && schemaCols[x] != nil && schemaCols[x].Type.IsNumeric() {
+					td = `<td style="text-align:right">`
+				}
 				cfmt, err := fmtFunc(cell, x)
 				if err != nil {
 					if escapeHTML {
-						tHTML += "<td>" + html.EscapeString(cell) + "</td>"
+						tHTML += td + html.EscapeString(cell) + "</td>"
 					} else {
-						tHTML += "<td>" + cell + "</td>"
+						tHTML += td + cell + "</td>"
 					}
 				} else {
 					if escapeHTML {
-						tHTML += "<td>" + html.EscapeString(cfmt.(string)) + "</td>"
+						tHTML += td + html.EscapeString(cfmt.(string)) + "</td>"
 					} else {
-						tHTML += "<td>" + cfmt.(string) + "</td>"
+						tHTML += td + cfmt.(string) + "</td>"
 					}
 				}
 			}
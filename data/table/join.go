@@ -0,0 +1,331 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// JoinType is the type of join used by `Table.Join`.
+type JoinType string
+
+const (
+	JoinInner JoinType = "inner" // rows with matching keys in both tables
+	JoinLeft  JoinType = "left"  // all left rows with matching right rows
+	JoinRight JoinType = "right" // all right rows with matching left rows
+	JoinFull  JoinType = "full"  // all rows from both tables
+	JoinAnti  JoinType = "anti"  // left rows without matching right rows
+)
+
+const (
+	defaultJoinLeftSuffix  = "_left"
+	defaultJoinRightSuffix = "_right"
+)
+
+// JoinOptions configures `Table.Join`.
+type JoinOptions struct {
+	Type        JoinType // default `JoinInner`
+	LeftKeys    []string // key column names in the left table
+	RightKeys   []string // key column names in the right table, default `LeftKeys`
+	LeftSuffix  string   // suffix for colliding left column names, default "_left"
+	RightSuffix string   // suffix for colliding right column names, default "_right"
+	IgnoreCase  bool     // match keys case-insensitively
+	TrimSpace   bool     // match keys ignoring leading and trailing space
+}
+
+func (opts JoinOptions) keyValue(row []string, idxs []int) (string, bool) {
+	parts := make([]string, len(idxs))
+	for i, idx := range idxs {
+		if idx >= len(row) {
+			return "", false
+		}
+		v := row[idx]
+		if opts.TrimSpace {
+			v = strings.TrimSpace(v)
+		}
+		if opts.IgnoreCase {
+			v = strings.ToLower(v)
+		}
+		if v == "" {
+			return "", false
+		}
+		parts[i] = v
+	}
+	return strings.Join(parts, "\x1f"), true
+}
+
+// joinColumn maps an output column to its source columns.
+type joinColumn struct {
+	name     string
+	leftIdx  int // -1 if not from the left table
+	rightIdx int // -1 if not from the right table
+}
+
+// Join joins the table with `right` on one or more key columns and returns
+// a new table. The output columns are the key columns, using left names, then
+// the other left columns and the other right columns. Non-key column names
+// in both tables are suffixed. Key values are taken from the left row, or
+// from the right row for unmatched right rows. Rows with an empty key value
+// do not match any row. `JoinAnti` returns the left columns only.
+//
+// Rows are ordered by left row, with matching right rows in right table
+// order, followed by unmatched right rows for `JoinRight` and `JoinFull`.
+// `FormatMap` and `Schema` column definitions are carried over from both
+// tables.
+func (tbl *Table) Join(right *Table, opts JoinOptions) (*Table, error) {
+	if tbl == nil || right == nil {
+		return nil, ErrTableCannotBeNil
+	}
+	if opts.Type == "" {
+		opts.Type = JoinInner
+	}
+	switch opts.Type {
+	case JoinInner, JoinLeft, JoinRight, JoinFull, JoinAnti:
+	default:
+		return nil, fmt.Errorf("unknown join type [%s]", opts.Type)
+	}
+	if len(opts.LeftKeys) == 0 {
+		return nil, errors.New("join keys not provided")
+	}
+	rightKeys := opts.RightKeys
+	if len(rightKeys) == 0 {
+		rightKeys = opts.LeftKeys
+	} else if len(rightKeys) != len(opts.LeftKeys) {
+		return nil, fmt.Errorf("join key count mismatch: left [%d] right [%d]", len(opts.LeftKeys), len(rightKeys))
+	}
+	leftKeyIdxs, err := columnIndexes(tbl.Columns, opts.LeftKeys)
+	if err != nil {
+		return nil, fmt.Errorf("left table: %w", err)
+	}
+	rightKeyIdxs, err := columnIndexes(right.Columns, rightKeys)
+	if err != nil {
+		return nil, fmt.Errorf("right table: %w", err)
+	}
+	cols, err := joinColumns(tbl.Columns, right.Columns, leftKeyIdxs, rightKeyIdxs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rightIndex := map[string][]int{}
+	for i, row := range right.Rows {
+		if key, ok := opts.keyValue(row, rightKeyIdxs); ok {
+			rightIndex[key] = append(rightIndex[key], i)
+		}
+	}
+	rightMatched := make([]bool, len(right.Rows))
+
+	out := NewTable(tbl.Name)
+	for _, col := range cols {
+		out.Columns = append(out.Columns, col.name)
+	}
+	joinRow := func(leftRow, rightRow []string) []string {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			if col.leftIdx >= 0 && leftRow != nil && col.leftIdx < len(leftRow) {
+				row[i] = leftRow[col.leftIdx]
+			} else if col.rightIdx >= 0 && rightRow != nil && col.rightIdx < len(rightRow) {
+				row[i] = rightRow[col.rightIdx]
+			}
+		}
+		return row
+	}
+	for _, leftRow := range tbl.Rows {
+		var matches []int
+		if key, ok := opts.keyValue(leftRow, leftKeyIdxs); ok {
+			matches = rightIndex[key]
+		}
+		if opts.Type == JoinAnti {
+			if len(matches) == 0 {
+				out.Rows = append(out.Rows, joinRow(leftRow, nil))
+			}
+			continue
+		}
+		for _, ri := range matches {
+			rightMatched[ri] = true
+			out.Rows = append(out.Rows, joinRow(leftRow, right.Rows[ri]))
+		}
+		if len(matches) == 0 && (opts.Type == JoinLeft || opts.Type == JoinFull) {
+			out.Rows = append(out.Rows, joinRow(leftRow, nil))
+		}
+	}
+	if opts.Type == JoinRight || opts.Type == JoinFull {
+		for ri, rightRow := range right.Rows {
+			if !rightMatched[ri] {
+				out.Rows = append(out.Rows, joinRow(nil, rightRow))
+			}
+		}
+	}
+
+	joinFormats(&out, tbl, right, cols, opts.Type)
+	return &out, nil
+}
+
+func columnIndexes(cols Columns, colNames []string) ([]int, error) {
+	idxs := make([]int, len(colNames))
+	for i, colName := range colNames {
+		if idxs[i] = cols.Index(colName); idxs[i] < 0 {
+			return nil, fmt.Errorf("key column not found [%s]", colName)
+		}
+	}
+	return idxs, nil
+}
+
+func joinColumns(leftCols, rightCols Columns, leftKeyIdxs, rightKeyIdxs []int, opts JoinOptions) ([]joinColumn, error) {
+	leftSuffix, rightSuffix := opts.LeftSuffix, opts.RightSuffix
+	if leftSuffix == "" {
+		leftSuffix = defaultJoinLeftSuffix
+	}
+	if rightSuffix == "" {
+		rightSuffix = defaultJoinRightSuffix
+	}
+	var cols []joinColumn
+	var keyNames []string
+	for i, leftIdx := range leftKeyIdxs {
+		cols = append(cols, joinColumn{name: leftCols[leftIdx], leftIdx: leftIdx, rightIdx: rightKeyIdxs[i]})
+		keyNames = append(keyNames, leftCols[leftIdx])
+	}
+	var leftOther, rightOther []string
+	for i, colName := range leftCols {
+		if !slices.Contains(leftKeyIdxs, i) {
+			leftOther = append(leftOther, colName)
+		}
+	}
+	if opts.Type != JoinAnti {
+		for i, colName := range rightCols {
+			if !slices.Contains(rightKeyIdxs, i) {
+				rightOther = append(rightOther, colName)
+			}
+		}
+	}
+	for i, colName := range leftCols {
+		if slices.Contains(leftKeyIdxs, i) {
+			continue
+		}
+		if slices.Contains(rightOther, colName) {
+			colName += leftSuffix
+		}
+		cols = append(cols, joinColumn{name: colName, leftIdx: i, rightIdx: -1})
+	}
+	for i, colName := range rightCols {
+		if opts.Type == JoinAnti || slices.Contains(rightKeyIdxs, i) {
+			continue
+		}
+		if slices.Contains(leftOther, colName) || slices.Contains(keyNames, colName) {
+			colName += rightSuffix
+		}
+		cols = append(cols, joinColumn{name: colName, leftIdx: -1, rightIdx: i})
+	}
+	seen := map[string]bool{}
+	for _, col := range cols {
+		if seen[col.name] {
+			return nil, fmt.Errorf("join output column name collision [%s]", col.name)
+		}
+		seen[col.name] = true
+	}
+	return cols, nil
+}
+
+// joinFormats sets the `FormatMap` and `Schema` of the joined table from the
+// source tables. Left table definitions take precedence for key columns.
+// Outer joins can leave non-key cells empty, so those columns are not
+// required in the joined schema.
+func joinFormats(out, left, right *Table, cols []joinColumn, joinType JoinType) {
+	var sch *Schema
+	if left.Schema != nil || right.Schema != nil {
+		sch = &Schema{}
+	}
+	for i, col := range cols {
+		var src *Table
+		var srcIdx int
+		if col.leftIdx >= 0 {
+			src, srcIdx = left, col.leftIdx
+		} else {
+			src, srcIdx = right, col.rightIdx
+		}
+		if f, ok := src.FormatMap[srcIdx]; ok {
+			out.FormatMap[i] = f
+		}
+		if sch != nil && src.Schema != nil {
+			if cs := src.Schema.Column(src.Columns[srcIdx]); cs != nil {
+				csOut := *cs
+				csOut.Name = col.name
+				isKey := col.leftIdx >= 0 && col.rightIdx >= 0
+				if !isKey && joinType != JoinInner && joinType != JoinAnti {
+					csOut.Required = false
+				}
+				sch.Columns = append(sch.Columns, csOut)
+			}
+		}
+	}
+	out.Schema = sch
+}
+
+// Relationship declares how two tables in a `TableSet` are joined, such as
+// an export table and a lookup table sharing an ID column.
+type Relationship struct {
+	Name       string
+	LeftTable  string
+	RightTable string
+	Options    JoinOptions
+}
+
+// AddRelationship adds a relationship between two tables in the set. The
+// relationship name must be unique and both tables must exist.
+func (ts *TableSet) AddRelationship(rel Relationship) error {
+	if strings.TrimSpace(rel.Name) == "" {
+		return errors.New("relationship name cannot be empty")
+	}
+	for _, r := range ts.Relationships {
+		if r.Name == rel.Name {
+			return fmt.Errorf("relationship name collision [%s]", rel.Name)
+		}
+	}
+	for _, tblName := range []string{rel.LeftTable, rel.RightTable} {
+		if _, err := ts.Table(tblName); err != nil {
+			return err
+		}
+	}
+	ts.Relationships = append(ts.Relationships, rel)
+	return nil
+}
+
+// Relationship returns the relationship with the supplied name.
+func (ts *TableSet) Relationship(name string) (Relationship, error) {
+	for _, rel := range ts.Relationships {
+		if rel.Name == name {
+			return rel, nil
+		}
+	}
+	return Relationship{}, fmt.Errorf("relationship not found [%s]", name)
+}
+
+// Join joins tables by the named relationships in order and returns the
+// result. The first relationship joins its left and right tables. Each later
+// relationship joins the result so far, as its left table, with its right
+// table, so its left keys must be column names in the result.
+func (ts *TableSet) Join(relationshipNames ...string) (*Table, error) {
+	if len(relationshipNames) == 0 {
+		return nil, errors.New("no relationships provided")
+	}
+	var out *Table
+	for i, relName := range relationshipNames {
+		rel, err := ts.Relationship(relName)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if out, err = ts.Table(rel.LeftTable); err != nil {
+				return nil, err
+			}
+		}
+		right, err := ts.Table(rel.RightTable)
+		if err != nil {
+			return nil, err
+		}
+		if out, err = out.Join(right, rel.Options); err != nil {
+			return nil, fmt.Errorf("relationship [%s]: %w", relName, err)
+		}
+	}
+	return out, nil
+}
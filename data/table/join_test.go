@@ -0,0 +1,83 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func joinTestTables() (*Table, *Table) {
+	left := NewTable("views")
+	left.Columns = []string{"user", "views", "name"}
+	left.Rows = [][]string{{"A1", "10", "x"}, {"b2 ", "20", "y"}, {"c3", "30", "z"}}
+	right := NewTable("roster")
+	right.Columns = []string{"id", "name", "team"}
+	right.Rows = [][]string{{"a1", "Alice", "red"}, {"B2", "Bob", "blue"}, {"d4", "Dave", "red"}}
+	return &left, &right
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		joinType JoinType
+		cols     string
+		rows     []string
+	}{
+		{JoinInner, "user,views,name_left,name_right,team", []string{"A1,10,x,Alice,red", "b2 ,20,y,Bob,blue"}},
+		{JoinLeft, "user,views,name_left,name_right,team", []string{"A1,10,x,Alice,red", "b2 ,20,y,Bob,blue", "c3,30,z,,"}},
+		{JoinRight, "user,views,name_left,name_right,team", []string{"A1,10,x,Alice,red", "b2 ,20,y,Bob,blue", "d4,,,Dave,red"}},
+		{JoinFull, "user,views,name_left,name_right,team", []string{"A1,10,x,Alice,red", "b2 ,20,y,Bob,blue", "c3,30,z,,", "d4,,,Dave,red"}},
+		{JoinAnti, "user,views,name", []string{"c3,30,z"}},
+	}
+	left, right := joinTestTables()
+	for _, tt := range tests {
+		out, err := left.Join(right, JoinOptions{
+			Type:       tt.joinType,
+			LeftKeys:   []string{"user"},
+			RightKeys:  []string{"id"},
+			IgnoreCase: true,
+			TrimSpace:  true})
+		if err != nil {
+			t.Fatalf("Table.Join(%s) error: [%v]", tt.joinType, err)
+		}
+		if cols := strings.Join(out.Columns, ","); cols != tt.cols {
+			t.Errorf("Table.Join(%s) columns mismatch: want [%s] got [%s]", tt.joinType, tt.cols, cols)
+		}
+		var rows []string
+		for _, row := range out.Rows {
+			rows = append(rows, strings.Join(row, ","))
+		}
+		if strings.Join(rows, "|") != strings.Join(tt.rows, "|") {
+			t.Errorf("Table.Join(%s) rows mismatch: want [%v] got [%v]", tt.joinType, tt.rows, rows)
+		}
+	}
+}
+
+func TestTableSetJoin(t *testing.T) {
+	left, right := joinTestTables()
+	teams := NewTable("teams")
+	teams.Columns = []string{"team", "lead"}
+	teams.Rows = [][]string{{"red", "Rita"}, {"blue", "Bea"}}
+	ts := NewTableSet("")
+	if err := ts.Add(left, right, &teams); err != nil {
+		t.Fatalf("TableSet.Add() error: [%v]", err)
+	}
+	for _, rel := range []Relationship{
+		{Name: "roster", LeftTable: "views", RightTable: "roster", Options: JoinOptions{
+			LeftKeys: []string{"user"}, RightKeys: []string{"id"}, IgnoreCase: true, TrimSpace: true}},
+		{Name: "lead", LeftTable: "roster", RightTable: "teams", Options: JoinOptions{
+			Type: JoinLeft, LeftKeys: []string{"team"}}},
+	} {
+		if err := ts.AddRelationship(rel); err != nil {
+			t.Fatalf("TableSet.AddRelationship() error: [%v]", err)
+		}
+	}
+	out, err := ts.Join("roster", "lead")
+	if err != nil {
+		t.Fatalf("TableSet.Join() error: [%v]", err)
+	}
+	if got := strings.Join(out.Columns, ","); got != "team,user,views,name_left,name_right,lead" {
+		t.Errorf("TableSet.Join() columns mismatch: got [%s]", got)
+	}
+	if len(out.Rows) != 2 || out.Rows[1][5] != "Bea" {
+		t.Errorf("TableSet.Join() rows mismatch: got [%v]", out.Rows)
+	}
+}
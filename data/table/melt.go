@@ -0,0 +1,178 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	defaultMeltVarName   = "variable"
+	defaultMeltValueName = "value"
+)
+
+// MeltOptions configures `Table.Melt`.
+type MeltOptions struct {
+	// TimeParseFunc parses value column names, such as "Jan 2024", into
+	// times for the variable column.
+	TimeParseFunc func(s string) (time.Time, error)
+	TimeFormat    string // layout for parsed times, default `time.RFC3339`
+	SkipEmpty     bool   // omit rows with empty values
+}
+
+// Melt converts a wide table to a long table, the reverse of `Table.Pivot`.
+// Each row is converted to one row per value column with the `idCols` values,
+// the value column name in `varName` and the cell in `valueName`. If
+// `valueCols` is empty, all columns not in `idCols` are used. `varName` and
+// `valueName` default to "variable" and "value".
+//
+// With `MeltOptions.TimeParseFunc`, the variable column holds formatted times,
+// ready for `timeseries.ParseTableTimeSeriesSetFlat`.
+func (tbl *Table) Melt(idCols, valueCols []string, varName, valueName string, opts *MeltOptions) (*Table, error) {
+	if tbl.IsFloat64 {
+		return nil, errors.New("cannot melt float table")
+	}
+	if opts == nil {
+		opts = &MeltOptions{}
+	}
+	if varName == "" {
+		varName = defaultMeltVarName
+	}
+	if valueName == "" {
+		valueName = defaultMeltValueName
+	}
+	idIdxs, err := columnIndexes(tbl.Columns, idCols)
+	if err != nil {
+		return nil, err
+	}
+	if len(valueCols) == 0 {
+		for _, colName := range tbl.Columns {
+			if !slices.Contains(idCols, colName) {
+				valueCols = append(valueCols, colName)
+			}
+		}
+	}
+	valueIdxs, err := columnIndexes(tbl.Columns, valueCols)
+	if err != nil {
+		return nil, err
+	}
+	outCols := append(slices.Clone(idCols), varName, valueName)
+	if dupe := duplicateColumn(outCols); dupe != "" {
+		return nil, fmt.Errorf("column name already exists [%s]", dupe)
+	}
+
+	varVals := slices.Clone(valueCols)
+	if opts.TimeParseFunc != nil {
+		timeFormat := opts.TimeFormat
+		if timeFormat == "" {
+			timeFormat = time.RFC3339
+		}
+		for i, colName := range valueCols {
+			t, err := opts.TimeParseFunc(colName)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse time from column name [%s]: %w", colName, err)
+			}
+			varVals[i] = t.Format(timeFormat)
+		}
+	}
+
+	out := NewTable(tbl.Name)
+	out.Columns = outCols
+	for i, idx := range idIdxs {
+		if f, ok := commonFormat(tbl.FormatMap, []int{idx}); ok {
+			out.FormatMap[i] = f
+		}
+	}
+	if f, ok := commonFormat(tbl.FormatMap, valueIdxs); ok {
+		out.FormatMap[len(idIdxs)+1] = f
+	}
+	if tbl.Schema != nil {
+		out.Schema = tbl.meltSchema(idCols, valueCols, varName, valueName, opts)
+	}
+	for i, valueIdx := range valueIdxs {
+		for _, row := range tbl.Rows {
+			val := ""
+			if valueIdx < len(row) {
+				val = row[valueIdx]
+			}
+			if opts.SkipEmpty && val == "" {
+				continue
+			}
+			outRow := make([]string, 0, len(outCols))
+			for _, idx := range idIdxs {
+				if idx < len(row) {
+					outRow = append(outRow, row[idx])
+				} else {
+					outRow = append(outRow, "")
+				}
+			}
+			out.Rows = append(out.Rows, append(outRow, varVals[i], val))
+		}
+	}
+	return &out, nil
+}
+
+// meltSchema returns the schema of a melted table. The value column keeps the
+// type of the value columns if they share one.
+func (tbl *Table) meltSchema(idCols, valueCols []string, varName, valueName string, opts *MeltOptions) *Schema {
+	sch := &Schema{}
+	for _, colName := range idCols {
+		if cs := tbl.Schema.Column(colName); cs != nil {
+			c := *cs
+			c.Name = colName
+			sch.Columns = append(sch.Columns, c)
+		} else {
+			sch.Columns = append(sch.Columns, ColumnSchema{Name: colName, Type: ColumnTypeString})
+		}
+	}
+	varCol := ColumnSchema{Name: varName, Type: ColumnTypeString}
+	if opts.TimeParseFunc != nil {
+		varCol = ColumnSchema{Name: varName, Type: ColumnTypeDatetime, Layout: opts.TimeFormat}
+	}
+	valueCol := ColumnSchema{Name: valueName, Type: ColumnTypeString}
+	for i, colName := range valueCols {
+		cs := tbl.Schema.Column(colName)
+		if cs == nil {
+			valueCol = ColumnSchema{Name: valueName, Type: ColumnTypeString}
+			break
+		} else if i == 0 {
+			valueCol = *cs
+			valueCol.Name = valueName
+			valueCol.Required = false
+		} else if cs.Type != valueCol.Type {
+			valueCol = ColumnSchema{Name: valueName, Type: ColumnTypeString}
+			break
+		}
+	}
+	sch.Columns = append(sch.Columns, varCol, valueCol)
+	return sch
+}
+
+// commonFormat returns the format shared by all columns in `idxs`, using the
+// default format at index -1 for columns without one.
+func commonFormat(fmtMap map[int]string, idxs []int) (string, bool) {
+	var f string
+	for i, idx := range idxs {
+		cur, ok := fmtMap[idx]
+		if !ok {
+			cur, ok = fmtMap[-1]
+		}
+		if !ok || (i > 0 && cur != f) {
+			return "", false
+		}
+		f = cur
+	}
+	return f, len(idxs) > 0
+}
+
+func duplicateColumn(cols []string) string {
+	seen := map[string]bool{}
+	for _, c := range cols {
+		if seen[c] {
+			return c
+		}
+		seen[c] = true
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMelt(t *testing.T) {
+	tbl := NewTable("crosstab")
+	tbl.Columns = []string{"region", "Jan 2024", "Feb 2024"}
+	tbl.Rows = [][]string{{"EMEA", "10", "12"}, {"APAC", "7", ""}}
+	out, err := tbl.Melt([]string{"region"}, nil, "month", "", &MeltOptions{
+		TimeParseFunc: func(s string) (time.Time, error) { return time.Parse("Jan 2006", s) },
+		TimeFormat:    time.DateOnly,
+		SkipEmpty:     true,
+	})
+	if err != nil {
+		t.Fatalf("Table.Melt() error: [%v]", err)
+	}
+	if got := strings.Join(out.Columns, ","); got != "region,month,value" {
+		t.Errorf("Table.Melt() columns mismatch: want [%s] got [%s]", "region,month,value", got)
+	}
+	want := []string{"EMEA,2024-01-01,10", "APAC,2024-01-01,7", "EMEA,2024-02-01,12"}
+	if len(out.Rows) != len(want) {
+		t.Fatalf("Table.Melt() row count mismatch: want [%d] got [%d]", len(want), len(out.Rows))
+	}
+	for i, row := range out.Rows {
+		if got := strings.Join(row, ","); got != want[i] {
+			t.Errorf("Table.Melt() row [%d] mismatch: want [%s] got [%s]", i, want[i], got)
+		}
+	}
+	if _, err := tbl.Melt([]string{"region"}, []string{"Mar 2024"}, "", "", nil); err == nil {
+		t.Error("Table.Melt() expected error for unknown value column")
+	}
+}
@@ -0,0 +1,337 @@
+// Package odsutil reads OpenDocument Spreadsheet (ODS) files with the same
+// table semantics as `excelizeutil`.
+package odsutil
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/gocharts/v2/data/table/sheet"
+)
+
+const (
+	nsOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	nsTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	nsText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+	contentFilename = "content.xml"
+)
+
+var ErrFileCannotBeNil = errors.New("odsutil.File cannot be nil")
+
+// File is the worksheets of an ODS file.
+type File struct {
+	Sheets []*sheet.Grid
+}
+
+// ReadFile reads an ODS file.
+func ReadFile(filename string) (*File, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	f, err := readZip(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	return f, zr.Close()
+}
+
+// NewReader reads an ODS file from `r`, which has the given size.
+func NewReader(r io.ReaderAt, size int64) (*File, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return readZip(zr)
+}
+
+func readZip(zr *zip.Reader) (*File, error) {
+	rc, err := zr.Open(contentFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	sheets, err := parseContent(rc)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Sheets: sheets}, nil
+}
+
+func (f *File) SheetNames(sortAsc bool) []string {
+	var names []string
+	if f == nil {
+		return names
+	}
+	for _, g := range f.Sheets {
+		names = append(names, g.Name)
+	}
+	if sortAsc {
+		sort.Strings(names)
+	}
+	return names
+}
+
+// Sheet returns the worksheet with the given name or `nil` if not found.
+func (f *File) Sheet(sheetName string) *sheet.Grid {
+	if f == nil {
+		return nil
+	}
+	for _, g := range f.Sheets {
+		if g.Name == sheetName {
+			return g
+		}
+	}
+	return nil
+}
+
+func (f *File) TableDataIndex(sheetIdx, headerRowCount uint32, trimSpace, umerge bool) ([]string, [][]string, error) {
+	if f == nil {
+		return []string{}, [][]string{}, ErrFileCannotBeNil
+	} else if int(sheetIdx) >= len(f.Sheets) {
+		return []string{}, [][]string{}, fmt.Errorf("sheet index not found [%d]", sheetIdx)
+	}
+	return f.Sheets[sheetIdx].TableData(headerRowCount, trimSpace, umerge)
+}
+
+// TableData returns the columns and rows of a worksheet with the same
+// semantics as `excelizeutil.File.TableData()`.
+func (f *File) TableData(sheetName string, headerRowCount uint32, trimSpace, umerge bool) ([]string, [][]string, error) {
+	if f == nil {
+		return []string{}, [][]string{}, ErrFileCannotBeNil
+	}
+	g := f.Sheet(sheetName)
+	if g == nil {
+		return []string{}, [][]string{}, fmt.Errorf("sheet not found [%s]", sheetName)
+	}
+	return g.TableData(headerRowCount, trimSpace, umerge)
+}
+
+// parseContent parses the worksheets in `content.xml`. Repeated empty rows
+// and cells, which are used to pad sheets to their maximum size, are only
+// added when followed by non-empty content.
+func parseContent(r io.Reader) ([]*sheet.Grid, error) {
+	dec := xml.NewDecoder(r)
+	var sheets []*sheet.Grid
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return sheets, nil
+		} else if err != nil {
+			return sheets, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Space == nsTable && se.Name.Local == "table" {
+			g, err := parseTable(dec, se)
+			if err != nil {
+				return sheets, err
+			}
+			sheets = append(sheets, g)
+		}
+	}
+}
+
+func parseTable(dec *xml.Decoder, start xml.StartElement) (*sheet.Grid, error) {
+	g := &sheet.Grid{Name: attr(start, nsTable, "name")}
+	pendingRows := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return g, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space != nsTable {
+				if err := dec.Skip(); err != nil {
+					return g, err
+				}
+				continue
+			}
+			switch el.Name.Local {
+			case "table-row":
+				rowIdx := uint32(len(g.Rows) + pendingRows)
+				row, merges, err := parseRow(dec, rowIdx)
+				if err != nil {
+					return g, err
+				}
+				repeat := countAttr(el, nsTable, "number-rows-repeated")
+				if len(row) == 0 {
+					pendingRows += repeat
+					continue
+				}
+				for range pendingRows {
+					g.Rows = append(g.Rows, []string{})
+				}
+				pendingRows = 0
+				for i := range repeat {
+					g.Rows = append(g.Rows, append([]string{}, row...))
+					for _, mr := range merges {
+						mr.StartRow += uint32(i)
+						mr.EndRow += uint32(i)
+						g.Merges = append(g.Merges, mr)
+					}
+				}
+			case "table-header-rows", "table-row-group", "table-rows":
+				// rows are read from within these groupings
+			default:
+				if err := dec.Skip(); err != nil {
+					return g, err
+				}
+			}
+		case xml.EndElement:
+			if el.Name.Space == nsTable && el.Name.Local == "table" {
+				g.Compact()
+				return g, nil
+			}
+		}
+	}
+}
+
+func parseRow(dec *xml.Decoder, rowIdx uint32) ([]string, []sheet.MergeRange, error) {
+	var row []string
+	var merges []sheet.MergeRange
+	pendingCells := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return row, merges, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space != nsTable || (el.Name.Local != "table-cell" && el.Name.Local != "covered-table-cell") {
+				if err := dec.Skip(); err != nil {
+					return row, merges, err
+				}
+				continue
+			}
+			val, err := parseCell(dec, el)
+			if err != nil {
+				return row, merges, err
+			}
+			repeat := countAttr(el, nsTable, "number-columns-repeated")
+			colSpan := countAttr(el, nsTable, "number-columns-spanned")
+			rowSpan := countAttr(el, nsTable, "number-rows-spanned")
+			if val == "" && colSpan == 1 && rowSpan == 1 {
+				pendingCells += repeat
+				continue
+			}
+			for range pendingCells {
+				row = append(row, "")
+			}
+			pendingCells = 0
+			for range repeat {
+				if colSpan > 1 || rowSpan > 1 {
+					colIdx := uint32(len(row))
+					merges = append(merges, sheet.MergeRange{
+						StartCol: colIdx,
+						StartRow: rowIdx,
+						EndCol:   colIdx + uint32(colSpan) - 1,
+						EndRow:   rowIdx + uint32(rowSpan) - 1,
+					})
+				}
+				row = append(row, val)
+			}
+		case xml.EndElement:
+			return row, merges, nil
+		}
+	}
+}
+
+// parseCell returns the displayed text of a cell, falling back to the
+// office value attributes for cells without text paragraphs.
+func parseCell(dec *xml.Decoder, start xml.StartElement) (string, error) {
+	var paras []string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space == nsText && el.Name.Local == "p" {
+				var b strings.Builder
+				if err := parseText(dec, &b); err != nil {
+					return "", err
+				}
+				paras = append(paras, b.String())
+			} else if err := dec.Skip(); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			if len(paras) > 0 {
+				return strings.Join(paras, "\n"), nil
+			}
+			for _, name := range []string{"value", "date-value", "time-value", "boolean-value", "string-value"} {
+				if v := attr(start, nsOffice, name); v != "" {
+					return v, nil
+				}
+			}
+			return "", nil
+		}
+	}
+}
+
+// parseText writes the text of a paragraph, including spans, links, spaces,
+// tabs and line breaks, until the end of the current element.
+func parseText(dec *xml.Decoder, b *strings.Builder) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.CharData:
+			b.Write(el)
+		case xml.StartElement:
+			if el.Name.Space == nsText {
+				switch el.Name.Local {
+				case "s":
+					b.WriteString(strings.Repeat(" ", countAttr(el, nsText, "c")))
+				case "tab":
+					b.WriteString("\t")
+				case "line-break":
+					b.WriteString("\n")
+				case "note", "annotation":
+					if err := dec.Skip(); err != nil {
+						return err
+					}
+					continue
+				}
+			} else if el.Name.Space == nsOffice && el.Name.Local == "annotation" {
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := parseText(dec, b); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func attr(se xml.StartElement, space, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// countAttr returns a positive count attribute, defaulting to 1.
+func countAttr(se xml.StartElement, space, local string) int {
+	if n, err := strconv.Atoi(attr(se, space, local)); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
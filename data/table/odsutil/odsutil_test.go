@@ -0,0 +1,67 @@
+package odsutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testContentXML = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+<office:body><office:spreadsheet>
+<table:table table:name="Sales">
+<table:table-column table:number-columns-repeated="3"/>
+<table:table-row><table:table-cell><text:p>Region</text:p></table:table-cell><table:table-cell><text:p>Month</text:p></table:table-cell><table:table-cell><text:p> Amount </text:p></table:table-cell><table:table-cell table:number-columns-repeated="1021"/></table:table-row>
+<table:table-row><table:table-cell table:number-rows-spanned="2"><text:p>West</text:p></table:table-cell><table:table-cell><text:p>Jan</text:p></table:table-cell><table:table-cell office:value-type="float" office:value="100"><text:p>100</text:p></table:table-cell></table:table-row>
+<table:table-row><table:covered-table-cell/><table:table-cell><text:p>Feb</text:p></table:table-cell><table:table-cell office:value-type="float" office:value="1200"/></table:table-row>
+<table:table-row><table:table-cell table:number-columns-spanned="3"><text:p>Total</text:p></table:table-cell><table:covered-table-cell table:number-columns-repeated="2"/></table:table-row>
+<table:table-row table:number-rows-repeated="1048572"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+<table:table table:name="Notes"><table:table-row><table:table-cell><text:p>a<text:s text:c="2"/>b</text:p></table:table-cell></table:table-row></table:table>
+</office:spreadsheet></office:body></office:document-content>`
+
+func TestTableData(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(contentFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(testContentXML)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("odsutil.NewReader() error: [%v]", err)
+	}
+	if got := strings.Join(f.SheetNames(true), ","); got != "Notes,Sales" {
+		t.Errorf("File.SheetNames() mismatch: want [%s] got [%s]", "Notes,Sales", got)
+	}
+	tests := []struct {
+		umerge bool
+		want   string
+	}{
+		{false, "Region,Month,Amount|West,Jan,100|,Feb,1200|Total"},
+		{true, "Region,Month,Amount|West,Jan,100|,Feb,1200|Total,Total,Total"},
+	}
+	for _, tt := range tests {
+		cols, rows, err := f.TableData("Sales", 1, true, tt.umerge)
+		if err != nil {
+			t.Fatalf("File.TableData() error: [%v]", err)
+		}
+		parts := []string{strings.Join(cols, ",")}
+		for _, row := range rows {
+			parts = append(parts, strings.Join(row, ","))
+		}
+		if got := strings.Join(parts, "|"); got != tt.want {
+			t.Errorf("File.TableData(umerge=%v) mismatch: want [%s] got [%s]", tt.umerge, tt.want, got)
+		}
+	}
+	if cols, _, _ := f.TableData("Notes", 0, false, false); len(cols) != 1 || cols[0] != "a  b" {
+		t.Errorf("File.TableData() mismatch: want [%s] got [%v]", "a  b", cols)
+	}
+}
@@ -0,0 +1,564 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/deprecated"
+)
+
+const (
+	// ParquetMetadataKeySchema is the Parquet key-value metadata key of the
+	// stored table `Schema`.
+	ParquetMetadataKeySchema = metadataKeySchema
+
+	ParquetExt = ".parquet"
+
+	defaultParquetRowGroupSize = 100000
+	parquetReadBatchSize       = 1024
+)
+
+// ParquetOptions configures Parquet writing.
+type ParquetOptions struct {
+	RowGroupSize int64  // maximum rows per row group, default 100,000
+	Compression  string // "snappy" (default), "gzip", "zstd", "lz4" or "none"
+}
+
+// RowGroupSizeOrDefault returns the row group size, or the default if not set.
+func (opts *ParquetOptions) RowGroupSizeOrDefault() int64 {
+	if opts == nil || opts.RowGroupSize <= 0 {
+		return defaultParquetRowGroupSize
+	}
+	return opts.RowGroupSize
+}
+
+// Codec returns the `compress.Codec` for the compression.
+func (opts *ParquetOptions) Codec() (compress.Codec, error) {
+	name := ""
+	if opts != nil {
+		name = strings.ToLower(strings.TrimSpace(opts.Compression))
+	}
+	switch name {
+	case "", "snappy":
+		return &parquet.Snappy, nil
+	case "gzip":
+		return &parquet.Gzip, nil
+	case "zstd":
+		return &parquet.Zstd, nil
+	case "lz4":
+		return &parquet.Lz4Raw, nil
+	case "none":
+		return &parquet.Uncompressed, nil
+	}
+	return nil, fmt.Errorf("unknown parquet compression [%s]", opts.Compression)
+}
+
+// ParquetReader reads a Parquet file one row group at a time so that memory
+// is bounded by the row group size rather than the file size. Parquet logical
+// types are mapped to a `Schema`: integers to int, floats to float, decimals to
+// decimal, dates to date, timestamps to datetime and booleans to bool. Values
+// are formatted as strings which parse with the schema. Nested groups are
+// flattened with dotted column names. Repeated columns are not supported.
+type ParquetReader struct {
+	Columns Columns
+	Schema  *Schema
+	file    *parquet.File
+	closer  io.Closer
+	convs   []func(parquet.Value) string
+}
+
+// NewParquetReader returns a `ParquetReader` for `r`, which has `size` bytes.
+func NewParquetReader(r io.ReaderAt, size int64) (*ParquetReader, error) {
+	f, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, err
+	}
+	pr := &ParquetReader{file: f, Schema: &Schema{}}
+	var storedSchema *Schema
+	if v, ok := f.Lookup(ParquetMetadataKeySchema); ok {
+		storedSchema = parseStoredSchema(v)
+	}
+	for _, path := range f.Schema().Columns() {
+		leaf, _ := f.Schema().Lookup(path...)
+		colName := strings.Join(path, ".")
+		if leaf.MaxRepetitionLevel > 0 {
+			return nil, fmt.Errorf("repeated parquet column not supported [%s]", colName)
+		}
+		cs, conv := parquetColumnSchema(leaf.Node)
+		cs.Name = colName
+		if storedSchema != nil {
+			if scs := storedSchema.Column(colName); scs != nil {
+				var format func(string) string
+				if cs, format = storedColumnSchema(*scs, cs); format != nil {
+					leafConv := conv
+					conv = func(v parquet.Value) string { return format(leafConv(v)) }
+				}
+			}
+		}
+		pr.Columns = append(pr.Columns, colName)
+		pr.Schema.Columns = append(pr.Schema.Columns, cs)
+		pr.convs = append(pr.convs, conv)
+	}
+	return pr, nil
+}
+
+// NewParquetReaderFile returns a `ParquetReader` for a file. Call `Close` when
+// done.
+func NewParquetReaderFile(filename string) (*ParquetReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	pr, err := NewParquetReader(f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	pr.closer = f
+	return pr, nil
+}
+
+// Close closes the underlying file, if opened by `NewParquetReaderFile`.
+func (pr *ParquetReader) Close() error {
+	if pr.closer == nil {
+		return nil
+	}
+	err := pr.closer.Close()
+	pr.closer = nil
+	return err
+}
+
+// NumRows returns the number of rows in the file.
+func (pr *ParquetReader) NumRows() int64 {
+	return pr.file.NumRows()
+}
+
+// Rows returns an iterator over the rows of all row groups.
+func (pr *ParquetReader) Rows() iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for _, rg := range pr.file.RowGroups() {
+			for row, err := range pr.rowGroupRows(rg) {
+				if !yield(row, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// RowGroups returns an iterator over the row groups, each as a `Table` with
+// the reader's columns and schema.
+func (pr *ParquetReader) RowGroups() iter.Seq2[*Table, error] {
+	return func(yield func(*Table, error) bool) {
+		for _, rg := range pr.file.RowGroups() {
+			tbl := pr.newTable()
+			var rowErr error
+			for row, err := range pr.rowGroupRows(rg) {
+				if err != nil {
+					rowErr = err
+					break
+				}
+				tbl.Rows = append(tbl.Rows, row)
+			}
+			if rowErr != nil {
+				yield(nil, rowErr)
+				return
+			} else if !yield(&tbl, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Table reads all rows into a `Table`.
+func (pr *ParquetReader) Table() (Table, error) {
+	tbl := pr.newTable()
+	for row, err := range pr.Rows() {
+		if err != nil {
+			return tbl, err
+		}
+		tbl.Rows = append(tbl.Rows, row)
+	}
+	return tbl, nil
+}
+
+func (pr *ParquetReader) newTable() Table {
+	tbl := NewTable("")
+	tbl.Columns = append(Columns{}, pr.Columns...)
+	tbl.Schema = pr.Schema
+	return tbl
+}
+
+func (pr *ParquetReader) rowGroupRows(rg parquet.RowGroup) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		rows := rg.Rows()
+		defer rows.Close()
+		buf := make([]parquet.Row, parquetReadBatchSize)
+		for {
+			n, err := rows.ReadRows(buf)
+			for _, prow := range buf[:n] {
+				row := make([]string, len(pr.Columns))
+				for _, v := range prow {
+					if colIdx := v.Column(); colIdx >= 0 && colIdx < len(row) {
+						row[colIdx] = pr.convs[colIdx](v)
+					}
+				}
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				return
+			} else if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// ReadFileParquet reads a Parquet file into a `Table` named after the file.
+// Use `NewParquetReaderFile` to stream large files by row group.
+func ReadFileParquet(filename string) (Table, error) {
+	pr, err := NewParquetReaderFile(filename)
+	if err != nil {
+		return Table{}, err
+	}
+	tbl, err := pr.Table()
+	if err != nil {
+		pr.Close()
+		return tbl, err
+	}
+	tbl.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return tbl, pr.Close()
+}
+
+// ReadFilesParquet reads Parquet files into a `TableSet`, one table per file,
+// named after the file.
+func ReadFilesParquet(filenames ...string) (*TableSet, error) {
+	ts := NewTableSet("")
+	for _, filename := range filenames {
+		tbl, err := ReadFileParquet(filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := ts.Add(&tbl); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+// WriteParquet writes the table as Parquet. Columns are typed by the table
+// `Schema`, with string columns for tables or columns without one; use
+// `InferSchema` to write typed columns. Ints, floats, decimals, dates,
+// datetimes and bools use the matching Parquet logical types. Decimal and
+// currency columns are written as DECIMAL(18, precision), percents as
+// fractions and durations as nanoseconds. The schema is stored in the file
+// metadata under `ParquetMetadataKeySchema`.
+func (tbl *Table) WriteParquet(w io.Writer, opts *ParquetOptions) error {
+	if tbl.IsFloat64 {
+		return errors.New("cannot write float table as parquet")
+	}
+	codec, err := opts.Codec()
+	if err != nil {
+		return err
+	}
+	sch := tbl.typedSchema()
+	group := parquetGroup{}
+	for _, cs := range sch.Columns {
+		node, err := parquetNode(cs)
+		if err != nil {
+			return err
+		}
+		group.fields = append(group.fields, parquetField{Node: parquet.Optional(node), name: cs.Name})
+	}
+	metadata, err := json.Marshal(sch)
+	if err != nil {
+		return err
+	}
+	pw := parquet.NewGenericWriter[any](w,
+		parquet.NewSchema(tbl.Name, group),
+		parquet.Compression(codec),
+		parquet.MaxRowsPerRowGroup(opts.RowGroupSizeOrDefault()),
+		parquet.KeyValueMetadata(ParquetMetadataKeySchema, string(metadata)))
+
+	batch := make([]parquet.Row, 0, parquetReadBatchSize)
+	for y, row := range tbl.Rows {
+		prow := make(parquet.Row, len(sch.Columns))
+		for x := range sch.Columns {
+			val := ""
+			if x < len(row) {
+				val = row[x]
+			}
+			v, err := parquetValue(&sch.Columns[x], val)
+			if err != nil {
+				return fmt.Errorf("row [%d] column [%s]: %w", y, sch.Columns[x].Name, err)
+			}
+			if v.IsNull() {
+				prow[x] = v.Level(0, 0, x)
+			} else {
+				prow[x] = v.Level(0, 1, x)
+			}
+		}
+		if batch = append(batch, prow); len(batch) == cap(batch) {
+			if _, err := pw.WriteRows(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if _, err := pw.WriteRows(batch); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
+
+// WriteFileParquet writes the table to a Parquet file.
+func (tbl *Table) WriteFileParquet(filename string, opts *ParquetOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := tbl.WriteParquet(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteFilesParquet writes each table to a Parquet file in `dir` named after
+// the table, such as "Sales.parquet".
+func (ts *TableSet) WriteFilesParquet(dir string, opts *ParquetOptions) error {
+	for name, tbl := range ts.TableMap {
+		filename := filepath.Join(dir, tableFileName(name)+ParquetExt)
+		if err := tbl.WriteFileParquet(filename, opts); err != nil {
+			return fmt.Errorf("table [%s]: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func parquetNode(cs ColumnSchema) (parquet.Node, error) {
+	switch cs.Type {
+	case ColumnTypeString, "":
+		return parquet.String(), nil
+	case ColumnTypeInt, ColumnTypeDuration:
+		return parquet.Int(64), nil
+	case ColumnTypeFloat, ColumnTypePercent:
+		return parquet.Leaf(parquet.DoubleType), nil
+	case ColumnTypeDecimal, ColumnTypeCurrency:
+		return parquet.Decimal(cs.precision(), decimalDigits, parquet.Int64Type), nil
+	case ColumnTypeBool:
+		return parquet.Leaf(parquet.BooleanType), nil
+	case ColumnTypeDate:
+		return parquet.Date(), nil
+	case ColumnTypeDatetime:
+		return parquet.Timestamp(parquet.Microsecond), nil
+	}
+	return nil, fmt.Errorf("unknown column type [%s]", cs.Type)
+}
+
+// parquetValue converts a cell to a Parquet value for the column type.
+func parquetValue(cs *ColumnSchema, val string) (parquet.Value, error) {
+	v, err := cs.Parse(val)
+	if err != nil || v == nil {
+		return parquet.NullValue(), err
+	}
+	switch cs.Type {
+	case ColumnTypeString, "":
+		return parquet.ByteArrayValue([]byte(v.(string))), nil
+	case ColumnTypeInt:
+		return parquet.Int64Value(int64(v.(int))), nil
+	case ColumnTypeDuration:
+		return parquet.Int64Value(int64(v.(time.Duration))), nil
+	case ColumnTypeFloat, ColumnTypePercent:
+		return parquet.DoubleValue(v.(float64)), nil
+	case ColumnTypeDecimal, ColumnTypeCurrency:
+		unscaled, err := cs.unscaledDecimal(val)
+		if err != nil {
+			return parquet.NullValue(), err
+		}
+		return parquet.Int64Value(unscaled), nil
+	case ColumnTypeBool:
+		return parquet.BooleanValue(v.(bool)), nil
+	case ColumnTypeDate:
+		t := v.(time.Time)
+		days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+		return parquet.Int32Value(int32(days)), nil
+	case ColumnTypeDatetime:
+		return parquet.Int64Value(v.(time.Time).UnixMicro()), nil
+	}
+	return parquet.NullValue(), fmt.Errorf("unknown column type [%s]", cs.Type)
+}
+
+// parquetColumnSchema returns the column schema and value formatter for a
+// Parquet leaf column.
+func parquetColumnSchema(node parquet.Node) (ColumnSchema, func(parquet.Value) string) {
+	typ := node.Type()
+	lt := typ.LogicalType()
+	switch {
+	case lt != nil && lt.Decimal != nil:
+		scale := int(lt.Decimal.Scale)
+		return ColumnSchema{Type: ColumnTypeDecimal, Precision: &scale}, func(v parquet.Value) string {
+			if v.IsNull() {
+				return ""
+			}
+			return formatParquetDecimal(v, scale)
+		}
+	case lt != nil && lt.Date != nil:
+		return ColumnSchema{Type: ColumnTypeDate}, parquetTimeFormatter(func(v parquet.Value) time.Time {
+			return time.Unix(int64(v.Int32())*86400, 0).UTC()
+		}, time.DateOnly)
+	case lt != nil && lt.Timestamp != nil:
+		unit := lt.Timestamp.Unit
+		return ColumnSchema{Type: ColumnTypeDatetime}, parquetTimeFormatter(func(v parquet.Value) time.Time {
+			switch {
+			case unit.Millis != nil:
+				return time.UnixMilli(v.Int64()).UTC()
+			case unit.Nanos != nil:
+				return time.Unix(0, v.Int64()).UTC()
+			}
+			return time.UnixMicro(v.Int64()).UTC()
+		}, time.RFC3339Nano)
+	case lt != nil && lt.Integer != nil && !lt.Integer.IsSigned:
+		return ColumnSchema{Type: ColumnTypeInt}, func(v parquet.Value) string {
+			if v.IsNull() {
+				return ""
+			} else if typ.Kind() == parquet.Int32 {
+				return strconv.FormatUint(uint64(v.Uint32()), 10)
+			}
+			return strconv.FormatUint(v.Uint64(), 10)
+		}
+	}
+	switch typ.Kind() {
+	case parquet.Boolean:
+		return ColumnSchema{Type: ColumnTypeBool}, func(v parquet.Value) string {
+			if v.IsNull() {
+				return ""
+			}
+			return strconv.FormatBool(v.Boolean())
+		}
+	case parquet.Int32, parquet.Int64:
+		return ColumnSchema{Type: ColumnTypeInt}, func(v parquet.Value) string {
+			if v.IsNull() {
+				return ""
+			}
+			return strconv.FormatInt(v.Int64(), 10)
+		}
+	case parquet.Float, parquet.Double:
+		bitSize := 64
+		if typ.Kind() == parquet.Float {
+			bitSize = 32
+		}
+		return ColumnSchema{Type: ColumnTypeFloat}, func(v parquet.Value) string {
+			if v.IsNull() {
+				return ""
+			} else if bitSize == 32 {
+				return strconv.FormatFloat(float64(v.Float()), 'f', -1, 32)
+			}
+			return strconv.FormatFloat(v.Double(), 'f', -1, 64)
+		}
+	case parquet.Int96:
+		// legacy timestamps: nanoseconds of the day and Julian day number
+		return ColumnSchema{Type: ColumnTypeDatetime}, parquetTimeFormatter(func(v parquet.Value) time.Time {
+			return int96Time(v.Int96())
+		}, time.RFC3339Nano)
+	}
+	return ColumnSchema{Type: ColumnTypeString}, func(v parquet.Value) string {
+		if v.IsNull() {
+			return ""
+		}
+		return string(v.ByteArray())
+	}
+}
+
+func parquetTimeFormatter(toTime func(parquet.Value) time.Time, layout string) func(parquet.Value) string {
+	return func(v parquet.Value) string {
+		if v.IsNull() {
+			return ""
+		}
+		return toTime(v).Format(layout)
+	}
+}
+
+func int96Time(v deprecated.Int96) time.Time {
+	const julianUnixEpoch = 2440588
+	nanos := int64(v[1])<<32 | int64(v[0])
+	days := int64(v[2]) - julianUnixEpoch
+	return time.Unix(days*86400, nanos).UTC()
+}
+
+// formatParquetDecimal formats an unscaled decimal stored as an int32, int64
+// or big-endian two's complement byte array.
+func formatParquetDecimal(v parquet.Value, scale int) string {
+	unscaled := new(big.Int)
+	switch v.Kind() {
+	case parquet.Int32, parquet.Int64:
+		unscaled.SetInt64(v.Int64())
+	default:
+		b := v.ByteArray()
+		unscaled.SetBytes(b)
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+		}
+	}
+	s := unscaled.String()
+	if scale <= 0 {
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if len(s) <= scale {
+		s = strings.Repeat("0", scale-len(s)+1) + s
+	}
+	s = s[:len(s)-scale] + "." + s[len(s)-scale:]
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+// parquetGroup is a Parquet group node which keeps the field order, unlike
+// `parquet.Group` which sorts fields by name.
+type parquetGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g parquetGroup) Fields() []parquet.Field { return g.fields }
+
+func (g parquetGroup) GoType() reflect.Type { return reflect.TypeOf(map[string]any{}) }
+
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string { return f.name }
+
+func (f parquetField) Value(base reflect.Value) reflect.Value {
+	if base.Kind() == reflect.Map {
+		return base.MapIndex(reflect.ValueOf(f.name))
+	}
+	return reflect.Value{}
+}
@@ -0,0 +1,68 @@
+package table
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParquet(t *testing.T) {
+	tbl := NewTable("sales")
+	tbl.Columns = []string{"region", "units", "price", "share", "day", "updated"}
+	tbl.Rows = [][]string{
+		{"EMEA", "10", "1,200.50", "12.5%", "2024-01-31", "2024-01-31T10:00:00Z"},
+		{"APAC", "", "-3.25", "", "", ""},
+		{"AMER", "7", "0.05", "50%", "2024-02-29", "2024-02-29T23:59:59Z"},
+		{"LATAM", "", "(9,999,999,999,999,999.99)", "", "", ""},
+	}
+	tbl.Schema = &Schema{Columns: []ColumnSchema{
+		{Name: "region", Type: ColumnTypeString},
+		{Name: "units", Type: ColumnTypeInt},
+		{Name: "price", Type: ColumnTypeDecimal},
+		{Name: "share", Type: ColumnTypePercent},
+		{Name: "day", Type: ColumnTypeDate},
+		{Name: "updated", Type: ColumnTypeDatetime},
+	}}
+	var buf bytes.Buffer
+	if err := tbl.WriteParquet(&buf, &ParquetOptions{RowGroupSize: 2}); err != nil {
+		t.Fatalf("Table.WriteParquet() error: [%v]", err)
+	}
+	pr, err := NewParquetReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("NewParquetReader() error: [%v]", err)
+	}
+	if got := strings.Join(pr.Columns, ","); got != strings.Join(tbl.Columns, ",") {
+		t.Errorf("ParquetReader.Columns mismatch: want [%s] got [%s]", strings.Join(tbl.Columns, ","), got)
+	}
+	for i, cs := range pr.Schema.Columns {
+		if cs.Type != tbl.Schema.Columns[i].Type {
+			t.Errorf("ParquetReader.Schema column [%s] type mismatch: want [%s] got [%s]", cs.Name, tbl.Schema.Columns[i].Type, cs.Type)
+		}
+	}
+	groups := 0
+	var rows [][]string
+	for rg, err := range pr.RowGroups() {
+		if err != nil {
+			t.Fatalf("ParquetReader.RowGroups() error: [%v]", err)
+		}
+		groups++
+		rows = append(rows, rg.Rows...)
+	}
+	if groups != 2 {
+		t.Errorf("ParquetReader.RowGroups() count mismatch: want [2] got [%d]", groups)
+	}
+	want := []string{
+		"EMEA,10,1200.50,12.5%,2024-01-31,2024-01-31T10:00:00Z",
+		"APAC,,-3.25,,,",
+		"AMER,7,0.05,50%,2024-02-29,2024-02-29T23:59:59Z",
+		"LATAM,,-9999999999999999.99,,,",
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("ParquetReader.RowGroups() row count mismatch: want [%d] got [%d]", len(want), len(rows))
+	}
+	for i, row := range rows {
+		if got := strings.Join(row, ","); got != want[i] {
+			t.Errorf("ParquetReader row [%d] mismatch: want [%s] got [%s]", i, want[i], got)
+		}
+	}
+}
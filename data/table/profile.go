@@ -0,0 +1,478 @@
+package table
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	excelize "github.com/xuri/excelize/v2"
+	"gonum.org/v1/gonum/stat"
+)
+
+const (
+	ProfileSheetSummary    = "Profile"
+	ProfileSheetTopValues  = "Top Values"
+	ProfileSheetHistograms = "Histograms"
+
+	defaultProfileTopN          = 5
+	defaultProfileHistogramBins = 10
+)
+
+// ProfileOptions configures `Table.Profile`.
+type ProfileOptions struct {
+	TopN          int       // number of most frequent values, default 5
+	HistogramBins int       // numeric histogram bins, default 10
+	Quantiles     []float64 // numeric quantiles, default 0.25, 0.5 and 0.75
+	SampleSize    int       // rows used to infer the schema when `Table.Schema` is not set, default all
+}
+
+func (opts *ProfileOptions) topN() int {
+	if opts == nil || opts.TopN <= 0 {
+		return defaultProfileTopN
+	}
+	return opts.TopN
+}
+
+func (opts *ProfileOptions) histogramBins() int {
+	if opts == nil || opts.HistogramBins <= 0 {
+		return defaultProfileHistogramBins
+	}
+	return opts.HistogramBins
+}
+
+func (opts *ProfileOptions) quantiles() []float64 {
+	if opts == nil || len(opts.Quantiles) == 0 {
+		return []float64{0.25, 0.5, 0.75}
+	}
+	return opts.Quantiles
+}
+
+// Profile summarizes the columns of a table.
+type Profile struct {
+	Name     string
+	RowCount int
+	Columns  []ColumnProfile
+}
+
+// ColumnProfile summarizes the values of a column. Values are trimmed of
+// space before they are counted.
+type ColumnProfile struct {
+	Name          string
+	Type          ColumnType
+	EmptyCount    int // empty or missing values
+	InvalidCount  int // non-empty values which do not parse as `Type`
+	DistinctCount int // distinct non-empty values
+	TopValues     []ValueCount
+	Min           string // smallest value: numeric, earliest or lexical depending on `Type`
+	Max           string
+	Numeric       *NumericProfile // set for numeric types
+	Dates         *DateProfile    // set for date and datetime types
+	Lengths       LengthProfile
+}
+
+// ValueCount is a value and the number of times it occurs.
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// NumericProfile summarizes the parsed values of a numeric column. Percents
+// are fractions. `Histogram` has equal width bins from `Min` to `Max`.
+type NumericProfile struct {
+	Count     int
+	Min       float64
+	Max       float64
+	Mean      float64
+	StdDev    float64 // sample standard deviation
+	Quantiles []Quantile
+	Histogram []int
+}
+
+// Quantile is the value at probability `P`, interpolated linearly between
+// closest ranks.
+type Quantile struct {
+	P     float64
+	Value float64
+}
+
+// DateProfile is the range of the parsed values of a date or datetime column.
+type DateProfile struct {
+	Count int
+	Min   time.Time
+	Max   time.Time
+}
+
+// LengthProfile summarizes the length in characters of non-empty values.
+type LengthProfile struct {
+	Min  int
+	Max  int
+	Mean float64
+}
+
+// Profile returns a profile of each column. Column types come from `Schema`,
+// or `InferSchema` if not set. Distinct and top values build on
+// `ColumnValuesCounts`, and string minimum and maximum values are lexical, as
+// with `ColumnValuesMinMax`, excluding empty values.
+func (tbl *Table) Profile(opts *ProfileOptions) (*Profile, error) {
+	if tbl.IsFloat64 {
+		return nil, fmt.Errorf("cannot profile float table [%s]", tbl.Name)
+	}
+	sch := tbl.Schema
+	if sch == nil {
+		sampleSize := 0
+		if opts != nil {
+			sampleSize = opts.SampleSize
+		}
+		sch = tbl.InferSchema(sampleSize)
+	}
+	schemaCols := sch.tableColumns(tbl.Columns)
+	p := &Profile{Name: tbl.Name, RowCount: len(tbl.Rows)}
+	for colIdx, colName := range tbl.Columns {
+		cs := &ColumnSchema{Name: colName, Type: ColumnTypeString}
+		if colIdx < len(schemaCols) && schemaCols[colIdx] != nil {
+			cs = schemaCols[colIdx]
+		}
+		p.Columns = append(p.Columns, tbl.profileColumn(colIdx, cs, opts))
+	}
+	return p, nil
+}
+
+func (tbl *Table) profileColumn(colIdx int, cs *ColumnSchema, opts *ProfileOptions) ColumnProfile {
+	cp := ColumnProfile{Name: tbl.Columns[colIdx], Type: cs.Type}
+	if cp.Type == "" {
+		cp.Type = ColumnTypeString
+	}
+	counts := tbl.ColumnValuesCounts(colIdx, true, false, false)
+	cp.DistinctCount = len(counts)
+	for val, count := range counts {
+		cp.TopValues = append(cp.TopValues, ValueCount{Value: val, Count: count})
+	}
+	slices.SortFunc(cp.TopValues, func(a, b ValueCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	cp.TopValues = cp.TopValues[:min(len(cp.TopValues), opts.topN())]
+
+	var nums []float64
+	var times []time.Time
+	lengthSum := 0
+	for _, row := range tbl.Rows {
+		val := ""
+		if colIdx < len(row) {
+			val = strings.TrimSpace(row[colIdx])
+		}
+		if val == "" {
+			cp.EmptyCount++
+			continue
+		}
+		n := utf8.RuneCountInString(val)
+		if lengthSum == 0 || n < cp.Lengths.Min {
+			cp.Lengths.Min = n
+		}
+		cp.Lengths.Max = max(cp.Lengths.Max, n)
+		lengthSum += n
+		if cp.Type == ColumnTypeString {
+			continue
+		}
+		v, err := cs.Parse(val)
+		if err != nil {
+			cp.InvalidCount++
+			continue
+		}
+		switch t := v.(type) {
+		case int:
+			nums = append(nums, float64(t))
+		case float64:
+			nums = append(nums, t)
+		case time.Time:
+			times = append(times, t)
+		}
+	}
+	if nonEmpty := len(tbl.Rows) - cp.EmptyCount; nonEmpty > 0 {
+		cp.Lengths.Mean = float64(lengthSum) / float64(nonEmpty)
+	}
+
+	switch {
+	case cp.Type.IsNumeric():
+		cp.Numeric = newNumericProfile(nums, opts)
+		if cp.Numeric.Count > 0 {
+			cp.Min = formatProfileFloat(cp.Numeric.Min)
+			cp.Max = formatProfileFloat(cp.Numeric.Max)
+		}
+	case cp.Type == ColumnTypeDate || cp.Type == ColumnTypeDatetime:
+		cp.Dates = &DateProfile{Count: len(times)}
+		if len(times) > 0 {
+			cp.Dates.Min = slices.MinFunc(times, time.Time.Compare)
+			cp.Dates.Max = slices.MaxFunc(times, time.Time.Compare)
+			layout := time.RFC3339
+			if cp.Type == ColumnTypeDate {
+				layout = time.DateOnly
+			}
+			cp.Min, cp.Max = cp.Dates.Min.Format(layout), cp.Dates.Max.Format(layout)
+		}
+	case len(counts) > 0:
+		keys := make([]string, 0, len(counts))
+		for val := range counts {
+			keys = append(keys, val)
+		}
+		cp.Min, cp.Max = slices.Min(keys), slices.Max(keys)
+	}
+	return cp
+}
+
+func newNumericProfile(nums []float64, opts *ProfileOptions) *NumericProfile {
+	np := &NumericProfile{Count: len(nums)}
+	if len(nums) == 0 {
+		return np
+	}
+	slices.Sort(nums)
+	np.Min, np.Max = nums[0], nums[len(nums)-1]
+	np.Mean, np.StdDev = stat.MeanStdDev(nums, nil)
+	if len(nums) == 1 {
+		np.StdDev = 0
+	}
+	for _, q := range opts.quantiles() {
+		np.Quantiles = append(np.Quantiles, Quantile{P: q, Value: quantileSorted(nums, q)})
+	}
+	np.Histogram = make([]int, opts.histogramBins())
+	width := (np.Max - np.Min) / float64(len(np.Histogram))
+	for _, v := range nums {
+		bin := 0
+		if width > 0 {
+			bin = min(int((v-np.Min)/width), len(np.Histogram)-1)
+		}
+		np.Histogram[bin]++
+	}
+	return np
+}
+
+// quantileSorted returns the quantile of sorted values, interpolating
+// linearly between closest ranks as with R type 7 and pandas.
+func quantileSorted(sorted []float64, p float64) float64 {
+	p = min(max(p, 0), 1)
+	h := p * float64(len(sorted)-1)
+	lo := int(math.Floor(h))
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (h-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+func formatProfileFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
+
+// Table returns the profile as a table with a row per column.
+func (p *Profile) Table() Table {
+	tbl := NewTable(ProfileSheetSummary)
+	tbl.Columns = []string{"Column", "Type", "Empty", "Invalid", "Distinct", "Min", "Max", "Mean", "Std Dev"}
+	var quantiles []float64
+	for _, cp := range p.Columns {
+		if cp.Numeric != nil && len(cp.Numeric.Quantiles) > 0 {
+			for _, q := range cp.Numeric.Quantiles {
+				quantiles = append(quantiles, q.P)
+			}
+			break
+		}
+	}
+	for _, q := range quantiles {
+		tbl.Columns = append(tbl.Columns, "P"+strconv.FormatFloat(q*100, 'f', -1, 64))
+	}
+	tbl.Columns = append(tbl.Columns, "Min Length", "Max Length", "Mean Length", "Top Values")
+	tbl.FormatMap = map[int]string{2: FormatInt, 3: FormatInt, 4: FormatInt, 7: FormatFloat, 8: FormatFloat}
+	for i := range quantiles {
+		tbl.FormatMap[9+i] = FormatFloat
+	}
+	tbl.FormatMap[9+len(quantiles)] = FormatInt
+	tbl.FormatMap[10+len(quantiles)] = FormatInt
+	tbl.FormatMap[11+len(quantiles)] = FormatFloat
+	for _, cp := range p.Columns {
+		row := []string{cp.Name, string(cp.Type), strconv.Itoa(cp.EmptyCount), strconv.Itoa(cp.InvalidCount),
+			strconv.Itoa(cp.DistinctCount), cp.Min, cp.Max}
+		if cp.Numeric != nil && cp.Numeric.Count > 0 {
+			row = append(row, formatProfileFloat(cp.Numeric.Mean), formatProfileFloat(cp.Numeric.StdDev))
+			for i := range quantiles {
+				if i < len(cp.Numeric.Quantiles) {
+					row = append(row, formatProfileFloat(cp.Numeric.Quantiles[i].Value))
+				} else {
+					row = append(row, "")
+				}
+			}
+		} else {
+			row = append(row, make([]string, 2+len(quantiles))...)
+		}
+		row = append(row, strconv.Itoa(cp.Lengths.Min), strconv.Itoa(cp.Lengths.Max),
+			formatProfileFloat(cp.Lengths.Mean), cp.topValuesString())
+		tbl.Rows = append(tbl.Rows, row)
+	}
+	return tbl
+}
+
+func (cp ColumnProfile) topValuesString() string {
+	var parts []string
+	for _, vc := range cp.TopValues {
+		parts = append(parts, fmt.Sprintf("%s (%d)", vc.Value, vc.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sparkline returns the numeric histogram as Unicode block characters, such
+// as "▁▃█▅▁", or an empty string for non-numeric columns.
+func (cp ColumnProfile) Sparkline() string {
+	if cp.Numeric == nil || cp.Numeric.Count == 0 {
+		return ""
+	}
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	peak := slices.Max(cp.Numeric.Histogram)
+	var sb strings.Builder
+	for _, c := range cp.Numeric.Histogram {
+		sb.WriteRune(blocks[int(math.Round(float64(c)/float64(peak)*float64(len(blocks)-1)))])
+	}
+	return sb.String()
+}
+
+// histogramSVG returns the numeric histogram as a small inline SVG bar chart.
+func (cp ColumnProfile) histogramSVG() string {
+	if cp.Numeric == nil || cp.Numeric.Count == 0 {
+		return ""
+	}
+	const barWidth, height = 6, 20
+	peak := slices.Max(cp.Numeric.Histogram)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, barWidth*len(cp.Numeric.Histogram), height)
+	for i, c := range cp.Numeric.Histogram {
+		h := float64(c) / float64(peak) * height
+		fmt.Fprintf(&sb, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="#4472C4"/>`, i*barWidth, height-h, barWidth-1, h)
+	}
+	sb.WriteString("</svg>")
+	return sb.String()
+}
+
+// Markdown returns the profile as a Markdown table with a sparkline histogram
+// for numeric columns.
+func (p *Profile) Markdown(newline string) string {
+	tbl := p.Table()
+	tbl.Columns = append(tbl.Columns, "Histogram")
+	for i, cp := range p.Columns {
+		tbl.Rows[i] = append(tbl.Rows[i], cp.Sparkline())
+	}
+	return tbl.Markdown(newline, true)
+}
+
+// WriteMarkdown writes the profile as Markdown to a file.
+func (p *Profile) WriteMarkdown(filename string, perm os.FileMode, newline string) error {
+	return os.WriteFile(filename, []byte(p.Markdown(newline)), perm)
+}
+
+// HTML returns the profile as an HTML table with an inline SVG histogram for
+// numeric columns.
+func (p *Profile) HTML() string {
+	tbl := p.Table()
+	tbl.FormatMap = map[int]string{}
+	tbl.Columns = append(tbl.Columns, "Histogram")
+	for i, cp := range p.Columns {
+		for j, cell := range tbl.Rows[i] {
+			tbl.Rows[i][j] = html.EscapeString(cell)
+		}
+		tbl.Rows[i] = append(tbl.Rows[i], cp.histogramSVG())
+	}
+	return tbl.ToHTML(false)
+}
+
+// NewXLSX returns the profile as a spreadsheet with a summary sheet, a sheet
+// of top values and a sheet of numeric histogram bins. Summary rows for
+// numeric columns have a column sparkline of the histogram.
+func (p *Profile) NewXLSX() (*excelize.File, error) {
+	summary := p.Table()
+	summary.Columns = append(summary.Columns, "Histogram")
+	for i := range summary.Rows {
+		summary.Rows[i] = append(summary.Rows[i], "")
+	}
+
+	topValues := NewTable(ProfileSheetTopValues)
+	topValues.Columns = []string{"Column", "Value", "Count", "Percent"}
+	topValues.FormatMap = map[int]string{2: FormatInt, 3: FormatPercent}
+	for _, cp := range p.Columns {
+		for _, vc := range cp.TopValues {
+			pct := 0.0
+			if p.RowCount > 0 {
+				pct = float64(vc.Count) / float64(p.RowCount)
+			}
+			topValues.Rows = append(topValues.Rows, []string{cp.Name, vc.Value, strconv.Itoa(vc.Count), strconv.FormatFloat(pct, 'f', -1, 64)})
+		}
+	}
+
+	hists := NewTable(ProfileSheetHistograms)
+	hists.Columns = []string{"Column", "Min", "Max"}
+	hists.FormatMap = map[int]string{-1: FormatInt, 0: FormatString, 1: FormatFloat, 2: FormatFloat}
+	type sparkline struct{ summaryRow, histRow, bins int }
+	var sparklines []sparkline
+	for i, cp := range p.Columns {
+		if cp.Numeric == nil || cp.Numeric.Count == 0 {
+			continue
+		}
+		row := []string{cp.Name, formatProfileFloat(cp.Numeric.Min), formatProfileFloat(cp.Numeric.Max)}
+		for _, c := range cp.Numeric.Histogram {
+			row = append(row, strconv.Itoa(c))
+		}
+		hists.Rows = append(hists.Rows, row)
+		for len(hists.Columns) < len(row) {
+			hists.Columns = append(hists.Columns, "Bin "+strconv.Itoa(len(hists.Columns)-2))
+		}
+		sparklines = append(sparklines, sparkline{summaryRow: i + 2, histRow: len(hists.Rows) + 1, bins: len(cp.Numeric.Histogram)})
+	}
+
+	f, err := NewXLSXReport([]*Table{&summary, &topValues, &hists}, &XLSXReportOptions{
+		AutoWidth:    true,
+		FreezeHeader: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sparklines) == 0 {
+		return f, nil
+	}
+	opts := &excelize.SparklineOptions{Type: "column"}
+	for _, s := range sparklines {
+		loc, err := excelize.CoordinatesToCellName(len(summary.Columns), s.summaryRow)
+		if err != nil {
+			return nil, err
+		}
+		start, err := excelize.CoordinatesToCellName(4, s.histRow)
+		if err != nil {
+			return nil, err
+		}
+		end, err := excelize.CoordinatesToCellName(3+s.bins, s.histRow)
+		if err != nil {
+			return nil, err
+		}
+		opts.Location = append(opts.Location, loc)
+		opts.Range = append(opts.Range, ProfileSheetHistograms+"!"+start+":"+end)
+	}
+	if err := f.AddSparkline(ProfileSheetSummary, opts); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// WriteXLSX writes the profile to a spreadsheet file. See `NewXLSX`.
+func (p *Profile) WriteXLSX(filename string) error {
+	f, err := p.NewXLSX()
+	if err != nil {
+		return err
+	}
+	if err := f.SaveAs(filename); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
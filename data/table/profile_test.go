@@ -0,0 +1,61 @@
+package table
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	tbl := NewTable("Sales")
+	tbl.Columns = []string{"region", "day", "units"}
+	tbl.Rows = [][]string{
+		{"West", "2024-01-15", "1"},
+		{"East", "2024-02-01", "2"},
+		{"West", "2024-03-10", "3"},
+		{"", "2023-12-31", "4"},
+		{"North", "", "10"},
+	}
+	p, err := tbl.Profile(nil)
+	if err != nil {
+		t.Fatalf("Table.Profile() error: [%v]", err)
+	}
+	region, day, units := p.Columns[0], p.Columns[1], p.Columns[2]
+	if region.Type != ColumnTypeString || region.EmptyCount != 1 || region.DistinctCount != 3 ||
+		region.TopValues[0] != (ValueCount{Value: "West", Count: 2}) || region.Min != "East" || region.Max != "West" {
+		t.Errorf("Table.Profile() string column mismatch: got [%v]", region)
+	}
+	if region.Lengths != (LengthProfile{Min: 4, Max: 5, Mean: 4.25}) {
+		t.Errorf("Table.Profile() lengths mismatch: want [4 5 4.25] got [%v]", region.Lengths)
+	}
+	if day.Type != ColumnTypeDate || day.Min != "2023-12-31" || day.Max != "2024-03-10" || day.Dates.Count != 4 {
+		t.Errorf("Table.Profile() date column mismatch: want [2023-12-31 2024-03-10] got [%s %s]", day.Min, day.Max)
+	}
+	if units.Type != ColumnTypeInt || units.Numeric == nil {
+		t.Fatalf("Table.Profile() numeric column mismatch: want [%s] got [%s]", ColumnTypeInt, units.Type)
+	}
+	np := units.Numeric
+	if np.Mean != 4 || math.Abs(np.StdDev-math.Sqrt(12.5)) > 1e-9 || np.Quantiles[1].Value != 3 {
+		t.Errorf("Table.Profile() numeric stats mismatch: want [4 %v 3] got [%v %v %v]", math.Sqrt(12.5), np.Mean, np.StdDev, np.Quantiles[1].Value)
+	}
+	if np.Histogram[0] != 1 || np.Histogram[4] != 0 || np.Histogram[9] != 1 {
+		t.Errorf("Table.Profile() histogram mismatch: got [%v]", np.Histogram)
+	}
+
+	if md := p.Markdown("\n"); !strings.Contains(md, "█") || !strings.Contains(md, "West (2)") {
+		t.Errorf("Profile.Markdown() mismatch: got [%s]", md)
+	}
+	if h := p.HTML(); !strings.Contains(h, "<svg") {
+		t.Errorf("Profile.HTML() mismatch: want inline svg got [%s]", h)
+	}
+	f, err := p.NewXLSX()
+	if err != nil {
+		t.Fatalf("Profile.NewXLSX() error: [%v]", err)
+	}
+	if sheets := f.GetSheetList(); len(sheets) != 3 || sheets[1] != ProfileSheetTopValues {
+		t.Errorf("Profile.NewXLSX() sheets mismatch: got [%v]", sheets)
+	}
+	if got, err := f.GetCellValue(ProfileSheetHistograms, "A2"); err != nil || got != "units" {
+		t.Errorf("Profile.NewXLSX() histogram mismatch: want [%s] got [%s]", "units", got)
+	}
+}
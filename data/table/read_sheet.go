@@ -0,0 +1,116 @@
+package table
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/gocharts/v2/data/table/odsutil"
+	"github.com/grokify/gocharts/v2/data/table/xlsutil"
+)
+
+// sheetFile is a spreadsheet with the table semantics of `excelizeutil.File`.
+type sheetFile interface {
+	SheetNames(sortAsc bool) []string
+	TableData(sheetName string, headerRowCount uint32, trimSpace, umerge bool) ([]string, [][]string, error)
+}
+
+func readTableSetSheetFile(sf sheetFile, headerRowCount uint32, trimSpace bool) (*TableSet, error) {
+	ts := NewTableSet("")
+	for _, sheetName := range sf.SheetNames(false) {
+		tbl, err := readTableSheetFile(sf, sheetName, headerRowCount, trimSpace)
+		if err != nil {
+			return nil, err
+		}
+		ts.TableMap[sheetName] = tbl
+	}
+	return ts, nil
+}
+
+func readTableSheetFile(sf sheetFile, sheetName string, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	cols, rows, err := sf.TableData(sheetName, headerRowCount, trimSpace, false)
+	if err != nil {
+		return nil, err
+	}
+	tbl := NewTable(sheetName)
+	tbl.Columns = cols
+	tbl.Rows = rows
+	return &tbl, nil
+}
+
+func readTableSheetFileIndex(sf sheetFile, sheetIdx, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	names := sf.SheetNames(false)
+	if int(sheetIdx) >= len(names) {
+		return nil, fmt.Errorf("sheet index not found [%d]", sheetIdx)
+	}
+	return readTableSheetFile(sf, names[sheetIdx], headerRowCount, trimSpace)
+}
+
+// ReadTableSetODSFile reads an OpenDocument Spreadsheet file as a `TableSet`,
+// like `ReadTableSetXLSXFile()`.
+func ReadTableSetODSFile(filename string, headerRowCount uint32, trimSpace bool) (*TableSet, error) {
+	f, err := odsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSetSheetFile(f, headerRowCount, trimSpace)
+}
+
+func ReadTableODSFile(filename, sheetName string, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	f, err := odsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSheetFile(f, sheetName, headerRowCount, trimSpace)
+}
+
+func ReadTableODSIndexFile(filename string, sheetIdx, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	f, err := odsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSheetFileIndex(f, sheetIdx, headerRowCount, trimSpace)
+}
+
+// ReadTableSetXLSFile reads a legacy Excel 97-2003 `.xls` file as a
+// `TableSet`, like `ReadTableSetXLSXFile()`. Merged cells are not supported
+// because the BIFF reader does not provide merged ranges, so a merged value is
+// only read in the top-left cell of its range.
+func ReadTableSetXLSFile(filename string, headerRowCount uint32, trimSpace bool) (*TableSet, error) {
+	f, err := xlsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSetSheetFile(f, headerRowCount, trimSpace)
+}
+
+func ReadTableXLSFile(filename, sheetName string, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	f, err := xlsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSheetFile(f, sheetName, headerRowCount, trimSpace)
+}
+
+func ReadTableXLSIndexFile(filename string, sheetIdx, headerRowCount uint32, trimSpace bool) (*Table, error) {
+	f, err := xlsutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return readTableSheetFileIndex(f, sheetIdx, headerRowCount, trimSpace)
+}
+
+// ReadTableSetSpreadsheetFile reads an `.xlsx`, `.xlsm`, `.ods` or `.xls` file
+// as a `TableSet`, using the file extension to determine the format.
+func ReadTableSetSpreadsheetFile(filename string, headerRowCount uint32, trimSpace bool) (*TableSet, error) {
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".xlsx", ".xlsm":
+		return ReadTableSetXLSXFile(filename, headerRowCount, trimSpace)
+	case ".ods":
+		return ReadTableSetODSFile(filename, headerRowCount, trimSpace)
+	case ".xls":
+		return ReadTableSetXLSFile(filename, headerRowCount, trimSpace)
+	default:
+		return nil, fmt.Errorf("spreadsheet file extension not supported [%s]", ext)
+	}
+}
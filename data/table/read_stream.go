@@ -0,0 +1,246 @@
+package table
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// RowReader reads delimited rows one at a time so that large files can be
+// processed without loading every record into `Table.Rows`. `ParseOptions`
+// are honoured as by `ParseReadSeeker`: `Comma`, `NoHeader`, `FieldsPerRecord`,
+// `FilterColNames`, `FilterColIndices` and `TrimSpace`.
+type RowReader struct {
+	Columns          Columns
+	csvReader        *csv.Reader
+	closer           io.Closer
+	indices          []uint
+	trimSpace        bool
+	errorOutOfBounds bool
+	read             bool
+}
+
+// NewRowReader returns a `RowReader` for `r`. Unless `opts.NoHeader` is set,
+// the header row is read immediately and used to set `Columns`. A leading
+// UTF-8 byte order mark is removed.
+func NewRowReader(opts *ParseOptions, r io.Reader) (*RowReader, error) {
+	if opts == nil {
+		opts = &ParseOptions{}
+	}
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+		if _, err := br.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+	}
+	csvReader := csv.NewReader(br)
+	csvReader.Comma = opts.CommaValue()
+	csvReader.FieldsPerRecord = opts.FieldsPerRecord
+	if opts.NoHeader {
+		csvReader.FieldsPerRecord = -1
+	}
+	rr := &RowReader{
+		Columns:          Columns{},
+		csvReader:        csvReader,
+		trimSpace:        opts.TrimSpace,
+		errorOutOfBounds: csvReader.FieldsPerRecord >= 0,
+	}
+
+	if opts.NoHeader {
+		if len(opts.FilterColNames) > 0 && len(opts.FilterColIndices) == 0 {
+			return nil, errors.New("filter column names require a header row")
+		}
+		rr.indices = opts.FilterColIndices
+		return rr, nil
+	}
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errors.New("no content")
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading header row: %w", err)
+	}
+	if opts.TrimSpace {
+		header = trimSpaceSliceString(header)
+	}
+	if !opts.HasFilter() {
+		rr.Columns = header
+		return rr, nil
+	}
+	cols := Columns(header)
+	if len(opts.FilterColIndices) > 0 {
+		rr.indices = opts.FilterColIndices
+	} else {
+		var notFound []string
+		for _, colName := range opts.FilterColNames {
+			if idx := cols.Index(colName); idx < 0 {
+				notFound = append(notFound, colName)
+			} else {
+				rr.indices = append(rr.indices, uint(idx)) //nolint:gosec // G115: idx is checked >= 0 above
+			}
+		}
+		if len(notFound) > 0 {
+			return nil, fmt.Errorf("filter columns not found [%s]", strings.Join(notFound, ","))
+		}
+	}
+	for _, idx := range rr.indices {
+		if idx >= uint(len(cols)) {
+			return nil, fmt.Errorf("want column index not found [%d]", idx)
+		}
+		rr.Columns = append(rr.Columns, cols[idx])
+	}
+	return rr, nil
+}
+
+// NewRowReaderFile opens `filename` and returns a `RowReader` for it. The
+// file is closed when `Close` is called.
+func NewRowReaderFile(opts *ParseOptions, filename string) (*RowReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	rr, err := NewRowReader(opts, f)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+	rr.closer = f
+	return rr, nil
+}
+
+// Close closes the underlying file for readers created with `NewRowReaderFile`.
+func (rr *RowReader) Close() error {
+	if rr.closer == nil {
+		return nil
+	}
+	err := rr.closer.Close()
+	rr.closer = nil
+	return err
+}
+
+// Rows returns an iterator over the data rows, excluding the header row.
+// Reading stops after the first error, which is yielded with a nil row. The
+// rows can only be iterated once.
+func (rr *RowReader) Rows() iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		if rr.read {
+			yield(nil, errors.New("rows already read"))
+			return
+		}
+		rr.read = true
+		for i := 0; ; i++ {
+			rec, err := rr.csvReader.Read()
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				yield(nil, fmt.Errorf("error reading row [%d]: %w", i, err))
+				return
+			}
+			row, err := rr.filter(rec)
+			if err != nil {
+				yield(nil, fmt.Errorf("error on row [%d]: %w", i, err))
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (rr *RowReader) filter(rec []string) ([]string, error) {
+	if rr.trimSpace {
+		rec = trimSpaceSliceString(rec)
+	}
+	if len(rr.indices) == 0 {
+		return rec, nil
+	}
+	row := make([]string, 0, len(rr.indices))
+	for _, idx := range rr.indices {
+		if idx < uint(len(rec)) {
+			row = append(row, rec[idx])
+		} else if rr.errorOutOfBounds {
+			return nil, fmt.Errorf("desired index out of bounds: index[%d] row len [%d]", idx, len(rec))
+		} else {
+			row = append(row, "")
+		}
+	}
+	return row, nil
+}
+
+// Table reads the remaining rows into a `Table`.
+func (rr *RowReader) Table() (Table, error) {
+	tbl := NewTable("")
+	tbl.Columns = rr.Columns
+	for row, err := range rr.Rows() {
+		if err != nil {
+			return tbl, err
+		}
+		tbl.Rows = append(tbl.Rows, row)
+	}
+	return tbl, nil
+}
+
+// ColumnValuesCounts reads the remaining rows and returns the count of each
+// value in column `colIdx`, as `Table.ColumnValuesCounts`.
+func (rr *RowReader) ColumnValuesCounts(colIdx int, trimSpace, includeEmpty, lowerCase bool) (map[string]int, error) {
+	return ColumnValuesCountsSeq(rr.Rows(), colIdx, trimSpace, includeEmpty, lowerCase)
+}
+
+// FilterColumnValuesRows returns an iterator over the remaining rows matching
+// `wantColNameValues`, as `Table.FilterColumnValuesRows`.
+func (rr *RowReader) FilterColumnValuesRows(wantColNameValues map[string][]string) (iter.Seq2[[]string, error], error) {
+	return FilterColumnValuesRowsSeq(rr.Columns, rr.Rows(), wantColNameValues)
+}
+
+// ColumnValuesCountsSeq returns the count of each value in column `colIdx`
+// for a sequence of rows, as `Table.ColumnValuesCounts`. The first error in
+// the sequence is returned with the counts up to that row.
+func ColumnValuesCountsSeq(rows iter.Seq2[[]string, error], colIdx int, trimSpace, includeEmpty, lowerCase bool) (map[string]int, error) {
+	m := map[string]int{}
+	if colIdx < 0 {
+		return m, nil
+	}
+	for row, err := range rows {
+		if err != nil {
+			return m, err
+		}
+		columnValuesCountsAdd(m, row, colIdx, trimSpace, includeEmpty, lowerCase)
+	}
+	return m, nil
+}
+
+// FilterColumnValuesRowsSeq returns an iterator over the rows matching
+// `wantColNameValues`, as `Table.FilterColumnValuesRows`. Errors in `rows`
+// are passed through.
+func FilterColumnValuesRowsSeq(cols Columns, rows iter.Seq2[[]string, error], wantColNameValues map[string][]string) (iter.Seq2[[]string, error], error) {
+	match, err := columnValuesMatcher(cols, wantColNameValues)
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func([]string, error) bool) {
+		for row, err := range rows {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if match(row) && !yield(row, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func trimSpaceSliceString(s []string) []string {
+	for i, cell := range s {
+		s[i] = strings.TrimSpace(cell)
+	}
+	return s
+}
@@ -1,6 +1,7 @@
 package table
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -39,3 +40,35 @@ func TestReadFile(t *testing.T) {
 		}
 	}
 }
+
+func TestRowReader(t *testing.T) {
+	data := "\xEF\xBB\xBFid, name ,status\n1, foo ,open\n2,bar,closed\n3,baz,open\n"
+	rr, err := NewRowReader(&ParseOptions{
+		FilterColNames: []string{"status", "name"},
+		TrimSpace:      true}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("table.NewRowReader() Error: [%v]", err)
+	}
+	if strings.Join(rr.Columns, ",") != "status,name" {
+		t.Errorf("table.NewRowReader() Columns mismatch: want [status,name] got [%s]", strings.Join(rr.Columns, ","))
+	}
+	rows, err := rr.FilterColumnValuesRows(map[string][]string{"status": {"open"}})
+	if err != nil {
+		t.Fatalf("RowReader.FilterColumnValuesRows() Error: [%v]", err)
+	}
+	counts, err := ColumnValuesCountsSeq(rows, 1, false, false, false)
+	if err != nil {
+		t.Fatalf("table.ColumnValuesCountsSeq() Error: [%v]", err)
+	}
+	if len(counts) != 2 || counts["foo"] != 1 || counts["baz"] != 1 {
+		t.Errorf("table.ColumnValuesCountsSeq() mismatch: want [map[baz:1 foo:1]] got [%v]", counts)
+	}
+
+	rr, err = NewRowReader(&ParseOptions{UseComma: true, Comma: ';'}, strings.NewReader("a;b\n1;2\n3\n"))
+	if err != nil {
+		t.Fatalf("table.NewRowReader() Error: [%v]", err)
+	}
+	if _, err := rr.Table(); err == nil {
+		t.Error("RowReader.Table() expected error for wrong number of fields")
+	}
+}
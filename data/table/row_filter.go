@@ -0,0 +1,381 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FilterOp string
+
+const (
+	FilterOpEq          FilterOp = "eq"
+	FilterOpNotEq       FilterOp = "neq"
+	FilterOpLess        FilterOp = "lt"  // numeric
+	FilterOpLessEq      FilterOp = "lte" // numeric
+	FilterOpGreater     FilterOp = "gt"  // numeric
+	FilterOpGreaterEq   FilterOp = "gte" // numeric
+	FilterOpBetween     FilterOp = "between"
+	FilterOpDateBetween FilterOp = "date_between"
+	FilterOpRegex       FilterOp = "regex"
+	FilterOpPrefix      FilterOp = "prefix"
+	FilterOpSuffix      FilterOp = "suffix"
+	FilterOpIn          FilterOp = "in"
+	FilterOpNotIn       FilterOp = "not_in"
+	FilterOpEmpty       FilterOp = "empty"
+	FilterOpNotEmpty    FilterOp = "not_empty"
+	FilterOpAnd         FilterOp = "and"
+	FilterOpOr          FilterOp = "or"
+	FilterOpNot         FilterOp = "not"
+)
+
+// Filter is a declarative row filter which can be built in Go with the
+// `Filter*` functions, parsed from an expression or JSON spec with `ParseFilter`
+// and applied with `Table.FilterRows`, `Table.FilterTable` or
+// `TableSet.Filter`.
+//
+// Numeric ops parse cells as numbers and date ops parse cells with
+// `time.DateOnly`, `time.RFC3339` or `time.DateTime`, using the table schema
+// where set. Cells which cannot be parsed do not match. Range bounds in `Min`
+// and `Max` are inclusive and an empty bound is unbounded.
+type Filter struct {
+	Op         FilterOp `json:"op"`
+	Column     string   `json:"column,omitempty"`
+	Value      string   `json:"value,omitempty"`
+	Values     []string `json:"values,omitempty"`
+	Min        string   `json:"min,omitempty"`
+	Max        string   `json:"max,omitempty"`
+	IgnoreCase bool     `json:"ignoreCase,omitempty"` // for string ops
+	Filters    []Filter `json:"filters,omitempty"`    // for `and`, `or` and `not`
+}
+
+func FilterEq(colName, val string) Filter {
+	return Filter{Op: FilterOpEq, Column: colName, Value: val}
+}
+
+func FilterNotEq(colName, val string) Filter {
+	return Filter{Op: FilterOpNotEq, Column: colName, Value: val}
+}
+
+func FilterLess(colName string, val float64) Filter {
+	return Filter{Op: FilterOpLess, Column: colName, Value: formatFilterFloat(val)}
+}
+
+func FilterLessEq(colName string, val float64) Filter {
+	return Filter{Op: FilterOpLessEq, Column: colName, Value: formatFilterFloat(val)}
+}
+
+func FilterGreater(colName string, val float64) Filter {
+	return Filter{Op: FilterOpGreater, Column: colName, Value: formatFilterFloat(val)}
+}
+
+func FilterGreaterEq(colName string, val float64) Filter {
+	return Filter{Op: FilterOpGreaterEq, Column: colName, Value: formatFilterFloat(val)}
+}
+
+// FilterBetween matches numbers from `minVal` to `maxVal` inclusive.
+func FilterBetween(colName string, minVal, maxVal float64) Filter {
+	return Filter{Op: FilterOpBetween, Column: colName, Min: formatFilterFloat(minVal), Max: formatFilterFloat(maxVal)}
+}
+
+// FilterDateBetween matches dates from `minTime` to `maxTime` inclusive. A zero
+// time is unbounded.
+func FilterDateBetween(colName string, minTime, maxTime time.Time) Filter {
+	f := Filter{Op: FilterOpDateBetween, Column: colName}
+	if !minTime.IsZero() {
+		f.Min = minTime.Format(time.RFC3339Nano)
+	}
+	if !maxTime.IsZero() {
+		f.Max = maxTime.Format(time.RFC3339Nano)
+	}
+	return f
+}
+
+func FilterRegex(colName, pattern string) Filter {
+	return Filter{Op: FilterOpRegex, Column: colName, Value: pattern}
+}
+
+func FilterPrefix(colName, prefix string) Filter {
+	return Filter{Op: FilterOpPrefix, Column: colName, Value: prefix}
+}
+
+func FilterSuffix(colName, suffix string) Filter {
+	return Filter{Op: FilterOpSuffix, Column: colName, Value: suffix}
+}
+
+func FilterIn(colName string, vals ...string) Filter {
+	return Filter{Op: FilterOpIn, Column: colName, Values: vals}
+}
+
+func FilterNotIn(colName string, vals ...string) Filter {
+	return Filter{Op: FilterOpNotIn, Column: colName, Values: vals}
+}
+
+// FilterEmpty matches empty and whitespace only values.
+func FilterEmpty(colName string) Filter {
+	return Filter{Op: FilterOpEmpty, Column: colName}
+}
+
+func FilterNotEmpty(colName string) Filter {
+	return Filter{Op: FilterOpNotEmpty, Column: colName}
+}
+
+// FilterAnd matches rows matching all filters. It matches all rows if no
+// filters are provided.
+func FilterAnd(filters ...Filter) Filter {
+	return Filter{Op: FilterOpAnd, Filters: filters}
+}
+
+// FilterOr matches rows matching any filter. It matches no rows if no filters
+// are provided.
+func FilterOr(filters ...Filter) Filter {
+	return Filter{Op: FilterOpOr, Filters: filters}
+}
+
+func FilterNot(f Filter) Filter {
+	return Filter{Op: FilterOpNot, Filters: []Filter{f}}
+}
+
+func formatFilterFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// FilterRows returns the rows matching the filter.
+func (tbl *Table) FilterRows(f Filter) ([][]string, error) {
+	match, err := f.matcher(tbl.Columns, tbl.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, row := range tbl.Rows {
+		if match(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// FilterTable returns a Table with the rows matching the filter.
+func (tbl *Table) FilterTable(f Filter) (*Table, error) {
+	if tbl.IsFloat64 {
+		return nil, errors.New("cannot filter float table on string values")
+	}
+	rows, err := tbl.FilterRows(f)
+	if err != nil {
+		return nil, err
+	}
+	out := tbl.Clone(false)
+	for _, row := range rows {
+		out.Rows = append(out.Rows, slices.Clone(row))
+	}
+	return out, nil
+}
+
+// Filter returns a TableSet with every table filtered by `f`. Each table must
+// have the columns used by the filter.
+func (ts *TableSet) Filter(f Filter) (*TableSet, error) {
+	out := NewTableSet(ts.Name)
+	out.Columns = slices.Clone(ts.Columns)
+	maps.Copy(out.FormatMap, ts.FormatMap)
+	out.FormatFunc = ts.FormatFunc
+	out.Order = slices.Clone(ts.Order)
+	out.Relationships = slices.Clone(ts.Relationships)
+	for name, tbl := range ts.TableMap {
+		filtered, err := tbl.FilterTable(f)
+		if err != nil {
+			return nil, fmt.Errorf("table [%s]: %w", name, err)
+		}
+		out.TableMap[name] = filtered
+	}
+	return out, nil
+}
+
+// Matcher returns a function reporting whether a row with columns `cols`
+// matches the filter. It can be used to filter rows without a `Table`, such
+// as when streaming.
+func (f Filter) Matcher(cols []string) (func(row []string) bool, error) {
+	return f.matcher(cols, nil)
+}
+
+func (f Filter) matcher(cols Columns, sch *Schema) (func(row []string) bool, error) {
+	switch f.Op {
+	case FilterOpAnd, FilterOpOr, FilterOpNot:
+		return f.groupMatcher(cols, sch)
+	case "":
+		return nil, errors.New("filter op not provided")
+	}
+	if f.Column == "" {
+		return nil, fmt.Errorf("filter column not provided for op [%s]", f.Op)
+	}
+	colIdx := cols.Index(f.Column)
+	if colIdx < 0 {
+		return nil, fmt.Errorf("column not found [%s]", f.Column)
+	}
+	var cs *ColumnSchema
+	if sch != nil {
+		cs = sch.Column(f.Column)
+	}
+	match, err := f.valueMatcher(cs)
+	if err != nil {
+		return nil, err
+	}
+	return func(row []string) bool {
+		if colIdx < len(row) {
+			return match(row[colIdx])
+		}
+		return match("")
+	}, nil
+}
+
+func (f Filter) groupMatcher(cols Columns, sch *Schema) (func(row []string) bool, error) {
+	if f.Op == FilterOpNot && len(f.Filters) != 1 {
+		return nil, fmt.Errorf("filter op [not] requires 1 filter, got [%d]", len(f.Filters))
+	}
+	matches := make([]func(row []string) bool, len(f.Filters))
+	for i, sub := range f.Filters {
+		match, err := sub.matcher(cols, sch)
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = match
+	}
+	switch f.Op {
+	case FilterOpNot:
+		return func(row []string) bool { return !matches[0](row) }, nil
+	case FilterOpOr:
+		return func(row []string) bool {
+			for _, match := range matches {
+				if match(row) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	default:
+		return func(row []string) bool {
+			for _, match := range matches {
+				if !match(row) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+}
+
+// valueMatcher returns a function reporting whether a cell value matches a
+// column filter.
+func (f Filter) valueMatcher(cs *ColumnSchema) (func(val string) bool, error) {
+	equal := func(a, b string) bool { return a == b }
+	if f.IgnoreCase {
+		equal = strings.EqualFold
+	}
+	switch f.Op {
+	case FilterOpEq, FilterOpNotEq:
+		want := f.Op == FilterOpEq
+		return func(val string) bool { return equal(val, f.Value) == want }, nil
+	case FilterOpIn, FilterOpNotIn:
+		want := f.Op == FilterOpIn
+		return func(val string) bool {
+			return slices.ContainsFunc(f.Values, func(v string) bool { return equal(val, v) }) == want
+		}, nil
+	case FilterOpPrefix, FilterOpSuffix:
+		has := strings.HasPrefix
+		if f.Op == FilterOpSuffix {
+			has = strings.HasSuffix
+		}
+		if f.IgnoreCase {
+			want := strings.ToLower(f.Value)
+			return func(val string) bool { return has(strings.ToLower(val), want) }, nil
+		}
+		return func(val string) bool { return has(val, f.Value) }, nil
+	case FilterOpRegex:
+		pattern := f.Value
+		if f.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		rx, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter regex [%s]: %w", f.Value, err)
+		}
+		return rx.MatchString, nil
+	case FilterOpEmpty, FilterOpNotEmpty:
+		want := f.Op == FilterOpEmpty
+		return func(val string) bool { return (strings.TrimSpace(val) == "") == want }, nil
+	case FilterOpLess, FilterOpLessEq, FilterOpGreater, FilterOpGreaterEq:
+		want, err := strconv.ParseFloat(strings.TrimSpace(f.Value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter number [%s]", f.Value)
+		}
+		lo, hi := math.Inf(-1), math.Inf(1)
+		incl := f.Op == FilterOpLessEq || f.Op == FilterOpGreaterEq
+		if f.Op == FilterOpLess || f.Op == FilterOpLessEq {
+			hi = want
+		} else {
+			lo = want
+		}
+		return numberRangeMatcher(cs, lo, hi, incl), nil
+	case FilterOpBetween:
+		lo, hi := math.Inf(-1), math.Inf(1)
+		for _, b := range []struct {
+			s string
+			f *float64
+		}{{f.Min, &lo}, {f.Max, &hi}} {
+			if s := strings.TrimSpace(b.s); s != "" {
+				v, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid filter number [%s]", b.s)
+				}
+				*b.f = v
+			}
+		}
+		return numberRangeMatcher(cs, lo, hi, true), nil
+	case FilterOpDateBetween:
+		layout := ""
+		if cs != nil {
+			layout = cs.Layout
+		}
+		var lo, hi time.Time
+		for _, b := range []struct {
+			s string
+			t *time.Time
+		}{{f.Min, &lo}, {f.Max, &hi}} {
+			if s := strings.TrimSpace(b.s); s != "" {
+				t, err := parseTime(s, "", time.DateOnly, time.RFC3339Nano, time.DateTime, layout)
+				if err != nil {
+					return nil, fmt.Errorf("invalid filter date [%s]", b.s)
+				}
+				*b.t = t
+			}
+		}
+		return func(val string) bool {
+			t, err := parseTime(strings.TrimSpace(val), layout, time.DateOnly, time.RFC3339Nano, time.DateTime)
+			return err == nil &&
+				(lo.IsZero() || !t.Before(lo)) &&
+				(hi.IsZero() || !t.After(hi))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown filter op [%s]", f.Op)
+	}
+}
+
+// numberRangeMatcher returns a function reporting whether a cell value is a
+// number between `lo` and `hi`, with the bounds included if `incl` is true.
+func numberRangeMatcher(cs *ColumnSchema, lo, hi float64, incl bool) func(val string) bool {
+	parse := schemaFloat64Parser(cs)
+	return func(val string) bool {
+		f, err := parse(strings.TrimSpace(val))
+		if err != nil {
+			return false
+		} else if incl {
+			return f >= lo && f <= hi
+		}
+		return f > lo && f < hi
+	}
+}
@@ -0,0 +1,334 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/gocharts/v2/data/table/expr"
+)
+
+// ParseFilter parses a filter from a JSON spec, if `s` begins with `{`, or
+// from an expression in the `expr` syntax, such as:
+//
+//	status == 200 and (in(country, "US", "CA") or not startswith(lower(name), "a"))
+//	between(revenue, 1000, null) and between(day, date("2024-01-01"), date("2024-03-31"))
+//
+// Conditions compare a column to a literal with `==`, `!=`, `<`, `<=`, `>` or
+// `>=`, where `== null` and `!= null` match empty values, or call `in`,
+// `between`, `matches`, `startswith`, `endswith` or `isempty` with the column
+// as the first argument. Conditions are combined with `and`, `or`, `not` and
+// parentheses. Wrapping the column in `lower()` ignores case and `between`
+// bounds given with `date()` compare dates. The expression is translated to a
+// `Filter`, so cells are matched with the filter ops rather than evaluated as
+// an expression. An empty string matches all rows.
+func ParseFilter(s string) (Filter, error) {
+	if strings.HasPrefix(strings.TrimSpace(s), "{") {
+		var f Filter
+		err := json.Unmarshal([]byte(s), &f)
+		return f, err
+	}
+	if strings.TrimSpace(s) == "" {
+		return FilterAnd(), nil
+	}
+	e, err := expr.Parse(s)
+	if err != nil {
+		return Filter{}, err
+	}
+	return filterFromNode(e.Root())
+}
+
+// String returns the filter as an expression accepted by `ParseFilter`.
+func (f Filter) String() string {
+	col := expr.QuoteColumn(f.Column)
+	val := f.Value
+	vals := f.Values
+	if f.IgnoreCase && f.Op != FilterOpRegex {
+		col = "lower(" + col + ")"
+		val = strings.ToLower(val)
+		vals = make([]string, len(f.Values))
+		for i, v := range f.Values {
+			vals[i] = strings.ToLower(v)
+		}
+	}
+	switch f.Op {
+	case FilterOpAnd, FilterOpOr:
+		if len(f.Filters) == 0 {
+			return strconv.FormatBool(f.Op == FilterOpAnd)
+		}
+		parts := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			parts[i] = sub.groupString()
+		}
+		return strings.Join(parts, " "+string(f.Op)+" ")
+	case FilterOpNot:
+		if len(f.Filters) == 1 {
+			return "not " + f.Filters[0].groupString()
+		}
+	case FilterOpEq:
+		return col + " == " + filterLiteral(val)
+	case FilterOpNotEq:
+		return col + " != " + filterLiteral(val)
+	case FilterOpLess:
+		return col + " < " + filterLiteral(val)
+	case FilterOpLessEq:
+		return col + " <= " + filterLiteral(val)
+	case FilterOpGreater:
+		return col + " > " + filterLiteral(val)
+	case FilterOpGreaterEq:
+		return col + " >= " + filterLiteral(val)
+	case FilterOpBetween:
+		return "between(" + col + ", " + filterBound(f.Min, false) + ", " + filterBound(f.Max, false) + ")"
+	case FilterOpDateBetween:
+		return "between(" + col + ", " + filterBound(f.Min, true) + ", " + filterBound(f.Max, true) + ")"
+	case FilterOpRegex:
+		if f.IgnoreCase {
+			val = "(?i)" + val
+		}
+		return "matches(" + col + ", " + expr.QuoteString(val) + ")"
+	case FilterOpPrefix:
+		return "startswith(" + col + ", " + expr.QuoteString(val) + ")"
+	case FilterOpSuffix:
+		return "endswith(" + col + ", " + expr.QuoteString(val) + ")"
+	case FilterOpIn, FilterOpNotIn:
+		args := []string{col}
+		for _, v := range vals {
+			args = append(args, filterLiteral(v))
+		}
+		s := "in(" + strings.Join(args, ", ") + ")"
+		if f.Op == FilterOpNotIn {
+			return "not " + s
+		}
+		return s
+	case FilterOpEmpty:
+		return "isempty(" + col + ")"
+	case FilterOpNotEmpty:
+		return "not isempty(" + col + ")"
+	}
+	return string(f.Op) + "(" + col + ")"
+}
+
+// groupString returns the filter string in parentheses if it has more than
+// one condition.
+func (f Filter) groupString() string {
+	if (f.Op == FilterOpAnd || f.Op == FilterOpOr) && len(f.Filters) > 1 {
+		return "(" + f.String() + ")"
+	}
+	return f.String()
+}
+
+// filterLiteral returns a value as a number literal if it formats back
+// unchanged, and otherwise as a string literal.
+func filterLiteral(v string) string {
+	if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && expr.FormatValue(f) == v {
+		return v
+	}
+	return expr.QuoteString(v)
+}
+
+// filterBound returns a range bound as a literal, with an empty bound as null.
+func filterBound(v string, isDate bool) string {
+	switch {
+	case v == "":
+		return "null"
+	case isDate:
+		return "date(" + expr.QuoteString(v) + ")"
+	default:
+		return filterLiteral(v)
+	}
+}
+
+// filterCompareOps maps comparison operators to filter ops.
+var filterCompareOps = map[string]FilterOp{
+	"==": FilterOpEq,
+	"!=": FilterOpNotEq,
+	"<":  FilterOpLess,
+	"<=": FilterOpLessEq,
+	">":  FilterOpGreater,
+	">=": FilterOpGreaterEq,
+}
+
+// filterFlippedOps maps comparison operators to the operators with the
+// operands swapped.
+var filterFlippedOps = map[string]string{
+	"==": "==", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<=",
+}
+
+func filterFromNode(n expr.Node) (Filter, error) {
+	switch n.Kind {
+	case expr.NodeLiteral:
+		if b, ok := n.Value.(bool); ok {
+			if b {
+				return FilterAnd(), nil
+			}
+			return FilterOr(), nil
+		}
+	case expr.NodeUnary:
+		if n.Op != "!" {
+			break
+		}
+		sub, err := filterFromNode(n.Args[0])
+		if err != nil {
+			return Filter{}, err
+		}
+		switch sub.Op {
+		case FilterOpIn:
+			sub.Op = FilterOpNotIn
+			return sub, nil
+		case FilterOpEmpty:
+			sub.Op = FilterOpNotEmpty
+			return sub, nil
+		}
+		return FilterNot(sub), nil
+	case expr.NodeBinary:
+		if n.Op == "&&" || n.Op == "||" {
+			filters, err := filterGroupFromNode(n, n.Op)
+			if err != nil {
+				return Filter{}, err
+			}
+			if n.Op == "&&" {
+				return FilterAnd(filters...), nil
+			}
+			return FilterOr(filters...), nil
+		} else if _, ok := filterCompareOps[n.Op]; ok {
+			return filterCompareFromNode(n)
+		}
+	case expr.NodeCall:
+		return filterCallFromNode(n)
+	}
+	return Filter{}, fmt.Errorf("unsupported filter condition [%s]", n)
+}
+
+// filterGroupFromNode returns the filters of a chain of `&&` or `||` nodes.
+func filterGroupFromNode(n expr.Node, op string) ([]Filter, error) {
+	var filters []Filter
+	for _, arg := range n.Args {
+		if arg.Kind == expr.NodeBinary && arg.Op == op {
+			subs, err := filterGroupFromNode(arg, op)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, subs...)
+			continue
+		}
+		sub, err := filterFromNode(arg)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, sub)
+	}
+	return filters, nil
+}
+
+func filterCompareFromNode(n expr.Node) (Filter, error) {
+	op, colNode, valNode := n.Op, n.Args[0], n.Args[1]
+	if _, _, ok := filterColumn(colNode); !ok {
+		op, colNode, valNode = filterFlippedOps[op], valNode, colNode
+	}
+	col, ignoreCase, ok := filterColumn(colNode)
+	if !ok {
+		return Filter{}, fmt.Errorf("filter condition requires a column [%s]", n)
+	}
+	val, isNull, isDate, ok := filterValue(valNode)
+	if !ok {
+		return Filter{}, fmt.Errorf("filter condition requires a literal value [%s]", n)
+	}
+	f := Filter{Op: filterCompareOps[op], Column: col, Value: val}
+	switch {
+	case isDate && op == ">=":
+		return Filter{Op: FilterOpDateBetween, Column: col, Min: val}, nil
+	case isDate && op == "<=":
+		return Filter{Op: FilterOpDateBetween, Column: col, Max: val}, nil
+	case isDate:
+		return Filter{}, fmt.Errorf("filter dates support [>=] and [<=] only [%s]", n)
+	case isNull && op == "==":
+		return FilterEmpty(col), nil
+	case isNull && op == "!=":
+		return FilterNotEmpty(col), nil
+	case isNull:
+		return Filter{}, fmt.Errorf("filter condition cannot compare to null [%s]", n)
+	case ignoreCase && op != "==" && op != "!=":
+		return Filter{}, fmt.Errorf("filter condition cannot ignore case [%s]", n)
+	}
+	f.IgnoreCase = ignoreCase
+	return f, nil
+}
+
+func filterCallFromNode(n expr.Node) (Filter, error) {
+	var col string
+	var ignoreCase, ok bool
+	if len(n.Args) > 0 {
+		col, ignoreCase, ok = filterColumn(n.Args[0])
+	}
+	if !ok {
+		return Filter{}, fmt.Errorf("filter function requires a column as the first argument [%s]", n)
+	}
+	vals := make([]string, len(n.Args)-1)
+	var isDate bool
+	for i, arg := range n.Args[1:] {
+		val, isNull, argDate, ok := filterValue(arg)
+		if !ok || isNull && n.Name != "between" {
+			return Filter{}, fmt.Errorf("filter function requires literal values [%s]", n)
+		}
+		vals[i] = val
+		isDate = isDate || argDate
+	}
+	var f Filter
+	switch n.Name {
+	case "in":
+		f = FilterIn(col, vals...)
+	case "between":
+		f = Filter{Op: FilterOpBetween, Column: col, Min: vals[0], Max: vals[1]}
+		if isDate {
+			f.Op = FilterOpDateBetween
+		}
+	case "matches":
+		f = FilterRegex(col, vals[0])
+	case "startswith":
+		f = FilterPrefix(col, vals[0])
+	case "endswith":
+		f = FilterSuffix(col, vals[0])
+	case "isempty":
+		return FilterEmpty(col), nil
+	default:
+		return Filter{}, fmt.Errorf("unsupported filter function [%s]", n.Name)
+	}
+	if ignoreCase && n.Name == "between" {
+		return Filter{}, fmt.Errorf("filter condition cannot ignore case [%s]", n)
+	}
+	f.IgnoreCase = ignoreCase
+	return f, nil
+}
+
+// filterColumn returns the column name of a column node or of a column in
+// `lower()`, which ignores case.
+func filterColumn(n expr.Node) (string, bool, bool) {
+	if n.Kind == expr.NodeCall && n.Name == "lower" && len(n.Args) == 1 && n.Args[0].Kind == expr.NodeColumn {
+		return n.Args[0].Name, true, true
+	}
+	return n.Name, false, n.Kind == expr.NodeColumn
+}
+
+// filterValue returns the value of a literal, a negated number or a `date()`
+// call with a literal argument.
+func filterValue(n expr.Node) (val string, isNull, isDate, ok bool) {
+	switch n.Kind {
+	case expr.NodeLiteral:
+		return expr.FormatValue(n.Value), n.Value == nil, false, true
+	case expr.NodeUnary:
+		if f, isNum := n.Args[0].Value.(float64); isNum && n.Args[0].Kind == expr.NodeLiteral {
+			if n.Op == "-" {
+				f = -f
+			}
+			return expr.FormatValue(f), false, false, n.Op != "!"
+		}
+	case expr.NodeCall:
+		if n.Name == "date" && len(n.Args) == 1 {
+			if s, isStr := n.Args[0].Value.(string); isStr && n.Args[0].Kind == expr.NodeLiteral {
+				return s, false, true, true
+			}
+		}
+	}
+	return "", false, false, false
+}
@@ -0,0 +1,87 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterTable(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"name", "country", "revenue", "day"}
+	tbl.Rows = [][]string{
+		{"Alice", "US", "1200", "2024-01-15"},
+		{"bob", "CA", "300", "2024-02-01"},
+		{"Carol", "DE", "", "2024-03-15"},
+		{"alan", "US", "80", ""},
+	}
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{FilterAnd(FilterIn("country", "US", "CA"), FilterNot(FilterPrefix("name", "b"))), "Alice,alan"},
+		{Filter{Op: FilterOpPrefix, Column: "name", Value: "A", IgnoreCase: true}, "Alice,alan"},
+		{FilterOr(FilterGreater("revenue", 1000), FilterEmpty("revenue")), "Alice,Carol"},
+		{FilterBetween("revenue", 80, 300), "bob,alan"},
+		{FilterDateBetween("day", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Time{}), "bob,Carol"},
+		{FilterAnd(FilterRegex("name", `^[A-Z]`), FilterNotEq("country", "US")), "Carol"},
+	}
+	for _, tt := range tests {
+		for _, f := range []Filter{tt.filter, mustParseFilter(t, tt.filter.String())} {
+			out, err := tbl.FilterTable(f)
+			if err != nil {
+				t.Fatalf("Table.FilterTable(%s) error: [%v]", f, err)
+			}
+			var got []string
+			for _, row := range out.Rows {
+				got = append(got, row[0])
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("Table.FilterTable(%s) mismatch: want [%s] got [%s]", f, tt.want, strings.Join(got, ","))
+			}
+		}
+	}
+	if _, err := tbl.FilterTable(FilterEq("missing", "x")); err == nil {
+		t.Error("Table.FilterTable() expected error for unknown column")
+	}
+}
+
+func mustParseFilter(t *testing.T, s string) Filter {
+	t.Helper()
+	f, err := ParseFilter(s)
+	if err != nil {
+		t.Fatalf("ParseFilter(%s) error: [%v]", s, err)
+	}
+	return f
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{`status == 200 and (in(country, "US", 'New Zealand') or not startswith(lower(name), "A"))`,
+			`status == 200 and (in(country, "US", "New Zealand") or not startswith(lower(name), "a"))`},
+		{`1000 <= revenue && day >= date("2024-01-01") || isempty([Sales Region])`,
+			`(revenue >= 1000 and between(day, date("2024-01-01"), null)) or isempty([Sales Region])`},
+		{`not in(code, "007", -1) and matches(name, "^\\w+$") and between(revenue, null, 2.5)`,
+			`not in(code, "007", "-1") and matches(name, "^\\w+$") and between(revenue, null, 2.5)`},
+		{`a != null and b == null and lower(c) != "X"`,
+			`not isempty(a) and isempty(b) and lower(c) != "x"`},
+		{`{"op":"or","filters":[{"op":"eq","column":"a","value":"x"},{"op":"not_empty","column":"b"}]}`,
+			`a == "x" or not isempty(b)`},
+		{``, `true`},
+		{`false`, `false`},
+	}
+	for _, tt := range tests {
+		if got := mustParseFilter(t, tt.spec).String(); got != tt.want {
+			t.Errorf("ParseFilter(%s) mismatch: want [%s] got [%s]", tt.spec, tt.want, got)
+		}
+	}
+	for _, spec := range []string{`a ==`, `a like b`, `(a == 1`, `a == b`, `a + 1 > 2`, `contains(a, "x")`,
+		`in("x", a)`, `a < null`, `day > date("2024-01-01")`, `between(lower(a), 1, 2)`, `lower(a) < 1`} {
+		if _, err := ParseFilter(spec); err == nil {
+			t.Errorf("ParseFilter(%s) expected error", spec)
+		}
+	}
+}
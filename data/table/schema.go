@@ -0,0 +1,566 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType is a declared column data type used by `Schema`.
+type ColumnType string
+
+const (
+	ColumnTypeString   ColumnType = "string"
+	ColumnTypeInt      ColumnType = "int"
+	ColumnTypeFloat    ColumnType = "float"
+	ColumnTypeDecimal  ColumnType = "decimal"  // number with thousands separators and fixed precision
+	ColumnTypeBool     ColumnType = "bool"     // true/false, yes/no, y/n, 1/0
+	ColumnTypeDate     ColumnType = "date"     // `time.DateOnly` unless `ColumnSchema.Layout` is set
+	ColumnTypeDatetime ColumnType = "datetime" // `time.RFC3339` or `time.DateTime` unless `ColumnSchema.Layout` is set
+	ColumnTypeDuration ColumnType = "duration" // `time.ParseDuration` format
+	ColumnTypePercent  ColumnType = "percent"  // "12.5%" or the fraction "0.125"
+	ColumnTypeCurrency ColumnType = "currency" // number with optional currency symbol and thousands separators
+)
+
+const (
+	defaultCurrencySymbol = "$"
+	defaultPrecision      = 2
+
+	// decimalDigits is the number of digits of decimal and currency columns
+	// written to typed formats such as Parquet and Arrow.
+	decimalDigits = 18
+)
+
+var maxUnscaledDecimal = new(big.Int).Exp(big.NewInt(10), big.NewInt(decimalDigits), nil)
+
+// ColumnTypes returns the supported column types.
+func ColumnTypes() []ColumnType {
+	return []ColumnType{
+		ColumnTypeString,
+		ColumnTypeInt,
+		ColumnTypeFloat,
+		ColumnTypeDecimal,
+		ColumnTypeBool,
+		ColumnTypeDate,
+		ColumnTypeDatetime,
+		ColumnTypeDuration,
+		ColumnTypePercent,
+		ColumnTypeCurrency,
+	}
+}
+
+// IsNumeric returns true for types parsed to a number.
+func (ct ColumnType) IsNumeric() bool {
+	switch ct {
+	case ColumnTypeInt, ColumnTypeFloat, ColumnTypeDecimal, ColumnTypePercent, ColumnTypeCurrency:
+		return true
+	default:
+		return false
+	}
+}
+
+// ColumnSchema declares the name and type of a column.
+type ColumnSchema struct {
+	Name           string
+	Type           ColumnType
+	Required       bool   // empty values are invalid
+	Layout         string // time layout for date and datetime columns
+	Precision      *int   // decimal places for decimal, percent and currency display, default 2
+	CurrencySymbol string // currency symbol, default "$"
+}
+
+// Schema declares column types for a `Table`. Schema columns are matched to
+// table columns by name or, when the table has no columns, by position.
+type Schema struct {
+	Columns []ColumnSchema
+}
+
+// NewSchema returns a `Schema` with string columns for the supplied names.
+func NewSchema(colNames ...string) *Schema {
+	sch := &Schema{}
+	for _, colName := range colNames {
+		sch.Columns = append(sch.Columns, ColumnSchema{Name: colName, Type: ColumnTypeString})
+	}
+	return sch
+}
+
+// Column returns the column schema for `colName` or `nil` if not found.
+func (sch *Schema) Column(colName string) *ColumnSchema {
+	for i, cs := range sch.Columns {
+		if cs.Name == colName {
+			return &sch.Columns[i]
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the column schema for each table column index, with
+// `nil` for columns not in the schema.
+func (sch *Schema) tableColumns(cols Columns) []*ColumnSchema {
+	if len(cols) == 0 {
+		out := make([]*ColumnSchema, len(sch.Columns))
+		for i := range sch.Columns {
+			out[i] = &sch.Columns[i]
+		}
+		return out
+	}
+	out := make([]*ColumnSchema, len(cols))
+	for i, colName := range cols {
+		out[i] = sch.Column(colName)
+	}
+	return out
+}
+
+func (cs *ColumnSchema) precision() int {
+	if cs.Precision != nil && *cs.Precision >= 0 {
+		return *cs.Precision
+	}
+	return defaultPrecision
+}
+
+func (cs *ColumnSchema) currencySymbol() string {
+	if cs.CurrencySymbol != "" {
+		return cs.CurrencySymbol
+	}
+	return defaultCurrencySymbol
+}
+
+// Parse converts a cell value to its typed Go value: `string`, `int`, `float64`,
+// `bool`, `time.Time` or `time.Duration`. Percents are returned as fractions.
+// Empty values are returned as `nil`.
+func (cs *ColumnSchema) Parse(val string) (any, error) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		if cs.Required {
+			return nil, errors.New("required value is empty")
+		}
+		return nil, nil
+	}
+	var v any
+	var err error
+	switch cs.Type {
+	case ColumnTypeString, "":
+		v = val
+	case ColumnTypeInt:
+		v, err = strconv.Atoi(val)
+	case ColumnTypeFloat:
+		v, err = strconv.ParseFloat(val, 64)
+	case ColumnTypeDecimal:
+		v, err = parseDecimal(val)
+	case ColumnTypeBool:
+		v, err = parseBool(val)
+	case ColumnTypeDate:
+		v, err = parseTime(val, cs.Layout, time.DateOnly)
+	case ColumnTypeDatetime:
+		v, err = parseTime(val, cs.Layout, time.RFC3339, time.DateTime)
+	case ColumnTypeDuration:
+		v, err = time.ParseDuration(val)
+	case ColumnTypePercent:
+		v, err = parsePercent(val)
+	case ColumnTypeCurrency:
+		v, err = parseCurrency(val, cs.currencySymbol())
+	default:
+		return nil, fmt.Errorf("unknown column type [%s]", cs.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", cs.Type, err)
+	}
+	return v, nil
+}
+
+// Format returns the display string for a cell value, such as for HTML.
+func (cs *ColumnSchema) Format(val string) (string, error) {
+	v, err := cs.Parse(val)
+	if err != nil || v == nil {
+		return "", err
+	}
+	switch cs.Type {
+	case ColumnTypeDecimal:
+		return formatGrouped(v.(float64), cs.precision()), nil
+	case ColumnTypePercent:
+		return strconv.FormatFloat(v.(float64)*100, 'f', cs.precision(), 64) + "%", nil
+	case ColumnTypeCurrency:
+		f := v.(float64)
+		if f < 0 {
+			return "-" + cs.currencySymbol() + formatGrouped(-f, cs.precision()), nil
+		}
+		return cs.currencySymbol() + formatGrouped(f, cs.precision()), nil
+	case ColumnTypeDate:
+		return v.(time.Time).Format(time.DateOnly), nil
+	case ColumnTypeDatetime:
+		return v.(time.Time).Format(time.RFC3339), nil
+	case ColumnTypeFloat:
+		return strconv.FormatFloat(v.(float64), 'f', -1, 64), nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func parseTime(val, layout string, defaultLayouts ...string) (time.Time, error) {
+	if layout != "" {
+		return time.Parse(layout, val)
+	}
+	var err error
+	for _, l := range defaultLayouts {
+		var t time.Time
+		if t, err = time.Parse(l, val); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func parseBool(val string) (bool, error) {
+	switch strings.ToLower(val) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(val)
+}
+
+// parseDecimal parses a number which can include thousands separators and
+// use parentheses for negative values.
+func parseDecimal(val string) (float64, error) {
+	s, err := decimalText(val)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// decimalText returns a number which can include thousands separators and
+// use parentheses for negative values as plain number text, such as "-1234.5".
+func decimalText(val string) (string, error) {
+	neg := false
+	if strings.HasPrefix(val, "(") && strings.HasSuffix(val, ")") {
+		neg = true
+		val = val[1 : len(val)-1]
+	}
+	s := strings.ReplaceAll(val, ",", "")
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return "", fmt.Errorf("invalid number [%s]", val)
+	}
+	if neg {
+		return negateText(s), nil
+	}
+	return s, nil
+}
+
+func negateText(s string) string {
+	if t, ok := strings.CutPrefix(s, "-"); ok {
+		return t
+	}
+	return "-" + strings.TrimPrefix(s, "+")
+}
+
+func parsePercent(val string) (float64, error) {
+	if pct, ok := strings.CutSuffix(val, "%"); ok {
+		f, err := parseDecimal(strings.TrimSpace(pct))
+		return f / 100, err
+	}
+	return parseDecimal(val)
+}
+
+func parseCurrency(val, symbol string) (float64, error) {
+	s, err := currencyText(val, symbol)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// currencyText returns a currency amount as plain number text, like
+// `decimalText`.
+func currencyText(val, symbol string) (string, error) {
+	neg := false
+	if strings.HasPrefix(val, "(") && strings.HasSuffix(val, ")") {
+		neg = true
+		val = val[1 : len(val)-1]
+	}
+	if v, ok := strings.CutPrefix(val, "-"); ok {
+		neg = !neg
+		val = v
+	}
+	val = strings.TrimSpace(strings.TrimPrefix(val, symbol))
+	s, err := decimalText(val)
+	if err != nil || !neg {
+		return s, err
+	}
+	return negateText(s), nil
+}
+
+// unscaledDecimal parses a decimal or currency cell as an unscaled integer at
+// the column precision, rounding half away from zero, such as 123456 for
+// "1,234.56" at precision 2. The cell text is scaled exactly rather than via
+// the float64 from `Parse`, so values of up to 18 digits are not rounded.
+func (cs *ColumnSchema) unscaledDecimal(val string) (int64, error) {
+	val = strings.TrimSpace(val)
+	var s string
+	var err error
+	if cs.Type == ColumnTypeCurrency {
+		s, err = currencyText(val, cs.currencySymbol())
+	} else {
+		s, err = decimalText(val)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", cs.Type, err)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s: invalid number [%s]", cs.Type, val)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(cs.precision())), nil)
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	unscaled, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Lsh(rem.Abs(rem), 1).Cmp(r.Denom()) >= 0 {
+		unscaled.Add(unscaled, big.NewInt(int64(r.Sign())))
+	}
+	if unscaled.CmpAbs(maxUnscaledDecimal) >= 0 {
+		return 0, fmt.Errorf("decimal out of range [%s]", val)
+	}
+	return unscaled.Int64(), nil
+}
+
+// formatGrouped formats a number with comma thousands separators.
+func formatGrouped(f float64, precision int) string {
+	s := strconv.FormatFloat(f, 'f', precision, 64)
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	sign := ""
+	if strings.HasPrefix(intPart, "-") {
+		sign, intPart = "-", intPart[1:]
+	}
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		return sign + b.String() + "." + fracPart
+	}
+	return sign + b.String()
+}
+
+// currencySymbols are the currency symbols recognized by `InferSchema`.
+var currencySymbols = []string{"$", "€", "£", "¥"}
+
+func currencySymbolOf(val string) string {
+	for _, symbol := range currencySymbols {
+		if strings.Contains(val, symbol) {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// inferenceOrder lists the types tried by `InferSchema`, narrowest first.
+var inferenceOrder = []ColumnType{
+	ColumnTypeBool,
+	ColumnTypeInt,
+	ColumnTypeFloat,
+	ColumnTypeDecimal,
+	ColumnTypePercent,
+	ColumnTypeCurrency,
+	ColumnTypeDate,
+	ColumnTypeDatetime,
+	ColumnTypeDuration,
+}
+
+// inferMatch returns true if a non-empty value looks like `ct`. It is stricter
+// than `ColumnSchema.Parse` so that, for example, "1" is not inferred as a bool
+// and "0.5" is not inferred as a percent.
+func inferMatch(ct ColumnType, val string) bool {
+	switch ct {
+	case ColumnTypeBool:
+		switch strings.ToLower(val) {
+		case "true", "false", "yes", "no":
+			return true
+		}
+		return false
+	case ColumnTypePercent:
+		if !strings.HasSuffix(val, "%") {
+			return false
+		}
+	case ColumnTypeCurrency:
+		symbol := currencySymbolOf(val)
+		if symbol == "" {
+			return false
+		}
+		_, err := parseCurrency(val, symbol)
+		return err == nil
+	}
+	cs := ColumnSchema{Type: ct}
+	_, err := cs.Parse(val)
+	return err == nil
+}
+
+// InferSchema returns a schema with a column type inferred from the non-empty
+// values in the first `sampleSize` rows, or all rows if `sampleSize` is not
+// positive. Each column is assigned the first type in the order bool, int,
+// float, decimal, percent, currency, date, datetime and duration which matches
+// every sampled value, falling back to string.
+func (tbl *Table) InferSchema(sampleSize int) *Schema {
+	rows := tbl.Rows
+	if sampleSize > 0 && sampleSize < len(rows) {
+		rows = rows[:sampleSize]
+	}
+	colCount := len(tbl.Columns)
+	for _, row := range rows {
+		colCount = max(colCount, len(row))
+	}
+	sch := &Schema{}
+	for colIdx := range colCount {
+		cs := ColumnSchema{Type: ColumnTypeString}
+		if colIdx < len(tbl.Columns) {
+			cs.Name = tbl.Columns[colIdx]
+		}
+		candidates := slices.Clone(inferenceOrder)
+		seen := false
+		firstVal := ""
+		for _, row := range rows {
+			if colIdx >= len(row) {
+				continue
+			}
+			val := strings.TrimSpace(row[colIdx])
+			if val == "" {
+				continue
+			}
+			if !seen {
+				seen, firstVal = true, val
+			}
+			candidates = slices.DeleteFunc(candidates, func(ct ColumnType) bool {
+				return !inferMatch(ct, val)
+			})
+			if len(candidates) == 0 {
+				break
+			}
+		}
+		if seen && len(candidates) > 0 {
+			cs.Type = candidates[0]
+			if cs.Type == ColumnTypeCurrency {
+				cs.CurrencySymbol = currencySymbolOf(firstVal)
+			}
+		}
+		sch.Columns = append(sch.Columns, cs)
+	}
+	return sch
+}
+
+// CellError describes a cell which does not match its `Schema` column.
+// `Row` is the index in `Table.Rows` or -1 for errors about the column
+// itself, such as a schema column missing from the table.
+type CellError struct {
+	Row    int
+	Column string
+	Value  string
+	Reason string
+}
+
+func (e CellError) Error() string {
+	if e.Row < 0 {
+		return fmt.Sprintf("column [%s]: %s", e.Column, e.Reason)
+	}
+	return fmt.Sprintf("row [%d] column [%s] value [%s]: %s", e.Row, e.Column, e.Value, e.Reason)
+}
+
+// CellErrors is a list of `CellError` which implements `error`.
+type CellErrors []CellError
+
+func (errs CellErrors) Error() string {
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks every cell in the schema's columns and returns an error for
+// each value which cannot be parsed as the column type or is empty in a
+// required column. It returns `nil` if there are no errors.
+func (sch *Schema) Validate(tbl *Table) CellErrors {
+	if tbl == nil {
+		return CellErrors{{Row: -1, Reason: ErrTableCannotBeNil.Error()}}
+	}
+	var errs CellErrors
+	if len(tbl.Columns) > 0 {
+		for _, cs := range sch.Columns {
+			if tbl.Columns.Index(cs.Name) < 0 {
+				errs = append(errs, CellError{Row: -1, Column: cs.Name, Reason: "column not found"})
+			}
+		}
+	}
+	tableCols := sch.tableColumns(tbl.Columns)
+	for y, row := range tbl.Rows {
+		for x, cs := range tableCols {
+			if cs == nil {
+				continue
+			}
+			val := ""
+			if x < len(row) {
+				val = row[x]
+			}
+			if _, err := cs.Parse(val); err != nil {
+				errs = append(errs, CellError{Row: y, Column: cs.Name, Value: val, Reason: err.Error()})
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// formatterFunc wraps `fallback` so that columns in the schema are parsed to
+// typed values.
+func (sch *Schema) formatterFunc(cols Columns, html bool, fallback func(val string, colIdx uint32) (any, error)) func(val string, colIdx uint32) (any, error) {
+	tableCols := sch.tableColumns(cols)
+	return func(val string, colIdx uint32) (any, error) {
+		if int(colIdx) >= len(tableCols) || tableCols[colIdx] == nil {
+			return fallback(val, colIdx)
+		}
+		if html {
+			if s, err := tableCols[colIdx].Format(val); err != nil {
+				return val, err
+			} else {
+				return s, nil
+			}
+		}
+		if v, err := tableCols[colIdx].Parse(val); err != nil {
+			return val, err
+		} else {
+			return v, nil
+		}
+	}
+}
+
+// excelNumFmt returns the Excel number format for the column type, if any.
+func (cs *ColumnSchema) excelNumFmt() (string, bool) {
+	decimals := ""
+	if p := cs.precision(); p > 0 {
+		decimals = "." + strings.Repeat("0", p)
+	}
+	switch cs.Type {
+	case ColumnTypeInt:
+		return "0", true
+	case ColumnTypeDecimal:
+		return "#,##0" + decimals, true
+	case ColumnTypePercent:
+		return "0" + decimals + "%", true
+	case ColumnTypeCurrency:
+		return `"` + cs.currencySymbol() + `"#,##0` + decimals, true
+	case ColumnTypeDate:
+		return "yyyy-mm-dd", true
+	case ColumnTypeDatetime:
+		return "yyyy-mm-dd hh:mm:ss", true
+	case ColumnTypeDuration:
+		return "[h]:mm:ss", true
+	default:
+		return "", false
+	}
+}
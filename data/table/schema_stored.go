@@ -0,0 +1,86 @@
+package table
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metadataKeySchema is the file metadata key used by typed formats, such as
+// Parquet and Arrow, to store the table `Schema` as JSON, so that column types
+// without a native type, such as percent and currency, survive a round trip.
+const metadataKeySchema = "gocharts.schema"
+
+// typedSchema returns a schema with a column for each table column, using the
+// table `Schema` where set and string columns otherwise.
+func (tbl *Table) typedSchema() *Schema {
+	sch := &Schema{}
+	var tblCols []*ColumnSchema
+	if tbl.Schema != nil {
+		tblCols = tbl.Schema.tableColumns(tbl.Columns)
+	}
+	for i, colName := range tbl.Columns {
+		cs := ColumnSchema{Name: colName, Type: ColumnTypeString}
+		if i < len(tblCols) && tblCols[i] != nil {
+			cs = *tblCols[i]
+			cs.Name = colName
+		}
+		sch.Columns = append(sch.Columns, cs)
+	}
+	return sch
+}
+
+// parseStoredSchema parses a schema stored under `metadataKeySchema`,
+// returning `nil` if it is invalid.
+func parseStoredSchema(v string) *Schema {
+	sch := &Schema{}
+	if err := json.Unmarshal([]byte(v), sch); err != nil {
+		return nil
+	}
+	return sch
+}
+
+// storedColumnSchema applies a column schema stored in the file metadata when
+// it is compatible with the column schema read from the file type. It returns
+// a function to reformat the cell values read for the file type, or `nil` if
+// they are unchanged.
+func storedColumnSchema(stored, cs ColumnSchema) (ColumnSchema, func(string) string) {
+	stored.Name = cs.Name
+	switch {
+	case stored.Type == cs.Type && (cs.Type == ColumnTypeDate || cs.Type == ColumnTypeDatetime) && stored.Layout != "":
+		return stored, func(s string) string {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t.Format(stored.Layout)
+			} else if t, err := time.Parse(time.DateOnly, s); err == nil {
+				return t.Format(stored.Layout)
+			}
+			return s
+		}
+	case stored.Type == ColumnTypeCurrency && cs.Type == ColumnTypeDecimal,
+		stored.Type == ColumnTypeDuration && cs.Type == ColumnTypeInt:
+		return stored, nil
+	case stored.Type == ColumnTypePercent && cs.Type == ColumnTypeFloat:
+		return stored, func(s string) string {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return s
+			}
+			return strconv.FormatFloat(f*100, 'f', -1, 64) + "%"
+		}
+	case stored.Type == cs.Type:
+		return stored, nil
+	}
+	return cs, nil
+}
+
+// tableFileName returns a table name with characters which are invalid in
+// file names replaced by underscores.
+func tableFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,127 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInferSchema(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"name", "count", "ratio", "amount", "share", "price", "active", "day", "at", "wait", "empty"}
+	tbl.Rows = [][]string{
+		{"foo", "1", "0.5", "1,000.25", "10%", "$1,200.00", "yes", "2024-01-31", "2024-01-31T10:00:00Z", "1h30m", ""},
+		{"bar", "-2", "3", "12", "12.5%", "($3.50)", "No", "2024-02-01", "2024-02-01 08:30:00", "45s", ""},
+	}
+	want := []ColumnType{ColumnTypeString, ColumnTypeInt, ColumnTypeFloat, ColumnTypeDecimal, ColumnTypePercent,
+		ColumnTypeCurrency, ColumnTypeBool, ColumnTypeDate, ColumnTypeDatetime, ColumnTypeDuration, ColumnTypeString}
+	sch := tbl.InferSchema(0)
+	for i, cs := range sch.Columns {
+		if cs.Name != tbl.Columns[i] || cs.Type != want[i] {
+			t.Errorf("Table.InferSchema() mismatch for column [%s]: want [%s] got [%s]", tbl.Columns[i], want[i], cs.Type)
+		}
+	}
+	if errs := sch.Validate(&tbl); errs != nil {
+		t.Errorf("Schema.Validate() unexpected errors: [%v]", errs)
+	}
+
+	tbl.Schema = sch
+	fmtFunc := tbl.FormatterFunc()
+	if v, err := fmtFunc("12.5%", 4); err != nil || v != 0.125 {
+		t.Errorf("Table.FormatterFunc() percent mismatch: want [0.125] got [%v] err [%v]", v, err)
+	}
+	if v, err := fmtFunc("2024-01-31", 7); err != nil || !v.(time.Time).Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Table.FormatterFunc() date mismatch: got [%v] err [%v]", v, err)
+	}
+	if h := tbl.ToHTML(true); !strings.Contains(h, `<td style="text-align:right">-$3.50</td>`) {
+		t.Errorf("Table.ToHTML() missing formatted currency: [%s]", h)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"id", "day"}
+	tbl.Rows = [][]string{{"1", "2024-01-31"}, {"x", ""}, {"3", "31/01/2024"}}
+	sch := &Schema{Columns: []ColumnSchema{
+		{Name: "id", Type: ColumnTypeInt},
+		{Name: "day", Type: ColumnTypeDate, Required: true},
+		{Name: "missing", Type: ColumnTypeString},
+	}}
+	errs := sch.Validate(&tbl)
+	want := []CellError{
+		{Row: -1, Column: "missing"},
+		{Row: 1, Column: "id", Value: "x"},
+		{Row: 1, Column: "day", Value: ""},
+		{Row: 2, Column: "day", Value: "31/01/2024"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Schema.Validate() error count mismatch: want [%d] got [%d]: [%v]", len(want), len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.Row != want[i].Row || e.Column != want[i].Column || e.Value != want[i].Value || e.Reason == "" {
+			t.Errorf("Schema.Validate() error [%d] mismatch: want [%v] got [%v]", i, want[i], e)
+		}
+	}
+}
+
+var unscaledDecimalTests = []struct {
+	typ       ColumnType
+	precision int
+	val       string
+	want      int64
+	wantErr   bool
+}{
+	{ColumnTypeDecimal, 2, "1,234.56", 123456, false},
+	{ColumnTypeDecimal, 2, "0.005", 1, false},
+	{ColumnTypeDecimal, 2, "(0.005)", -1, false},
+	{ColumnTypeDecimal, 0, "1e3", 1000, false},
+	{ColumnTypeDecimal, 2, "9999999999999999.99", 999999999999999999, false},
+	{ColumnTypeDecimal, 2, "10000000000000000", 0, true},
+	{ColumnTypeDecimal, 2, "Inf", 0, true},
+	{ColumnTypeDecimal, 2, "abc", 0, true},
+	{ColumnTypeCurrency, 2, "-$1,234,567,890,123.45", -123456789012345, false},
+	{ColumnTypeCurrency, 3, "($0.0125)", -13, false},
+}
+
+func TestUnscaledDecimal(t *testing.T) {
+	for _, tt := range unscaledDecimalTests {
+		cs := ColumnSchema{Type: tt.typ, Precision: &tt.precision}
+		got, err := cs.unscaledDecimal(tt.val)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ColumnSchema.unscaledDecimal(%s) expected error", tt.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ColumnSchema.unscaledDecimal(%s) error: [%v]", tt.val, err)
+		} else if got != tt.want {
+			t.Errorf("ColumnSchema.unscaledDecimal(%s) mismatch: want [%d] got [%d]", tt.val, tt.want, got)
+		}
+	}
+}
+
+func TestStoredColumnSchema(t *testing.T) {
+	tests := []struct {
+		stored ColumnSchema
+		read   ColumnType
+		val    string
+		want   ColumnType
+		wantV  string
+	}{
+		{ColumnSchema{Type: ColumnTypeDate, Layout: "01/02/2006"}, ColumnTypeDate, "2024-01-31", ColumnTypeDate, "01/31/2024"},
+		{ColumnSchema{Type: ColumnTypePercent}, ColumnTypeFloat, "0.125", ColumnTypePercent, "12.5%"},
+		{ColumnSchema{Type: ColumnTypeCurrency}, ColumnTypeDecimal, "3.50", ColumnTypeCurrency, "3.50"},
+		{ColumnSchema{Type: ColumnTypePercent}, ColumnTypeString, "abc", ColumnTypeString, "abc"},
+	}
+	for _, tt := range tests {
+		cs, format := storedColumnSchema(tt.stored, ColumnSchema{Name: "col", Type: tt.read})
+		got := tt.val
+		if format != nil {
+			got = format(tt.val)
+		}
+		if cs.Name != "col" || cs.Type != tt.want || got != tt.wantV {
+			t.Errorf("storedColumnSchema(%s, %s) mismatch: want [%s %s] got [%s %s %s]", tt.stored.Type, tt.read, tt.want, tt.wantV, cs.Name, cs.Type, got)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package sheet
+
+import (
+	"errors"
+	"strings"
+)
+
+// MergeRange is a rectangle of merged cells using zero-based, inclusive
+// column and row indexes.
+type MergeRange struct {
+	StartCol uint32
+	StartRow uint32
+	EndCol   uint32
+	EndRow   uint32
+}
+
+// Contains returns whether the cell at `colIdx` and `rowIdx` is in the range.
+func (mr MergeRange) Contains(colIdx, rowIdx uint32) bool {
+	return colIdx >= mr.StartCol && colIdx <= mr.EndCol &&
+		rowIdx >= mr.StartRow && rowIdx <= mr.EndRow
+}
+
+// Grid is the cell values of a worksheet, used by spreadsheet readers to
+// provide the same table data as `excelizeutil.File.TableData()`.
+type Grid struct {
+	Name   string
+	Rows   [][]string
+	Merges []MergeRange
+}
+
+// Compact removes trailing empty cells from each row and trailing empty rows,
+// matching `excelize.File.GetRows()`.
+func (g *Grid) Compact() {
+	for i, row := range g.Rows {
+		l := len(row)
+		for l > 0 && row[l-1] == "" {
+			l--
+		}
+		g.Rows[i] = row[:l]
+	}
+	l := len(g.Rows)
+	for l > 0 && len(g.Rows[l-1]) == 0 {
+		l--
+	}
+	g.Rows = g.Rows[:l]
+}
+
+// CellValue returns the value of a cell. Cells in a merged range return the
+// value of the top left cell of the range.
+func (g *Grid) CellValue(colIdx, rowIdx uint32) string {
+	for _, mr := range g.Merges {
+		if mr.Contains(colIdx, rowIdx) {
+			colIdx, rowIdx = mr.StartCol, mr.StartRow
+			break
+		}
+	}
+	if int(rowIdx) >= len(g.Rows) || int(colIdx) >= len(g.Rows[rowIdx]) {
+		return ""
+	}
+	return g.Rows[rowIdx][colIdx]
+}
+
+// Columns returns the first cell of each column, like `excelizeutil.ColumnsTop()`.
+func (g *Grid) Columns(trimSpace bool) []string {
+	width := 0
+	for _, row := range g.Rows {
+		width = max(width, len(row))
+	}
+	cols := make([]string, width)
+	if len(g.Rows) > 0 {
+		copy(cols, g.Rows[0])
+	}
+	if trimSpace {
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+	}
+	return cols
+}
+
+// TableData returns columns and rows with the same semantics as
+// `excelizeutil.File.TableData()`. Columns are the first row and rows exclude
+// the first `headerRowCount` rows. If `unmerge` is set, empty rows are
+// skipped and short rows are filled using merged cell values.
+func (g *Grid) TableData(headerRowCount uint32, trimSpace, unmerge bool) ([]string, [][]string, error) {
+	cols := g.Columns(trimSpace)
+	var rows [][]string
+	for _, row := range g.Rows {
+		rows = append(rows, append([]string{}, row...))
+	}
+	if headerRowCount > 0 && int(headerRowCount) <= len(rows) {
+		rows = rows[headerRowCount:]
+	}
+	if trimSpace {
+		for _, row := range rows {
+			for j := range row {
+				row[j] = strings.TrimSpace(row[j])
+			}
+		}
+	}
+	if !unmerge || len(rows) == 0 || rowsHaveLength(rows, len(cols)) {
+		return cols, rows, nil
+	}
+
+	var newRows [][]string
+	for i, row := range rows {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		if len(row) == len(cols) {
+			newRows = append(newRows, row)
+		} else if len(row) > len(cols) {
+			return cols, newRows, errors.New("row longer than cols")
+		} else {
+			newRow := make([]string, len(cols))
+			for colIdx := range newRow {
+				cell := g.CellValue(uint32(colIdx), uint32(i)+headerRowCount)
+				if trimSpace {
+					cell = strings.TrimSpace(cell)
+				}
+				newRow[colIdx] = cell
+			}
+			newRows = append(newRows, newRow)
+		}
+	}
+	if len(newRows) > 0 && !rowsHaveLength(newRows, len(newRows[0])) {
+		return cols, newRows, errors.New("row mismatch after unmerging")
+	}
+	return cols, newRows, nil
+}
+
+func rowsHaveLength(rows [][]string, l int) bool {
+	for _, row := range rows {
+		if len(row) != l {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,329 @@
+package table
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortType determines how values are compared by `Table.SortBy`.
+type SortType string
+
+const (
+	SortAuto    SortType = ""        // schema column type if set, otherwise string
+	SortString  SortType = "string"  // lexical comparison
+	SortNumeric SortType = "numeric" // numbers, using the schema column type to parse values if set
+	SortDate    SortType = "date"    // `time.DateOnly`, `time.RFC3339` or `time.DateTime`, or the schema layout
+	SortNatural SortType = "natural" // digit runs compared as numbers, such as "v1.9" < "v1.10"
+)
+
+// EmptyPlacement determines where empty values are sorted.
+type EmptyPlacement string
+
+const (
+	EmptyLast  EmptyPlacement = "" // empty values last, for both directions
+	EmptyFirst EmptyPlacement = "first"
+)
+
+// SortKey is a column to sort by.
+type SortKey struct {
+	Column     string
+	Descending bool
+	Type       SortType
+	IgnoreCase bool // for string and natural comparisons
+	Empty      EmptyPlacement
+}
+
+type sortValue struct {
+	empty   bool
+	numeric bool
+	num     float64
+	str     string
+}
+
+// SortBy sorts the rows in place by one or more keys. The sort is stable, so
+// rows with equal keys keep their order. An error is returned if a key column
+// is not found or a value cannot be parsed for a numeric or date key, in which
+// case the rows are not modified.
+func (tbl *Table) SortBy(keys ...SortKey) error {
+	if len(keys) == 0 {
+		return errors.New("no sort keys provided")
+	}
+	vals, err := tbl.sortValues(keys)
+	if err != nil {
+		return err
+	}
+	idxs := make([]int, len(tbl.Rows))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	slices.SortStableFunc(idxs, func(a, b int) int {
+		return compareSortValues(vals[a], vals[b], keys)
+	})
+	rows := make([][]string, len(idxs))
+	for i, idx := range idxs {
+		rows[i] = tbl.Rows[idx]
+	}
+	tbl.Rows = rows
+	return nil
+}
+
+// sortType returns the effective sort type for a key.
+func (tbl *Table) sortType(key SortKey) (SortType, *ColumnSchema) {
+	var cs *ColumnSchema
+	if tbl.Schema != nil {
+		cs = tbl.Schema.Column(key.Column)
+	}
+	if key.Type != SortAuto {
+		return key.Type, cs
+	}
+	if cs != nil {
+		switch {
+		case cs.Type.IsNumeric() || cs.Type == ColumnTypeDuration:
+			return SortNumeric, cs
+		case cs.Type == ColumnTypeDate || cs.Type == ColumnTypeDatetime:
+			return SortDate, cs
+		}
+	}
+	return SortString, cs
+}
+
+// sortValues parses the key values of each row.
+func (tbl *Table) sortValues(keys []SortKey) ([][]sortValue, error) {
+	colIdxs := make([]int, len(keys))
+	parsers := make([]func(string) (sortValue, error), len(keys))
+	for i, key := range keys {
+		if colIdxs[i] = tbl.Columns.Index(key.Column); colIdxs[i] < 0 {
+			return nil, fmt.Errorf("sort column not found [%s]", key.Column)
+		}
+		sortType, cs := tbl.sortType(key)
+		switch sortType {
+		case SortString, SortNatural:
+			ignoreCase := key.IgnoreCase
+			parsers[i] = func(s string) (sortValue, error) {
+				if ignoreCase {
+					s = strings.ToLower(s)
+				}
+				return sortValue{str: s}, nil
+			}
+		case SortNumeric:
+			parse := tbl.float64Parser(key.Column)
+			if cs != nil && cs.Type == ColumnTypeDuration {
+				parse = func(s string) (float64, error) {
+					d, err := time.ParseDuration(s)
+					return float64(d), err
+				}
+			}
+			parsers[i] = func(s string) (sortValue, error) {
+				f, err := parse(s)
+				return sortValue{numeric: true, num: f}, err
+			}
+		case SortDate:
+			layout := ""
+			if cs != nil {
+				layout = cs.Layout
+			}
+			parsers[i] = func(s string) (sortValue, error) {
+				t, err := parseTime(s, layout, time.DateOnly, time.RFC3339, time.DateTime)
+				return sortValue{numeric: true, num: float64(t.UnixNano())}, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown sort type [%s]", sortType)
+		}
+	}
+	vals := make([][]sortValue, len(tbl.Rows))
+	for y, row := range tbl.Rows {
+		vals[y] = make([]sortValue, len(keys))
+		for i, colIdx := range colIdxs {
+			s := ""
+			if colIdx < len(row) {
+				s = strings.TrimSpace(row[colIdx])
+			}
+			if s == "" {
+				vals[y][i] = sortValue{empty: true}
+				continue
+			}
+			v, err := parsers[i](s)
+			if err != nil {
+				return nil, fmt.Errorf("row [%d] column [%s] value [%s]: %w", y, keys[i].Column, s, err)
+			}
+			vals[y][i] = v
+		}
+	}
+	return vals, nil
+}
+
+func compareSortValues(a, b []sortValue, keys []SortKey) int {
+	for i, key := range keys {
+		va, vb := a[i], b[i]
+		if va.empty || vb.empty {
+			if va.empty == vb.empty {
+				continue
+			}
+			c := 1
+			if va.empty == (key.Empty == EmptyFirst) {
+				c = -1
+			}
+			return c
+		}
+		var c int
+		switch {
+		case va.numeric:
+			c = cmp.Compare(va.num, vb.num)
+		case key.Type == SortNatural:
+			c = CompareNatural(va.str, vb.str)
+		default:
+			c = strings.Compare(va.str, vb.str)
+		}
+		if key.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// CompareNatural compares strings treating runs of digits as numbers, so that
+// "file2" sorts before "file10" and "1.9.2" before "1.10.0".
+func CompareNatural(a, b string) int {
+	for a != "" && b != "" {
+		ca, restA := naturalChunk(a)
+		cb, restB := naturalChunk(b)
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if c := cmp.Compare(len(na), len(nb)); c != 0 {
+				return c
+			} else if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(ca, cb); c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// naturalChunk splits off the leading run of digits or non-digits.
+func naturalChunk(s string) (string, string) {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// RankFunc is a window function used by `Table.AddRankColumn`.
+type RankFunc string
+
+const (
+	RankRowNumber RankFunc = "row_number" // 1, 2, 3, 4 with ties in table order
+	RankStandard  RankFunc = "rank"       // 1, 2, 2, 4
+	RankDense     RankFunc = "dense_rank" // 1, 2, 2, 3
+)
+
+// RankOptions configures `Table.AddRankColumn`.
+type RankOptions struct {
+	Name        string    // output column name, default the function name
+	Func        RankFunc  // default `RankRowNumber`
+	PartitionBy []string  // columns whose values restart the ranking
+	OrderBy     []SortKey // ranking order, ties compare equal on all keys
+}
+
+// AddRankColumn appends a column with the rank of each row within its
+// partition, similar to SQL window functions. The row order is not changed.
+func (tbl *Table) AddRankColumn(opts RankOptions) error {
+	if len(opts.OrderBy) == 0 {
+		return errors.New("rank order by keys not provided")
+	}
+	if opts.Func == "" {
+		opts.Func = RankRowNumber
+	}
+	switch opts.Func {
+	case RankRowNumber, RankStandard, RankDense:
+	default:
+		return fmt.Errorf("unknown rank function [%s]", opts.Func)
+	}
+	name := opts.Name
+	if name == "" {
+		name = string(opts.Func)
+	}
+	if tbl.Columns.Index(name) >= 0 {
+		return fmt.Errorf("column name already exists [%s]", name)
+	}
+	partIdxs, err := columnIndexes(tbl.Columns, opts.PartitionBy)
+	if err != nil {
+		return err
+	}
+	vals, err := tbl.sortValues(opts.OrderBy)
+	if err != nil {
+		return err
+	}
+
+	var partitions [][]int
+	partitionIndex := map[string]int{}
+	for y, row := range tbl.Rows {
+		key := make([]string, len(partIdxs))
+		for i, idx := range partIdxs {
+			if idx < len(row) {
+				key[i] = row[idx]
+			}
+		}
+		keyStr := strings.Join(key, "\x1f")
+		pi, ok := partitionIndex[keyStr]
+		if !ok {
+			pi = len(partitions)
+			partitionIndex[keyStr] = pi
+			partitions = append(partitions, nil)
+		}
+		partitions[pi] = append(partitions[pi], y)
+	}
+
+	ranks := make([]int, len(tbl.Rows))
+	for _, idxs := range partitions {
+		slices.SortStableFunc(idxs, func(a, b int) int {
+			return compareSortValues(vals[a], vals[b], opts.OrderBy)
+		})
+		rank, dense := 0, 0
+		for i, idx := range idxs {
+			tie := i > 0 && compareSortValues(vals[idxs[i-1]], vals[idx], opts.OrderBy) == 0
+			if !tie {
+				rank = i + 1
+				dense++
+			}
+			switch opts.Func {
+			case RankRowNumber:
+				ranks[idx] = i + 1
+			case RankStandard:
+				ranks[idx] = rank
+			case RankDense:
+				ranks[idx] = dense
+			}
+		}
+	}
+
+	colIdx := len(tbl.Columns)
+	tbl.Columns = append(tbl.Columns, name)
+	if tbl.FormatMap == nil {
+		tbl.FormatMap = map[int]string{}
+	}
+	tbl.FormatMap[colIdx] = FormatInt
+	for y, row := range tbl.Rows {
+		for len(row) < colIdx {
+			row = append(row, "")
+		}
+		tbl.Rows[y] = append(row, strconv.Itoa(ranks[y]))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"team", "version", "score", "day"}
+	tbl.Rows = [][]string{
+		{"b", "v1.10", "9", "2024-03-01"},
+		{"a", "v1.9", "", "2024-01-15"},
+		{"b", "v1.2", "10", ""},
+		{"a", "v1.10", "7", "2024-02-01"},
+	}
+	tests := []struct {
+		keys []SortKey
+		want string
+	}{
+		{[]SortKey{{Column: "team"}, {Column: "version", Type: SortNatural, Descending: true}}, "a,v1.10|a,v1.9|b,v1.10|b,v1.2"},
+		{[]SortKey{{Column: "score", Type: SortNumeric, Descending: true}}, "b,v1.2|b,v1.10|a,v1.10|a,v1.9"},
+		{[]SortKey{{Column: "score", Type: SortNumeric, Empty: EmptyFirst}}, "a,v1.9|a,v1.10|b,v1.10|b,v1.2"},
+		{[]SortKey{{Column: "day", Type: SortDate}}, "a,v1.9|a,v1.10|b,v1.10|b,v1.2"},
+	}
+	for _, tt := range tests {
+		if err := tbl.SortBy(tt.keys...); err != nil {
+			t.Fatalf("Table.SortBy() error: [%v]", err)
+		}
+		var got []string
+		for _, row := range tbl.Rows {
+			got = append(got, row[0]+","+row[1])
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("Table.SortBy(%v) mismatch: want [%s] got [%s]", tt.keys, tt.want, strings.Join(got, "|"))
+		}
+	}
+	if err := tbl.SortBy(SortKey{Column: "team", Type: SortNumeric}); err == nil {
+		t.Error("Table.SortBy() expected error for non-numeric values")
+	}
+}
+
+func TestAddRankColumn(t *testing.T) {
+	tbl := NewTable("")
+	tbl.Columns = []string{"team", "score"}
+	tbl.Rows = [][]string{{"a", "5"}, {"a", "9"}, {"b", "3"}, {"a", "9"}, {"a", "1"}}
+	for _, fn := range []RankFunc{RankRowNumber, RankStandard, RankDense} {
+		if err := tbl.AddRankColumn(RankOptions{
+			Func:        fn,
+			PartitionBy: []string{"team"},
+			OrderBy:     []SortKey{{Column: "score", Type: SortNumeric, Descending: true}},
+		}); err != nil {
+			t.Fatalf("Table.AddRankColumn(%s) error: [%v]", fn, err)
+		}
+	}
+	want := []string{"a,5,3,3,2", "a,9,1,1,1", "b,3,1,1,1", "a,9,2,1,1", "a,1,4,4,3"}
+	for i, row := range tbl.Rows {
+		if got := strings.Join(row, ","); got != want[i] {
+			t.Errorf("Table.AddRankColumn() row [%d] mismatch: want [%s] got [%s]", i, want[i], got)
+		}
+	}
+}
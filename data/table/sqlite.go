@@ -0,0 +1,476 @@
+package table
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// SQLiteDriverName is the `database/sql` driver name used to open SQLite files.
+const SQLiteDriverName = "sqlite"
+
+// SQLIfExists sets the behavior when writing a table that already exists.
+type SQLIfExists string
+
+const (
+	SQLIfExistsFail    SQLIfExists = "fail"    // return an error, the default
+	SQLIfExistsReplace SQLIfExists = "replace" // drop and recreate the table
+	SQLIfExistsAppend  SQLIfExists = "append"  // insert rows into the existing table
+)
+
+// SQLOptions configures writing tables to a SQL database.
+type SQLOptions struct {
+	IfExists SQLIfExists
+}
+
+// SQL column types. Types without a native SQLite equivalent, such as
+// PERCENT and CURRENCY, have NUMERIC affinity and are mapped back to the
+// matching format when read.
+const (
+	sqlTypeInteger  = "INTEGER"
+	sqlTypeReal     = "REAL"
+	sqlTypeText     = "TEXT"
+	sqlTypeDecimal  = "DECIMAL"
+	sqlTypeBoolean  = "BOOLEAN"
+	sqlTypeDate     = "DATE"
+	sqlTypeDatetime = "DATETIME"
+	sqlTypePercent  = "PERCENT"
+	sqlTypeCurrency = "CURRENCY"
+)
+
+// sqlColumn is the SQL type of a table column with a function converting
+// cells to SQL values.
+type sqlColumn struct {
+	name    string
+	sqlType string
+	value   func(val string) (any, error)
+}
+
+// sqlColumns returns the SQL columns of the table, typed by `Schema` if set,
+// or by `FormatMap` otherwise.
+func (tbl *Table) sqlColumns() []sqlColumn {
+	var schemaCols []*ColumnSchema
+	if tbl.Schema != nil {
+		schemaCols = tbl.Schema.tableColumns(tbl.Columns)
+	}
+	cols := make([]sqlColumn, len(tbl.Columns))
+	for x, colName := range tbl.Columns {
+		if x < len(schemaCols) && schemaCols[x] != nil {
+			cols[x] = schemaSQLColumn(colName, schemaCols[x])
+			continue
+		}
+		fmtType, ok := tbl.FormatMap[x]
+		if !ok {
+			fmtType = tbl.FormatMap[-1]
+		}
+		cols[x] = formatSQLColumn(colName, strings.ToLower(strings.TrimSpace(fmtType)))
+	}
+	return cols
+}
+
+// formatSQLColumn returns a SQL column for a `FormatMap` format. Values which
+// do not parse as numbers are stored as text.
+func formatSQLColumn(colName, fmtType string) sqlColumn {
+	col := sqlColumn{name: colName, sqlType: sqlTypeText, value: sqlText}
+	switch fmtType {
+	case FormatInt:
+		col.sqlType = sqlTypeInteger
+		col.value = func(val string) (any, error) {
+			if strings.TrimSpace(val) == "" {
+				return nil, nil
+			} else if i, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64); err == nil {
+				return i, nil
+			}
+			return sqlNumber(val)
+		}
+	case FormatFloat, FormatPercent, FormatCurrency:
+		col.sqlType = map[string]string{
+			FormatFloat:    sqlTypeReal,
+			FormatPercent:  sqlTypePercent,
+			FormatCurrency: sqlTypeCurrency,
+		}[fmtType]
+		col.value = sqlNumber
+	case FormatDate:
+		col.sqlType = sqlTypeDate
+	case FormatTime:
+		col.sqlType = sqlTypeDatetime
+	}
+	return col
+}
+
+func sqlText(val string) (any, error) {
+	if val == "" {
+		return nil, nil
+	}
+	return val, nil
+}
+
+func sqlNumber(val string) (any, error) {
+	if strings.TrimSpace(val) == "" {
+		return nil, nil
+	} else if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+		return f, nil
+	}
+	return val, nil
+}
+
+// schemaSQLColumn returns a SQL column for a schema column. Values are parsed
+// with `ColumnSchema.Parse`, with dates and datetimes stored as ISO 8601 text.
+func schemaSQLColumn(colName string, cs *ColumnSchema) sqlColumn {
+	col := sqlColumn{name: colName}
+	switch cs.Type {
+	case ColumnTypeInt:
+		col.sqlType = sqlTypeInteger
+	case ColumnTypeFloat:
+		col.sqlType = sqlTypeReal
+	case ColumnTypeDecimal:
+		col.sqlType = sqlTypeDecimal
+	case ColumnTypePercent:
+		col.sqlType = sqlTypePercent
+	case ColumnTypeCurrency:
+		col.sqlType = sqlTypeCurrency
+	case ColumnTypeBool:
+		col.sqlType = sqlTypeBoolean
+	case ColumnTypeDate:
+		col.sqlType = sqlTypeDate
+	case ColumnTypeDatetime:
+		col.sqlType = sqlTypeDatetime
+	default:
+		col.sqlType = sqlTypeText
+		col.value = sqlText
+		return col
+	}
+	col.value = func(val string) (any, error) {
+		v, err := cs.Parse(val)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		switch t := v.(type) {
+		case time.Time:
+			if cs.Type == ColumnTypeDate {
+				return t.Format(time.DateOnly), nil
+			}
+			return t.Format(time.RFC3339), nil
+		case bool:
+			if t {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case int:
+			return int64(t), nil
+		}
+		return v, nil
+	}
+	return col
+}
+
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// WriteSQL writes the table to a SQL table named `tableName`, or the table
+// name if empty. Column types are inferred from `Schema` if set, or from
+// `FormatMap`, with ints as INTEGER, floats as REAL, dates as DATE and
+// times as DATETIME. Empty cells are written as NULL. Rows are inserted in
+// a single transaction.
+func (tbl *Table) WriteSQL(db *sql.DB, tableName string, opts *SQLOptions) error {
+	if tbl.IsFloat64 {
+		return errors.New("cannot write float table as sql")
+	}
+	if tableName = strings.TrimSpace(tableName); tableName == "" {
+		tableName = strings.TrimSpace(tbl.Name)
+	}
+	if tableName == "" {
+		return errors.New("sql table name cannot be empty")
+	}
+	ifExists := SQLIfExistsFail
+	if opts != nil && opts.IfExists != "" {
+		ifExists = opts.IfExists
+	}
+	cols := tbl.sqlColumns()
+	if len(cols) == 0 {
+		return errors.New("table must have columns")
+	} else if dupe := duplicateColumn(tbl.Columns); dupe != "" {
+		return fmt.Errorf("duplicate column name [%s]", dupe)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	quotedName := quoteSQLIdentifier(tableName)
+	createStmt := "CREATE TABLE "
+	switch ifExists {
+	case SQLIfExistsFail:
+	case SQLIfExistsReplace:
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + quotedName); err != nil {
+			return err
+		}
+	case SQLIfExistsAppend:
+		createStmt = "CREATE TABLE IF NOT EXISTS "
+	default:
+		return fmt.Errorf("unknown sql if exists option [%s]", ifExists)
+	}
+	colDefs := make([]string, len(cols))
+	colNames := make([]string, len(cols))
+	for i, col := range cols {
+		colNames[i] = quoteSQLIdentifier(col.name)
+		colDefs[i] = colNames[i] + " " + col.sqlType
+	}
+	if _, err := tx.Exec(createStmt + quotedName + " (" + strings.Join(colDefs, ", ") + ")"); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO " + quotedName + " (" + strings.Join(colNames, ", ") +
+		") VALUES (" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	args := make([]any, len(cols))
+	for y, row := range tbl.Rows {
+		for x, col := range cols {
+			val := ""
+			if x < len(row) {
+				val = row[x]
+			}
+			if args[x], err = col.value(val); err != nil {
+				return fmt.Errorf("row [%d] column [%s]: %w", y, col.name, err)
+			}
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return fmt.Errorf("row [%d]: %w", y, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// WriteSQL writes each table in the `TableSet` to a SQL table. See `Table.WriteSQL`.
+func (ts *TableSet) WriteSQL(db *sql.DB, opts *SQLOptions) error {
+	names := ts.Order
+	if len(names) == 0 {
+		names = ts.TableNames()
+	}
+	for _, name := range names {
+		tbl, ok := ts.TableMap[name]
+		if !ok || tbl == nil {
+			continue
+		}
+		if err := tbl.WriteSQL(db, name, opts); err != nil {
+			return fmt.Errorf("table [%s]: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// WriteFileSQLite writes the `TableSet` to a SQLite file, creating it if needed.
+func (ts *TableSet) WriteFileSQLite(filename string, opts *SQLOptions) error {
+	db, err := sql.Open(SQLiteDriverName, filename)
+	if err != nil {
+		return err
+	}
+	if err := ts.WriteSQL(db, opts); err != nil {
+		db.Close()
+		return err
+	}
+	return db.Close()
+}
+
+// ReadSQL reads the results of a SQL query into a table. The `FormatMap` is
+// set from the declared column types, such as INTEGER to `FormatInt`, REAL to
+// `FormatFloat` and DATE to `FormatDate`, or from the values of computed
+// columns. NULL values are read as empty strings.
+func ReadSQL(db *sql.DB, name, query string, args ...any) (*Table, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	tbl := NewTable(name)
+	formats := make([]string, len(colTypes))
+	for i, ct := range colTypes {
+		tbl.Columns = append(tbl.Columns, ct.Name())
+		formats[i] = sqlTypeFormat(ct.DatabaseTypeName())
+	}
+	// inferred tracks computed columns, without a declared type, whose values are all ints or numbers.
+	inferred := make([]string, len(colTypes))
+	vals := make([]any, len(colTypes))
+	ptrs := make([]any, len(colTypes))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(vals))
+		for i, v := range vals {
+			row[i] = sqlValueString(v, formats[i])
+			if colTypes[i].DatabaseTypeName() == "" {
+				inferred[i] = inferSQLFormat(inferred[i], v)
+			}
+		}
+		tbl.Rows = append(tbl.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, f := range formats {
+		if f == "" && inferred[i] != FormatString {
+			f = inferred[i]
+		}
+		if f != "" {
+			tbl.FormatMap[i] = f
+		}
+	}
+	return &tbl, nil
+}
+
+// sqlTypeFormat returns the `FormatMap` format for a declared SQL type, using
+// SQLite type affinity rules for types not written by `Table.WriteSQL`.
+func sqlTypeFormat(sqlType string) string {
+	sqlType = strings.ToUpper(strings.TrimSpace(sqlType))
+	switch sqlType {
+	case "":
+		return ""
+	case sqlTypePercent:
+		return FormatPercent
+	case sqlTypeCurrency:
+		return FormatCurrency
+	case sqlTypeDate:
+		return FormatDate
+	case sqlTypeDatetime, "TIMESTAMP":
+		return FormatTime
+	case sqlTypeDecimal, "NUMERIC":
+		return FormatFloat
+	}
+	switch {
+	case strings.Contains(sqlType, "INT"):
+		return FormatInt
+	case strings.Contains(sqlType, "REAL"), strings.Contains(sqlType, "FLOA"), strings.Contains(sqlType, "DOUB"):
+		return FormatFloat
+	}
+	return FormatString
+}
+
+// inferSQLFormat updates the inferred format of a computed column with a value.
+func inferSQLFormat(cur string, v any) string {
+	switch v.(type) {
+	case nil:
+		return cur
+	case int64:
+		if cur == "" {
+			return FormatInt
+		}
+		return cur
+	case float64:
+		if cur == "" || cur == FormatInt {
+			return FormatFloat
+		}
+		return cur
+	}
+	return FormatString
+}
+
+func sqlValueString(v any, format string) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case []byte:
+		return string(t)
+	case string:
+		return t
+	case time.Time:
+		if format == FormatDate {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// ReadTableSetSQLite reads all tables in a SQLite database into a `TableSet`,
+// in creation order. See `ReadSQL`.
+func ReadTableSetSQLite(db *sql.DB) (*TableSet, error) {
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	ts := NewTableSet("")
+	for _, name := range names {
+		tbl, err := ReadSQL(db, name, "SELECT * FROM "+quoteSQLIdentifier(name))
+		if err != nil {
+			return nil, fmt.Errorf("table [%s]: %w", name, err)
+		}
+		if err := ts.Add(tbl); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+// openSQLiteFile opens an existing SQLite file.
+func openSQLiteFile(filename string) (*sql.DB, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, err
+	}
+	return sql.Open(SQLiteDriverName, filename)
+}
+
+// ReadFileSQLite reads all tables in a SQLite file into a `TableSet`.
+func ReadFileSQLite(filename string) (*TableSet, error) {
+	db, err := openSQLiteFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := ReadTableSetSQLite(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return ts, db.Close()
+}
+
+// ReadFileSQLiteQuery reads the results of a SQL query on a SQLite file into a table.
+func ReadFileSQLiteQuery(filename, name, query string, args ...any) (*Table, error) {
+	db, err := openSQLiteFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := ReadSQL(db, name, query, args...)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return tbl, db.Close()
+}
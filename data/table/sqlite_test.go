@@ -0,0 +1,61 @@
+package table
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	tbl := NewTable("Sales")
+	tbl.Columns = []string{"region", "day", "units", "revenue", "margin"}
+	tbl.Rows = [][]string{
+		{"West", "2024-01-15", "12", "1200.5", "0.25"},
+		{"East", "2024-02-01", "", "300", "0.1"},
+	}
+	tbl.FormatMap = map[int]string{1: FormatDate, 2: FormatInt, 3: FormatFloat, 4: FormatPercent}
+	ts := NewTableSet("")
+	if err := ts.Add(&tbl); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "sales.db")
+	if err := ts.WriteFileSQLite(filename, nil); err != nil {
+		t.Fatalf("TableSet.WriteFileSQLite() error: [%v]", err)
+	}
+	if err := ts.WriteFileSQLite(filename, nil); err == nil {
+		t.Error("TableSet.WriteFileSQLite() expected error for existing table")
+	}
+	if err := ts.WriteFileSQLite(filename, &SQLOptions{IfExists: SQLIfExistsAppend}); err != nil {
+		t.Fatalf("TableSet.WriteFileSQLite() append error: [%v]", err)
+	}
+
+	ts2, err := ReadFileSQLite(filename)
+	if err != nil {
+		t.Fatalf("ReadFileSQLite() error: [%v]", err)
+	}
+	tbl2, err := ts2.Table("Sales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tbl2.Rows) != 4 {
+		t.Errorf("ReadFileSQLite() rows mismatch: want [%d] got [%d]", 4, len(tbl2.Rows))
+	}
+	for x, want := range tbl.Rows[1] {
+		if got := tbl2.Rows[1][x]; got != want {
+			t.Errorf("ReadFileSQLite() cell [%d] mismatch: want [%s] got [%s]", x, want, got)
+		}
+	}
+	for x, want := range tbl.FormatMap {
+		if got := tbl2.FormatMap[x]; got != want {
+			t.Errorf("ReadFileSQLite() format [%d] mismatch: want [%s] got [%s]", x, want, got)
+		}
+	}
+
+	tbl3, err := ReadFileSQLiteQuery(filename, "Totals",
+		`SELECT region, SUM(revenue) AS revenue FROM "Sales" WHERE units IS NOT NULL GROUP BY region`)
+	if err != nil {
+		t.Fatalf("ReadFileSQLiteQuery() error: [%v]", err)
+	}
+	if len(tbl3.Rows) != 1 || tbl3.Rows[0][1] != "2401" || tbl3.FormatMap[1] != FormatFloat {
+		t.Errorf("ReadFileSQLiteQuery() mismatch: want [%s %s] got [%v %v]", "2401", FormatFloat, tbl3.Rows, tbl3.FormatMap)
+	}
+}
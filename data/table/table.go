@@ -25,6 +25,7 @@ type Table struct {
 	RowsFloat64         [][]float64
 	IsFloat64           bool
 	FormatMap           FormatMap
+	Schema              *Schema
 	FormatFunc          func(val string, colIdx uint32) (any, error) `json:"-"`
 	FormatAutoLink      bool
 	BackgroundColorFunc func(colIdx, rowIdx uint) string `json:"-"`
@@ -87,6 +88,7 @@ func (tbl *Table) Clone(inclRows bool) *Table {
 		Columns:     slices.Clone(tbl.Columns),
 		IsFloat64:   tbl.IsFloat64,
 		FormatMap:   map[int]string{},
+		Schema:      tbl.Schema,
 		ID:          tbl.ID,
 		Class:       tbl.Class,
 		Style:       tbl.Style,
@@ -210,24 +210,28 @@ func (tbl *Table) ColumnValuesCounts(colIdx int, trimSpace, includeEmpty, lowerC
 		return m
 	}
 	for _, row := range tbl.Rows {
-		if colIdx >= len(row) {
-			continue
-		}
-		v := row[colIdx]
-		if trimSpace {
-			v = strings.TrimSpace(v)
-		}
-		if !includeEmpty && v == "" {
-			continue
-		}
-		if lowerCase {
-			v = strings.ToLower(v)
-		}
-		m[v]++
+		columnValuesCountsAdd(m, row, colIdx, trimSpace, includeEmpty, lowerCase)
 	}
 	return m
 }
 
+func columnValuesCountsAdd(m map[string]int, row []string, colIdx int, trimSpace, includeEmpty, lowerCase bool) {
+	if colIdx >= len(row) {
+		return
+	}
+	v := row[colIdx]
+	if trimSpace {
+		v = strings.TrimSpace(v)
+	}
+	if !includeEmpty && v == "" {
+		return
+	}
+	if lowerCase {
+		v = strings.ToLower(v)
+	}
+	m[v]++
+}
+
 func (tbl *Table) ColumnValuesSplit(colIdx uint32, split bool, sep string, unique, sortResults bool) ([]string, map[string]int, error) {
 	msi := map[string]int{}
 	vals := []string{}
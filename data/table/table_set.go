@@ -14,12 +14,13 @@ import (
 )
 
 type TableSet struct {
-	Name       string
-	Columns    []string
-	FormatMap  map[int]string
-	FormatFunc func(val string, colIdx uint32) (any, error)
-	TableMap   map[string]*Table
-	Order      []string
+	Name          string
+	Columns       []string
+	FormatMap     map[int]string
+	FormatFunc    func(val string, colIdx uint32) (any, error)
+	TableMap      map[string]*Table
+	Order         []string
+	Relationships []Relationship
 }
 
 func NewTableSet(name string) *TableSet {
@@ -52,8 +52,16 @@ func (tbl *Table) RowMapAny(row []string, fmtFunc func(val string, colIdx uint32
 
 // FormatterFunc returns a formatter function. A custom format func is returned if it is
 // supplied and `FormatMap` is empty. If FormatMap is not empty, a function for it is
-// returned.`
+// returned.` If `Schema` is set, columns in the schema are parsed to typed values with
+// `ColumnSchema.Parse` and other columns are formatted as above.
 func (tbl *Table) FormatterFunc() func(val string, colIdx uint32) (any, error) {
+	if tbl.Schema != nil {
+		return tbl.Schema.formatterFunc(tbl.Columns, false, tbl.formatterFuncFormatMap())
+	}
+	return tbl.formatterFuncFormatMap()
+}
+
+func (tbl *Table) formatterFuncFormatMap() func(val string, colIdx uint32) (any, error) {
 	if len(tbl.FormatMap) == 0 {
 		if tbl.FormatFunc != nil {
 			return tbl.FormatFunc
@@ -68,7 +76,7 @@ func (tbl *Table) FormatterFunc() func(val string, colIdx uint32) (any, error) {
 			}
 		}
 		switch strings.ToLower(strings.TrimSpace(fmtType)) {
-		case FormatFloat:
+		case FormatFloat, FormatCurrency:
 			if strings.TrimSpace(val) == "" {
 				return float64(0), nil
 			} else if floatVal, err := strconv.ParseFloat(val, 64); err != nil {
@@ -118,7 +126,16 @@ func (tbl *Table) FormatterFunc() func(val string, colIdx uint32) (any, error) {
 	}
 }
 
+// FormatterFuncHTML returns a formatter function for HTML output which returns `string`
+// values. If `Schema` is set, columns in the schema are formatted with `ColumnSchema.Format`.
 func (tbl *Table) FormatterFuncHTML() func(val string, colIdx uint32) (any, error) {
+	if tbl.Schema != nil {
+		return tbl.Schema.formatterFunc(tbl.Columns, true, tbl.formatterFuncHTMLFormatMap())
+	}
+	return tbl.formatterFuncHTMLFormatMap()
+}
+
+func (tbl *Table) formatterFuncHTMLFormatMap() func(val string, colIdx uint32) (any, error) {
 	if len(tbl.FormatMap) == 0 {
 		if tbl.FormatFunc != nil {
 			return tbl.FormatFunc
@@ -236,27 +253,9 @@ func WriteXLSX(path string, tbls []*Table) error {
 							return err
 						}
 					case FormatURL:
-						txt, lnk := markdown.ParseLink(cellValue)
-						txt = strings.TrimSpace(txt)
-						lnk = strings.TrimSpace(lnk)
-						if txt == "" && lnk != "" {
-							txt = lnk
-						}
-						if txt != "" && lnk != "" {
-							if err := f.SetCellValue(sheetName, cellLocation, txt); err != nil {
-								return err
-							}
-							if err := f.SetCellHyperLink(sheetName, cellLocation, lnk, excelizeLinkTypeExternal); err != nil {
-								return err
-							}
-							continue
-						} else if rxURLHTTPOrHTTPS.MatchString(cellValue) {
-							if err := f.SetCellValue(sheetName, cellLocation, cellValue); err != nil {
-								return err
-							}
-							if err := f.SetCellHyperLink(sheetName, cellLocation, cellValue, excelizeLinkTypeExternal); err != nil {
-								return err
-							}
+						if ok, err := setXLSXCellLink(f, sheetName, cellLocation, cellValue); err != nil {
+							return err
+						} else if ok {
 							continue
 						}
 					}
@@ -278,6 +277,11 @@ func WriteXLSX(path string, tbls []*Table) error {
 				}
 			}
 		}
+		if tbl.Schema != nil && len(tbl.Rows) > 0 {
+			if err := writeXLSXSchemaStyles(f, sheetName, tbl, rowBase); err != nil {
+				return err
+			}
+		}
 		// Set active sheet of the workbook.
 		if i == 0 {
 			f.SetActiveSheet(sheetIndex)
@@ -294,6 +298,54 @@ func WriteXLSX(path string, tbls []*Table) error {
 	return f.SaveAs(path)
 }
 
+// setXLSXCellLink sets a hyperlink cell for a Markdown link or URL value. It
+// returns false if the value is not a link.
+func setXLSXCellLink(f *excelize.File, sheetName, cellLocation, cellValue string) (bool, error) {
+	txt, lnk := markdown.ParseLink(cellValue)
+	txt = strings.TrimSpace(txt)
+	lnk = strings.TrimSpace(lnk)
+	if txt == "" && lnk != "" {
+		txt = lnk
+	}
+	if txt == "" || lnk == "" {
+		if !rxURLHTTPOrHTTPS.MatchString(cellValue) {
+			return false, nil
+		}
+		txt, lnk = cellValue, cellValue
+	}
+	if err := f.SetCellValue(sheetName, cellLocation, txt); err != nil {
+		return false, err
+	}
+	return true, f.SetCellHyperLink(sheetName, cellLocation, lnk, excelizeLinkTypeExternal)
+}
+
+// writeXLSXSchemaStyles sets the number format of each schema column with a typed
+// Excel representation. Styles are set per column rather than per cell.
+func writeXLSXSchemaStyles(f *excelize.File, sheetName string, tbl *Table, rowBase uint32) error {
+	rowEnd := uint32(len(tbl.Rows)) - 1 + rowBase //nolint:gosec // G115: row count is bounded by Excel
+	for x, cs := range tbl.Schema.tableColumns(tbl.Columns) {
+		if cs == nil {
+			continue
+		}
+		numFmt, ok := cs.excelNumFmt()
+		if !ok {
+			continue
+		}
+		style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+		if err != nil {
+			return err
+		}
+		xUint32 := uint32(x) //nolint:gosec // G115: column count is bounded by Excel
+		if err := f.SetCellStyle(sheetName,
+			sheet.CoordinatesToSheetLocation(xUint32, rowBase),
+			sheet.CoordinatesToSheetLocation(xUint32, rowEnd),
+			style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type SheetData struct {
 	SheetName string
 	Rows      [][]any
@@ -0,0 +1,586 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+	"github.com/grokify/gocharts/v2/charts/chartir/xlsx"
+	"github.com/grokify/gocharts/v2/data/table/sheet"
+	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/grokify/mogo/image/colors"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+const (
+	defaultXLSXMaxColumnWidth = 60
+	defaultXLSXTotalsLabel    = "Total"
+	xlsxChartRowHeight        = 22 // rows per chart, for stacking charts
+)
+
+// XLSXTotal is the aggregate function for a column in a totals row.
+type XLSXTotal string
+
+const (
+	XLSXTotalSum     XLSXTotal = "sum"
+	XLSXTotalAverage XLSXTotal = "average"
+	XLSXTotalCount   XLSXTotal = "count"
+	XLSXTotalMin     XLSXTotal = "min"
+	XLSXTotalMax     XLSXTotal = "max"
+)
+
+// subtotalFunc returns the Excel `SUBTOTAL` function number, which ignores
+// rows hidden by an autofilter.
+func (t XLSXTotal) subtotalFunc() (int, error) {
+	switch t {
+	case XLSXTotalSum:
+		return 109, nil
+	case XLSXTotalAverage:
+		return 101, nil
+	case XLSXTotalCount:
+		return 103, nil
+	case XLSXTotalMin:
+		return 105, nil
+	case XLSXTotalMax:
+		return 104, nil
+	default:
+		return 0, fmt.Errorf("xlsx total not supported [%s]", t)
+	}
+}
+
+// XLSXConditionalFormat is an Excel conditional format rule for the data
+// cells of a column. If `Style` is set, it is used as the format of the rule.
+type XLSXConditionalFormat struct {
+	Column string
+	Rule   excelize.ConditionalFormatOptions
+	Style  *excelize.Style
+}
+
+// XLSXChart is a native Excel chart in a report. If `ChartIR` is set, its
+// datasets are written to a hidden data sheet. Otherwise the chart plots the
+// `YColumns` of the table against `XColumn`.
+type XLSXChart struct {
+	Sheet    string           // table name, default the first table
+	Cell     string           // top left cell, default to the right of the table
+	ChartIR  *chartir.ChartIR // e.g. from `data2chartir.TimeSeriesSetChartIR()`
+	Geometry chartir.Geometry // for table charts, default line
+	Stack    bool             // stack table chart marks
+	Title    string
+	XColumn  string
+	YColumns []string
+}
+
+// XLSXReportOptions configures `WriteXLSXReport`.
+type XLSXReportOptions struct {
+	HeaderStyle        *excelize.Style // default bold with a gray fill and bottom border
+	AutoWidth          bool            // set column widths from cell contents
+	MaxColumnWidth     float64         // default 60
+	FreezeHeader       bool            // freeze the header row
+	FreezeColumns      uint            // number of left columns to freeze
+	AutoFilter         bool
+	BackgroundColors   bool                 // fill cells using hex colors from `Table.BackgroundColorFunc`
+	Totals             map[string]XLSXTotal // totals row aggregates by column name
+	TotalsLabel        string               // label in the first column, default "Total"
+	ConditionalFormats []XLSXConditionalFormat
+	Charts             []XLSXChart
+	ChartCompiler      *xlsx.Compiler // default `xlsx.NewCompiler()` with a hidden data sheet
+}
+
+func defaultXLSXHeaderStyle() *excelize.Style {
+	return &excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Color: []string{"D9D9D9"}, Pattern: 1},
+		Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+	}
+}
+
+// xlsxNumFmt returns the Excel number format for a column, using the schema
+// if set, or `FormatMap` otherwise.
+func (tbl *Table) xlsxNumFmt(colIdx int, cs *ColumnSchema) string {
+	if cs != nil {
+		if numFmt, ok := cs.excelNumFmt(); ok {
+			return numFmt
+		}
+		return ""
+	}
+	fmtType, ok := tbl.FormatMap[colIdx]
+	if !ok {
+		fmtType = tbl.FormatMap[-1]
+	}
+	switch strings.ToLower(strings.TrimSpace(fmtType)) {
+	case FormatInt:
+		return "#,##0"
+	case FormatFloat:
+		return "#,##0.00"
+	case FormatPercent:
+		return "0.00%"
+	case FormatCurrency:
+		return `"$"#,##0.00`
+	case FormatDate:
+		return "yyyy-mm-dd"
+	case FormatTime:
+		return "yyyy-mm-dd hh:mm:ss"
+	default:
+		return ""
+	}
+}
+
+// WriteXLSXReport writes the table as a formatted XLSX report. See `WriteXLSXReport`.
+func (tbl *Table) WriteXLSXReport(path string, opts *XLSXReportOptions) error {
+	return WriteXLSXReport(path, []*Table{tbl}, opts)
+}
+
+// WriteXLSXReport writes the TableSet as a formatted XLSX report. See `WriteXLSXReport`.
+func (ts *TableSet) WriteXLSXReport(filename string, opts *XLSXReportOptions) error {
+	names := ts.Order
+	if len(names) == 0 {
+		names = ts.TableNames()
+	}
+	if err := WriteXLSXReport(filename, ts.Tables(names), opts); err != nil {
+		return errorsutil.Wrapf(err, "error in TableSet.WriteXLSXReport(%s)", filename)
+	}
+	return nil
+}
+
+// WriteXLSXReport writes tables as an Excel XLSX report with one sheet per
+// table. In addition to `WriteXLSX`, it styles the header row, sets number
+// formats per column from `Schema` or `FormatMap`, writes dates as Excel dates,
+// and optionally sets column widths, freeze panes, an autofilter,
+// background colors, conditional formats, a totals row and native charts.
+func WriteXLSXReport(path string, tbls []*Table, opts *XLSXReportOptions) error {
+	f, err := NewXLSXReport(tbls, opts)
+	if err != nil {
+		return err
+	}
+	return f.SaveAs(path)
+}
+
+// NewXLSXReport returns an `excelize.File` report for further editing. See `WriteXLSXReport`.
+func NewXLSXReport(tbls []*Table, opts *XLSXReportOptions) (*excelize.File, error) {
+	if opts == nil {
+		opts = &XLSXReportOptions{}
+	}
+	tables := []*Table{}
+	for _, tbl := range tbls {
+		if tbl != nil {
+			tables = append(tables, tbl)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, ErrTablesCannotBeEmpty
+	}
+
+	f := excelize.NewFile()
+	defaultSheet := f.GetSheetName(0)
+	sheetNames := map[string]int{}
+	reportSheets := map[string]*xlsxReportSheet{}
+	var firstSheet string
+	for i, tbl := range tables {
+		sheetName := strings.TrimSpace(tbl.Name)
+		if len(sheetName) == 0 {
+			sheetName = fmt.Sprintf("Sheet%d", i+1)
+		}
+		if _, ok := sheetNames[sheetName]; ok {
+			return nil, errorsutil.Wrap(ErrSheetNameCollision, "sheet name collision for (%s)", sheetName)
+		}
+		sheetNames[sheetName]++
+		if sheetName != defaultSheet {
+			if _, err := f.NewSheet(sheetName); err != nil {
+				return nil, errorsutil.Wrap(err, "excelize.File.NewSheet()")
+			}
+		}
+		rs := &xlsxReportSheet{f: f, name: sheetName, tbl: tbl, opts: opts}
+		if err := rs.write(); err != nil {
+			return nil, err
+		}
+		reportSheets[sheetName] = rs
+		if i == 0 {
+			firstSheet = sheetName
+			reportSheets[""] = rs
+		}
+	}
+	if _, ok := sheetNames[defaultSheet]; !ok {
+		if err := f.DeleteSheet(defaultSheet); err != nil {
+			return nil, errorsutil.Wrap(err, "excelize.File.DeleteSheet()")
+		}
+	}
+
+	compiler := opts.ChartCompiler
+	if compiler == nil {
+		compiler = xlsx.NewCompiler()
+		compiler.HideDataSheet = true
+	}
+	chartCounts := map[string]int{}
+	for _, chart := range opts.Charts {
+		rs, ok := reportSheets[strings.TrimSpace(chart.Sheet)]
+		if !ok {
+			return nil, fmt.Errorf("chart sheet not found [%s]", chart.Sheet)
+		}
+		if err := rs.addChart(compiler, chart, chartCounts[rs.name]); err != nil {
+			return nil, err
+		}
+		chartCounts[rs.name]++
+	}
+	if idx, err := f.GetSheetIndex(firstSheet); err != nil {
+		return nil, err
+	} else {
+		f.SetActiveSheet(idx)
+	}
+	return f, nil
+}
+
+// xlsxReportSheet writes one table to a report sheet.
+type xlsxReportSheet struct {
+	f      *excelize.File
+	name   string
+	tbl    *Table
+	opts   *XLSXReportOptions
+	styles map[string]int // style IDs by number format and fill color
+}
+
+// dataRows returns the 1-based first and last rows of table data.
+func (rs *xlsxReportSheet) dataRows() (int, int) {
+	return 2, len(rs.tbl.Rows) + 1
+}
+
+func (rs *xlsxReportSheet) style(numFmt, color string, bold bool) (int, error) {
+	key := fmt.Sprintf("%s|%s|%v", numFmt, color, bold)
+	if id, ok := rs.styles[key]; ok {
+		return id, nil
+	}
+	style := &excelize.Style{}
+	if numFmt != "" {
+		style.CustomNumFmt = &numFmt
+	}
+	if color != "" {
+		style.Fill = excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}
+	}
+	if bold {
+		style.Font = &excelize.Font{Bold: true}
+		style.Border = []excelize.Border{{Type: "top", Color: "000000", Style: 1}}
+	}
+	id, err := rs.f.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	if rs.styles == nil {
+		rs.styles = map[string]int{}
+	}
+	rs.styles[key] = id
+	return id, nil
+}
+
+func (rs *xlsxReportSheet) write() error {
+	f, tbl, opts := rs.f, rs.tbl, rs.opts
+	var schemaCols []*ColumnSchema
+	if tbl.Schema != nil {
+		schemaCols = tbl.Schema.tableColumns(tbl.Columns)
+	}
+	numFmts := make([]string, len(tbl.Columns))
+	widths := make([]int, len(tbl.Columns))
+	for x, colName := range tbl.Columns {
+		var cs *ColumnSchema
+		if x < len(schemaCols) {
+			cs = schemaCols[x]
+		}
+		numFmts[x] = tbl.xlsxNumFmt(x, cs)
+		widths[x] = utf8.RuneCountInString(colName)
+		cell := sheet.CoordinatesToSheetLocation(uint32(x), 0) //nolint:gosec // G115: column count is bounded by Excel
+		if err := f.SetCellValue(rs.name, cell, colName); err != nil {
+			return err
+		}
+	}
+	if len(tbl.Columns) > 0 {
+		headerStyle := opts.HeaderStyle
+		if headerStyle == nil {
+			headerStyle = defaultXLSXHeaderStyle()
+		}
+		styleID, err := f.NewStyle(headerStyle)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(rs.name, "A1", sheet.CoordinatesToSheetLocation(uint32(len(tbl.Columns)-1), 0), styleID); err != nil { //nolint:gosec // G115: column count is bounded by Excel
+			return err
+		}
+	}
+
+	fmtFunc := tbl.FormatterFunc()
+	for y, row := range tbl.Rows {
+		for x, cellValue := range row {
+			xUint32, yUint32 := uint32(x), uint32(y)+1 //nolint:gosec // G115: row and column counts are bounded by Excel
+			cell := sheet.CoordinatesToSheetLocation(xUint32, yUint32)
+			if x < len(widths) {
+				widths[x] = max(widths[x], utf8.RuneCountInString(cellValue))
+			}
+			if err := rs.setCellValue(cell, cellValue, xUint32, numFmts, schemaCols, fmtFunc); err != nil {
+				return err
+			}
+			numFmt := ""
+			if x < len(numFmts) {
+				numFmt = numFmts[x]
+			}
+			color := ""
+			if opts.BackgroundColors && tbl.BackgroundColorFunc != nil {
+				c, err := xlsxBackgroundColor(tbl.BackgroundColorFunc(uint(x), uint(y)))
+				if err != nil {
+					return fmt.Errorf("invalid background color for cell [%s]: %w", cell, err)
+				}
+				color = c
+			}
+			if numFmt == "" && color == "" {
+				continue
+			}
+			styleID, err := rs.style(numFmt, color, false)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellStyle(rs.name, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := rs.writeTotals(numFmts); err != nil {
+		return err
+	}
+	if err := rs.writeConditionalFormats(); err != nil {
+		return err
+	}
+	if opts.AutoWidth {
+		maxWidth := opts.MaxColumnWidth
+		if maxWidth <= 0 {
+			maxWidth = defaultXLSXMaxColumnWidth
+		}
+		for x, w := range widths {
+			col := sheet.ColIndexToLetters(uint32(x)) //nolint:gosec // G115: column count is bounded by Excel
+			if err := f.SetColWidth(rs.name, col, col, min(max(float64(w)+2, 8), maxWidth)); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.AutoFilter && len(tbl.Columns) > 0 {
+		_, lastRow := rs.dataRows()
+		ref := "A1:" + sheet.CoordinatesToSheetLocation(uint32(len(tbl.Columns)-1), uint32(max(lastRow, 2)-1)) //nolint:gosec // G115: row and column counts are bounded by Excel
+		if err := f.AutoFilter(rs.name, ref, nil); err != nil {
+			return err
+		}
+	}
+	if opts.FreezeHeader || opts.FreezeColumns > 0 {
+		ySplit := 0
+		if opts.FreezeHeader {
+			ySplit = 1
+		}
+		xSplit := int(opts.FreezeColumns) //nolint:gosec // G115: column count is bounded by Excel
+		topLeft, err := excelize.CoordinatesToCellName(xSplit+1, ySplit+1)
+		if err != nil {
+			return err
+		}
+		activePane := "bottomRight"
+		if xSplit == 0 {
+			activePane = "bottomLeft"
+		} else if ySplit == 0 {
+			activePane = "topRight"
+		}
+		if err := f.SetPanes(rs.name, &excelize.Panes{
+			Freeze:      true,
+			XSplit:      xSplit,
+			YSplit:      ySplit,
+			TopLeftCell: topLeft,
+			ActivePane:  activePane,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCellValue writes a cell as a typed value. Dates in `FormatDate` columns
+// are written as Excel dates instead of the `WriteXLSX` text format.
+func (rs *xlsxReportSheet) setCellValue(cell, cellValue string, x uint32, numFmts []string, schemaCols []*ColumnSchema, fmtFunc func(val string, colIdx uint32) (any, error)) error {
+	f, tbl := rs.f, rs.tbl
+	if int(x) >= len(schemaCols) || schemaCols[x] == nil {
+		fmtType, ok := tbl.FormatMap[int(x)]
+		if !ok {
+			fmtType = tbl.FormatMap[-1]
+		}
+		switch strings.ToLower(strings.TrimSpace(fmtType)) {
+		case FormatURL:
+			if ok, err := setXLSXCellLink(f, rs.name, cell, cellValue); err != nil || ok {
+				return err
+			}
+		case FormatDate:
+			if strings.TrimSpace(cellValue) == "" {
+				return nil
+			} else if dt, err := time.Parse(time.DateOnly, cellValue); err == nil {
+				return f.SetCellValue(rs.name, cell, dt)
+			}
+		}
+	}
+	formattedVal, err := fmtFunc(cellValue, x)
+	if err != nil {
+		return errorsutil.Wrap(err, "gocharts/data/tables/write_xlsx_report.go/WriteXLSXReport.Error.FormatCellValue")
+	}
+	if err := f.SetCellValue(rs.name, cell, formattedVal); err != nil {
+		return err
+	}
+	if tbl.FormatAutoLink && rxURLHTTPOrHTTPS.MatchString(cellValue) {
+		return f.SetCellHyperLink(rs.name, cell, cellValue, excelizeLinkTypeExternal)
+	}
+	return nil
+}
+
+// xlsxBackgroundColor returns a background color as an uppercase hex color
+// without "#", as for `excelizeutil.StyleBackgroundColorSimple`, or an empty
+// string for no color.
+func xlsxBackgroundColor(color string) (string, error) {
+	if strings.TrimSpace(color) == "" {
+		return "", nil
+	}
+	return colors.CanonicalHex(color, true, false)
+}
+
+func (rs *xlsxReportSheet) writeTotals(numFmts []string) error {
+	if len(rs.opts.Totals) == 0 {
+		return nil
+	}
+	firstRow, lastRow := rs.dataRows()
+	totalsRow := uint32(lastRow) //nolint:gosec // G115: row count is bounded by Excel
+	for colName := range rs.opts.Totals {
+		if rs.tbl.Columns.Index(colName) < 0 {
+			return fmt.Errorf("totals column not found [%s]", colName)
+		}
+	}
+	label := rs.opts.TotalsLabel
+	if label == "" {
+		label = defaultXLSXTotalsLabel
+	}
+	for x, colName := range rs.tbl.Columns {
+		xUint32 := uint32(x) //nolint:gosec // G115: column count is bounded by Excel
+		cell := sheet.CoordinatesToSheetLocation(xUint32, totalsRow)
+		numFmt := ""
+		if total, ok := rs.opts.Totals[colName]; ok {
+			fn, err := total.subtotalFunc()
+			if err != nil {
+				return err
+			}
+			if lastRow < firstRow {
+				// without data rows the range would include the totals cell
+				if err := rs.f.SetCellValue(rs.name, cell, 0); err != nil {
+					return err
+				}
+			} else {
+				col := sheet.ColIndexToLetters(xUint32)
+				formula := fmt.Sprintf("SUBTOTAL(%d,%s%d:%s%d)", fn, col, firstRow, col, lastRow)
+				if err := rs.f.SetCellFormula(rs.name, cell, formula); err != nil {
+					return err
+				}
+			}
+			if total != XLSXTotalCount {
+				numFmt = numFmts[x]
+			}
+		} else if x == 0 {
+			if err := rs.f.SetCellValue(rs.name, cell, label); err != nil {
+				return err
+			}
+		}
+		styleID, err := rs.style(numFmt, "", true)
+		if err != nil {
+			return err
+		}
+		if err := rs.f.SetCellStyle(rs.name, cell, cell, styleID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *xlsxReportSheet) writeConditionalFormats() error {
+	firstRow, lastRow := rs.dataRows()
+	if lastRow < firstRow {
+		return nil
+	}
+	for _, cf := range rs.opts.ConditionalFormats {
+		x := rs.tbl.Columns.Index(cf.Column)
+		if x < 0 {
+			return fmt.Errorf("conditional format column not found [%s]", cf.Column)
+		}
+		rule := cf.Rule
+		if cf.Style != nil {
+			styleID, err := rs.f.NewConditionalStyle(cf.Style)
+			if err != nil {
+				return err
+			}
+			rule.Format = &styleID
+		}
+		col := sheet.ColIndexToLetters(uint32(x)) //nolint:gosec // G115: column count is bounded by Excel
+		ref := fmt.Sprintf("%s%d:%s%d", col, firstRow, col, lastRow)
+		if err := rs.f.SetConditionalFormat(rs.name, ref, []excelize.ConditionalFormatOptions{rule}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addChart adds a chart to the sheet. The `n`th chart on a sheet defaults to
+// a position to the right of the table, below earlier charts.
+func (rs *xlsxReportSheet) addChart(compiler *xlsx.Compiler, chart XLSXChart, n int) error {
+	cell := chart.Cell
+	if cell == "" {
+		cell = sheet.CoordinatesToSheetLocation(uint32(len(rs.tbl.Columns)+1), uint32(n*xlsxChartRowHeight+1)) //nolint:gosec // G115: column and chart counts are bounded by Excel
+	}
+	if chart.ChartIR != nil {
+		return compiler.AddChart(rs.f, rs.name, cell, chart.ChartIR)
+	}
+	ir, err := rs.tableChartIR(chart)
+	if err != nil {
+		return err
+	}
+	if err := chartir.Validate(ir); err != nil {
+		return err
+	}
+	charts, err := compiler.Compile(ir, map[string]xlsx.DatasetRange{
+		rs.name: {Sheet: rs.name, Columns: rs.tbl.Columns, RowCount: len(rs.tbl.Rows)},
+	})
+	if err != nil {
+		return err
+	}
+	return rs.f.AddChart(rs.name, cell, charts[0], charts[1:]...)
+}
+
+// tableChartIR returns a ChartIR for a chart of the table columns. Rows are
+// omitted because the chart references the sheet cells.
+func (rs *xlsxReportSheet) tableChartIR(chart XLSXChart) (*chartir.ChartIR, error) {
+	if chart.XColumn == "" || len(chart.YColumns) == 0 {
+		return nil, fmt.Errorf("chart requires an x column and y columns")
+	}
+	geometry := chart.Geometry
+	if geometry == "" {
+		geometry = chartir.GeometryLine
+	}
+	ds := chartir.Dataset{ID: rs.name}
+	for _, colName := range append([]string{chart.XColumn}, chart.YColumns...) {
+		if rs.tbl.Columns.Index(colName) < 0 {
+			return nil, fmt.Errorf("chart column not found [%s]", colName)
+		}
+		colType := chartir.ColumnTypeNumber
+		if colName == chart.XColumn {
+			colType = chartir.ColumnTypeString
+		}
+		ds.Columns = append(ds.Columns, chartir.Column{Name: colName, Type: colType})
+	}
+	ir := &chartir.ChartIR{Title: chart.Title, Datasets: []chartir.Dataset{ds}}
+	for _, colName := range chart.YColumns {
+		mark := chartir.Mark{ID: colName, DatasetID: rs.name, Geometry: geometry,
+			Encode: chartir.Encode{X: chart.XColumn, Y: colName}}
+		if geometry == chartir.GeometryPie {
+			mark.Encode = chartir.Encode{Name: chart.XColumn, Value: colName}
+		}
+		if chart.Stack {
+			mark.Stack = "total"
+		}
+		ir.Marks = append(ir.Marks, mark)
+	}
+	return ir, nil
+}
@@ -0,0 +1,115 @@
+package table
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/grokify/gocharts/v2/charts/chartir"
+)
+
+func TestNewXLSXReport(t *testing.T) {
+	tbl := NewTable("Sales")
+	tbl.Columns = []string{"region", "day", "revenue", "margin"}
+	tbl.Rows = [][]string{
+		{"West", "2024-01-15", "1200.5", "0.25"},
+		{"East", "2024-02-01", "300", "0.1"},
+	}
+	tbl.FormatMap = map[int]string{1: FormatDate, 2: FormatCurrency, 3: FormatPercent}
+	tbl.BackgroundColorFunc = func(colIdx, rowIdx uint) string {
+		if colIdx == 3 && rowIdx == 0 {
+			return "#C6EFCE"
+		} else if colIdx == 3 && rowIdx == 1 {
+			return " ffc7ce"
+		}
+		return ""
+	}
+	f, err := NewXLSXReport([]*Table{&tbl}, &XLSXReportOptions{
+		AutoWidth:        true,
+		FreezeHeader:     true,
+		AutoFilter:       true,
+		BackgroundColors: true,
+		Totals:           map[string]XLSXTotal{"revenue": XLSXTotalSum},
+		Charts: []XLSXChart{
+			{XColumn: "region", YColumns: []string{"revenue"}, Geometry: chartir.GeometryBar},
+			{ChartIR: &chartir.ChartIR{
+				Datasets: []chartir.Dataset{{ID: "d", Columns: []chartir.Column{{Name: "x", Type: chartir.ColumnTypeString}, {Name: "y", Type: chartir.ColumnTypeNumber}}, Rows: [][]string{{"a", "1"}}}},
+				Marks:    []chartir.Mark{{ID: "m", DatasetID: "d", Geometry: chartir.GeometryLine, Encode: chartir.Encode{X: "x", Y: "y"}}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewXLSXReport() error: [%v]", err)
+	}
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"B2", "2024-01-15"},
+		{"C2", "$1,200.50"},
+		{"D3", "10.00%"},
+		{"A4", "Total"},
+	}
+	for _, tt := range tests {
+		if got, err := f.GetCellValue("Sales", tt.cell); err != nil || got != tt.want {
+			t.Errorf("NewXLSXReport() cell [%s] mismatch: want [%s] got [%s]", tt.cell, tt.want, got)
+		}
+	}
+	if formula, err := f.GetCellFormula("Sales", "C4"); err != nil || formula != "SUBTOTAL(109,C2:C3)" {
+		t.Errorf("NewXLSXReport() totals mismatch: want [%s] got [%s]", "SUBTOTAL(109,C2:C3)", formula)
+	}
+	if styleID, err := f.GetCellStyle("Sales", "D2"); err != nil {
+		t.Fatal(err)
+	} else if style, err := f.GetStyle(styleID); err != nil || len(style.Fill.Color) == 0 || style.Fill.Color[0] != "C6EFCE" {
+		t.Errorf("NewXLSXReport() background mismatch: want [%s] got [%v]", "C6EFCE", style.Fill.Color)
+	}
+	if styleID, err := f.GetCellStyle("Sales", "D3"); err != nil {
+		t.Fatal(err)
+	} else if style, err := f.GetStyle(styleID); err != nil || len(style.Fill.Color) == 0 || style.Fill.Color[0] != "FFC7CE" {
+		t.Errorf("NewXLSXReport() background mismatch: want [%s] got [%v]", "FFC7CE", style.Fill.Color)
+	}
+	if panes, err := f.GetPanes("Sales"); err != nil || !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("NewXLSXReport() panes mismatch: want frozen header got [%v]", panes)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("excelize.File.Write() error: [%v]", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	charts := 0
+	for _, zf := range zr.File {
+		if len(zf.Name) > 9 && zf.Name[:9] == "xl/charts" {
+			charts++
+		}
+	}
+	if charts != 2 {
+		t.Errorf("NewXLSXReport() charts mismatch: want [%d] got [%d]", 2, charts)
+	}
+}
+
+func TestNewXLSXReportEdgeCases(t *testing.T) {
+	tbl := NewTable("Empty")
+	tbl.Columns = []string{"region", "revenue"}
+	f, err := NewXLSXReport([]*Table{&tbl}, &XLSXReportOptions{
+		Totals: map[string]XLSXTotal{"revenue": XLSXTotalSum},
+	})
+	if err != nil {
+		t.Fatalf("NewXLSXReport() empty table error: [%v]", err)
+	}
+	if formula, err := f.GetCellFormula("Empty", "B2"); err != nil || formula != "" {
+		t.Errorf("NewXLSXReport() empty totals formula mismatch: want none got [%s]", formula)
+	}
+	if got, err := f.GetCellValue("Empty", "B2"); err != nil || got != "0" {
+		t.Errorf("NewXLSXReport() empty totals mismatch: want [0] got [%s]", got)
+	}
+
+	tbl.Rows = [][]string{{"West", "1"}}
+	tbl.BackgroundColorFunc = func(colIdx, rowIdx uint) string { return "green" }
+	if _, err := NewXLSXReport([]*Table{&tbl}, &XLSXReportOptions{BackgroundColors: true}); err == nil {
+		t.Error("NewXLSXReport() expected error for invalid background color")
+	}
+}
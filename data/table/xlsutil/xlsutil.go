@@ -0,0 +1,140 @@
+// Package xlsutil reads legacy Excel 97-2003 (BIFF) `.xls` files with the same
+// table semantics as `excelizeutil`.
+package xlsutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/extrame/xls"
+	"github.com/grokify/gocharts/v2/data/table/sheet"
+)
+
+// DefaultCharset is the charset used for BIFF5 and earlier files, which are
+// not encoded as UTF-16.
+const DefaultCharset = "utf-8"
+
+var ErrFileCannotBeNil = errors.New("xlsutil.File cannot be nil")
+
+// File is the worksheets of an XLS file. Merged cell ranges are not provided
+// by the BIFF reader, so unmerging only pads short rows with empty cells.
+type File struct {
+	Sheets []*sheet.Grid
+}
+
+// ReadFile reads an XLS file.
+func ReadFile(filename string) (*File, error) {
+	r, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	f, err := NewReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return f, r.Close()
+}
+
+// NewReader reads an XLS file from `r`.
+func NewReader(r io.ReadSeeker) (*File, error) {
+	wb, err := xls.OpenReader(r, DefaultCharset)
+	if err != nil {
+		return nil, err
+	} else if wb == nil {
+		return nil, errors.New("xls workbook stream not found")
+	}
+	f := &File{}
+	for i := range wb.NumSheets() {
+		ws := wb.GetSheet(i)
+		if ws == nil {
+			continue
+		}
+		g := &sheet.Grid{Name: ws.Name}
+		for rowIdx := 0; rowIdx <= int(ws.MaxRow); rowIdx++ {
+			g.Rows = append(g.Rows, rowValues(ws, rowIdx))
+		}
+		g.Compact()
+		f.Sheets = append(f.Sheets, g)
+	}
+	return f, nil
+}
+
+// rowValues returns the values of a row, with rows without cells returned as
+// empty rows.
+func rowValues(ws *xls.WorkSheet, rowIdx int) []string {
+	vals := []string{}
+	row := sheetRow(ws, rowIdx)
+	if row == nil {
+		return vals
+	}
+	// `LastCol()` is one past the last column, per the BIFF ROW record.
+	for colIdx := 0; colIdx < row.LastCol(); colIdx++ {
+		vals = append(vals, row.Col(colIdx))
+	}
+	return vals
+}
+
+// sheetRow returns a row or `nil` if the row has no cells. `xls.WorkSheet.Row()`
+// returns `nil` for missing rows in later versions of the reader, but v0.0.1
+// dereferences the missing row, so the unexported row map is checked first.
+func sheetRow(ws *xls.WorkSheet, rowIdx int) *xls.Row {
+	rows := reflect.ValueOf(ws).Elem().FieldByName("rows")
+	if rows.Kind() == reflect.Map && !rows.MapIndex(reflect.ValueOf(uint16(rowIdx))).IsValid() {
+		return nil
+	}
+	return ws.Row(rowIdx)
+}
+
+func (f *File) SheetNames(sortAsc bool) []string {
+	var names []string
+	if f == nil {
+		return names
+	}
+	for _, g := range f.Sheets {
+		names = append(names, g.Name)
+	}
+	if sortAsc {
+		sort.Strings(names)
+	}
+	return names
+}
+
+// Sheet returns the worksheet with the given name or `nil` if not found.
+func (f *File) Sheet(sheetName string) *sheet.Grid {
+	if f == nil {
+		return nil
+	}
+	for _, g := range f.Sheets {
+		if g.Name == sheetName {
+			return g
+		}
+	}
+	return nil
+}
+
+func (f *File) TableDataIndex(sheetIdx, headerRowCount uint32, trimSpace, umerge bool) ([]string, [][]string, error) {
+	if f == nil {
+		return []string{}, [][]string{}, ErrFileCannotBeNil
+	} else if int(sheetIdx) >= len(f.Sheets) {
+		return []string{}, [][]string{}, fmt.Errorf("sheet index not found [%d]", sheetIdx)
+	}
+	return f.Sheets[sheetIdx].TableData(headerRowCount, trimSpace, umerge)
+}
+
+// TableData returns the columns and rows of a worksheet with the same
+// semantics as `excelizeutil.File.TableData()`.
+func (f *File) TableData(sheetName string, headerRowCount uint32, trimSpace, umerge bool) ([]string, [][]string, error) {
+	if f == nil {
+		return []string{}, [][]string{}, ErrFileCannotBeNil
+	}
+	g := f.Sheet(sheetName)
+	if g == nil {
+		return []string{}, [][]string{}, fmt.Errorf("sheet not found [%s]", sheetName)
+	}
+	return g.TableData(headerRowCount, trimSpace, umerge)
+}
@@ -0,0 +1,42 @@
+package xlsutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableData(t *testing.T) {
+	f, err := ReadFile("testdata/simple.xls")
+	if err != nil {
+		t.Fatalf("xlsutil.ReadFile() error: [%v]", err)
+	}
+	if got := strings.Join(f.SheetNames(true), ","); got != "Data,Notes" {
+		t.Errorf("File.SheetNames() mismatch: want [%s] got [%s]", "Data,Notes", got)
+	}
+	// row 3 has no cells, so it is read as an empty row
+	tests := []struct {
+		sheetName string
+		trimSpace bool
+		want      string
+	}{
+		{"Data", true, "Name,Count|alpha,1||beta,2.5"},
+		{"Data", false, "Name,Count| alpha ,1||beta,2.5"},
+		{"Notes", true, "Note|first"},
+	}
+	for _, tt := range tests {
+		cols, rows, err := f.TableData(tt.sheetName, 1, tt.trimSpace, false)
+		if err != nil {
+			t.Fatalf("File.TableData() error: [%v]", err)
+		}
+		parts := []string{strings.Join(cols, ",")}
+		for _, row := range rows {
+			parts = append(parts, strings.Join(row, ","))
+		}
+		if got := strings.Join(parts, "|"); got != tt.want {
+			t.Errorf("File.TableData(%q) mismatch: want [%s] got [%s]", tt.sheetName, tt.want, got)
+		}
+	}
+	if _, _, err := f.TableData("Missing", 1, true, false); err == nil {
+		t.Error("File.TableData() expected error for missing sheet")
+	}
+}
@@ -0,0 +1,222 @@
+package tablef64
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/grokify/gocharts/v2/data/table"
+)
+
+// ArrowSchema returns the Arrow schema for the table, with a float64 column
+// for each table column.
+func (tbl *Table) ArrowSchema() *arrow.Schema {
+	fields := make([]arrow.Field, len(tbl.Columns))
+	for i, colName := range tbl.Columns {
+		fields[i] = arrow.Field{Name: colName, Type: arrow.PrimitiveTypes.Float64}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// ArrowRecordBatch returns the table rows as an Arrow record batch. Rows are
+// transposed into one float64 array per column. The caller must call
+// `Release` on the record batch.
+func (tbl *Table) ArrowRecordBatch(mem memory.Allocator) (arrow.RecordBatch, error) {
+	return tbl.arrowRecordBatch(mem, tbl.ArrowSchema(), 0, len(tbl.Rows))
+}
+
+func (tbl *Table) arrowRecordBatch(mem memory.Allocator, schema *arrow.Schema, start, end int) (arrow.RecordBatch, error) {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	cols := make([]arrow.Array, len(tbl.Columns))
+	defer func() {
+		for _, col := range cols {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+	vals := make([]float64, end-start)
+	for x := range tbl.Columns {
+		for y := start; y < end; y++ {
+			if x >= len(tbl.Rows[y]) {
+				return nil, fmt.Errorf("index out of bounds: index greater than row length on row (%d)", y)
+			}
+			vals[y-start] = tbl.Rows[y][x]
+		}
+		b := array.NewFloat64Builder(mem)
+		b.AppendValues(vals, nil)
+		cols[x] = b.NewArray()
+		b.Release()
+	}
+	return array.NewRecordBatch(schema, cols, int64(end-start)), nil
+}
+
+// FromArrow converts Arrow record batches with `schema` to a `Table`. All
+// columns must be integer or float types. Nulls are read as `NaN`.
+func FromArrow(schema *arrow.Schema, recs ...arrow.RecordBatch) (*Table, error) {
+	if schema == nil {
+		return nil, errors.New("arrow schema cannot be nil")
+	}
+	n := NewTable("")
+	for _, field := range schema.Fields() {
+		if !arrow.IsInteger(field.Type.ID()) && !arrow.IsFloating(field.Type.ID()) {
+			return nil, fmt.Errorf("arrow column is not numeric [%s]", field.Name)
+		}
+		n.Columns = append(n.Columns, field.Name)
+	}
+	for _, rec := range recs {
+		if !rec.Schema().Equal(schema) {
+			return nil, errors.New("arrow record batch schema mismatch")
+		}
+		rows := make([][]float64, rec.NumRows())
+		for y := range rows {
+			rows[y] = make([]float64, len(n.Columns))
+		}
+		for x, col := range rec.Columns() {
+			arrowColumnValues(col, rows, x)
+		}
+		n.Rows = append(n.Rows, rows...)
+	}
+	return n, nil
+}
+
+// arrowColumnValues sets column `x` of `rows` from a numeric Arrow array.
+func arrowColumnValues(col arrow.Array, rows [][]float64, x int) {
+	switch arr := col.(type) {
+	case *array.Float64:
+		setArrowColumn(rows, x, col, arr.Float64Values())
+	case *array.Float32:
+		setArrowColumn(rows, x, col, arr.Float32Values())
+	case *array.Int64:
+		setArrowColumn(rows, x, col, arr.Int64Values())
+	case *array.Int32:
+		setArrowColumn(rows, x, col, arr.Int32Values())
+	case *array.Int16:
+		setArrowColumn(rows, x, col, arr.Int16Values())
+	case *array.Int8:
+		setArrowColumn(rows, x, col, arr.Int8Values())
+	case *array.Uint64:
+		setArrowColumn(rows, x, col, arr.Uint64Values())
+	case *array.Uint32:
+		setArrowColumn(rows, x, col, arr.Uint32Values())
+	case *array.Uint16:
+		setArrowColumn(rows, x, col, arr.Uint16Values())
+	case *array.Uint8:
+		setArrowColumn(rows, x, col, arr.Uint8Values())
+	case *array.Float16:
+		vals := make([]float32, arr.Len())
+		for i, v := range arr.Values() {
+			vals[i] = v.Float32()
+		}
+		setArrowColumn(rows, x, col, vals)
+	default:
+		setArrowColumn(rows, x, col, make([]float64, col.Len()))
+	}
+}
+
+type arrowNumber interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+func setArrowColumn[T arrowNumber](rows [][]float64, x int, col arrow.Array, vals []T) {
+	for y := range rows {
+		if col.IsNull(y) {
+			rows[y][x] = math.NaN()
+		} else {
+			rows[y][x] = float64(vals[y])
+		}
+	}
+}
+
+// WriteArrowIPC writes the table in the Arrow IPC file format in record
+// batches of up to `ArrowOptions.BatchSize` rows.
+func (tbl *Table) WriteArrowIPC(w io.Writer, opts *table.ArrowOptions) error {
+	schema := tbl.ArrowSchema()
+	ipcOpts, err := opts.IPCOptions()
+	if err != nil {
+		return err
+	}
+	fw, err := ipc.NewFileWriter(w, append(ipcOpts, ipc.WithSchema(schema))...)
+	if err != nil {
+		return err
+	}
+	batchSize := opts.BatchSizeOrDefault()
+	for start := 0; start < len(tbl.Rows); start += batchSize {
+		rec, err := tbl.arrowRecordBatch(memory.DefaultAllocator, schema, start, min(start+batchSize, len(tbl.Rows)))
+		if err != nil {
+			fw.Close()
+			return err
+		}
+		err = fw.Write(rec)
+		rec.Release()
+		if err != nil {
+			fw.Close()
+			return err
+		}
+	}
+	return fw.Close()
+}
+
+// WriteFileArrow writes the table to an Arrow IPC file.
+func (tbl *Table) WriteFileArrow(filename string, opts *table.ArrowOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := tbl.WriteArrowIPC(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadArrowIPC reads an Arrow IPC file into a `Table`. See `FromArrow`.
+func ReadArrowIPC(r ipc.ReadAtSeeker) (*Table, error) {
+	fr, err := ipc.NewFileReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+	n, err := FromArrow(fr.Schema())
+	if err != nil {
+		return nil, err
+	}
+	for i := range fr.NumRecords() {
+		rec, err := fr.RecordBatch(i)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := FromArrow(fr.Schema(), rec)
+		if err != nil {
+			return nil, err
+		}
+		n.Rows = append(n.Rows, batch.Rows...)
+	}
+	return n, nil
+}
+
+// ReadFileArrow reads an Arrow IPC file into a `Table` named after the file.
+func ReadFileArrow(filename string) (*Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	n, err := ReadArrowIPC(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	n.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return n, f.Close()
+}
@@ -0,0 +1,79 @@
+package tablef64
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/grokify/gocharts/v2/data/table"
+)
+
+func TestArrowRoundTrip(t *testing.T) {
+	tbl := NewTable("Metrics")
+	tbl.Columns = []string{"x", "y"}
+	tbl.Rows = [][]float64{{1, 2.5}, {3, -4}, {5, 1e-9}}
+
+	var buf bytes.Buffer
+	if err := tbl.WriteArrowIPC(&buf, &table.ArrowOptions{BatchSize: 2, Compression: "lz4"}); err != nil {
+		t.Fatalf("Table.WriteArrowIPC() error: [%v]", err)
+	}
+	tbl2, err := ReadArrowIPC(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadArrowIPC() error: [%v]", err)
+	}
+	if len(tbl2.Columns) != 2 || tbl2.Columns[0] != "x" || tbl2.Columns[1] != "y" {
+		t.Errorf("ReadArrowIPC() columns mismatch: want [x y] got [%v]", tbl2.Columns)
+	}
+	if len(tbl2.Rows) != len(tbl.Rows) {
+		t.Fatalf("ReadArrowIPC() rows mismatch: want [%d] got [%d]", len(tbl.Rows), len(tbl2.Rows))
+	}
+	for y, row := range tbl.Rows {
+		for x, want := range row {
+			if got := tbl2.Rows[y][x]; got != want {
+				t.Errorf("ReadArrowIPC() cell [%d,%d] mismatch: want [%v] got [%v]", x, y, want, got)
+			}
+		}
+	}
+
+	if err := tbl.WriteArrowIPC(&buf, &table.ArrowOptions{Compression: "brotli"}); err == nil {
+		t.Error("Table.WriteArrowIPC() expected error for unknown compression")
+	}
+	tbl.Rows = append(tbl.Rows, []float64{6})
+	if _, err := tbl.ArrowRecordBatch(nil); err == nil {
+		t.Error("Table.ArrowRecordBatch() expected error for short row")
+	}
+}
+
+func TestFromArrow(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "count", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float32},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).AppendValues([]int32{7, 0}, []bool{true, false})
+	b.Field(1).(*array.Float32Builder).AppendValues([]float32{0.5, 2}, nil)
+	rec := b.NewRecordBatch()
+	defer rec.Release()
+
+	tbl, err := FromArrow(schema, rec)
+	if err != nil {
+		t.Fatalf("FromArrow() error: [%v]", err)
+	}
+	if len(tbl.Rows) != 2 || tbl.Rows[0][0] != 7 || tbl.Rows[0][1] != 0.5 || !math.IsNaN(tbl.Rows[1][0]) || tbl.Rows[1][1] != 2 {
+		t.Errorf("FromArrow() mismatch: want [[7 0.5] [NaN 2]] got [%v]", tbl.Rows)
+	}
+
+	strSchema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	if _, err := FromArrow(strSchema); err == nil {
+		t.Error("FromArrow() expected error for non-numeric column")
+	}
+	if _, err := FromArrow(nil); err == nil {
+		t.Error("FromArrow() expected error for nil schema")
+	}
+}
@@ -0,0 +1,315 @@
+package timeseries
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AnomalyMethod is the detector used by `TimeSeries.Anomalies`.
+type AnomalyMethod string
+
+const (
+	AnomalyZScore      AnomalyMethod = "zscore"      // deviation from the trailing window mean in standard deviations
+	AnomalyMAD         AnomalyMethod = "mad"         // deviation from the trailing window median in median absolute deviations
+	AnomalySeasonal    AnomalyMethod = "seasonal"    // robust score of seasonal decomposition residuals
+	AnomalyChangePoint AnomalyMethod = "changepoint" // shift in mean between adjacent windows
+)
+
+const AnomalyWindowDefault = 12
+
+// AnomalyOptions configures `TimeSeries.Anomalies`.
+type AnomalyOptions struct {
+	Method       AnomalyMethod
+	Window       int     // items in the trailing or adjacent windows, default 12
+	Threshold    float64 // absolute score to flag, default 3, or 3.5 for MAD and seasonal, or 4 for change points
+	SeasonLength int     // periods per season for seasonal, default 4 for quarter, 12 for month, 52 for week and 7 for day
+}
+
+func (opts AnomalyOptions) window() int {
+	if opts.Window > 0 {
+		return opts.Window
+	}
+	return AnomalyWindowDefault
+}
+
+func (opts AnomalyOptions) threshold() float64 {
+	if opts.Threshold > 0 {
+		return opts.Threshold
+	}
+	switch opts.Method {
+	case AnomalyMAD, AnomalySeasonal:
+		return 3.5
+	case AnomalyChangePoint:
+		return 4
+	}
+	return 3
+}
+
+// Anomaly is a flagged `TimeItem` with its signed score and a human readable
+// reason.
+type Anomaly struct {
+	TimeItem
+	Method AnomalyMethod
+	Score  float64
+	Reason string
+}
+
+// Anomalies returns the items flagged by the `opts.Method` detector in time
+// order. Rolling z-score and MAD compare each item to the `Window` items
+// before it. Seasonal compares each item to the trend and seasonal
+// components of a classical decomposition and requires consecutive periods.
+// Change point compares the means of the `Window` items before and from each
+// item with a t statistic, flagging the strongest shift within each window.
+func (ts *TimeSeries) Anomalies(opts AnomalyOptions) ([]Anomaly, error) {
+	if opts.Window < 0 {
+		return nil, errors.New("anomaly window cannot be negative")
+	}
+	items := ts.ItemsSorted()
+	ys := make([]float64, len(items))
+	for i, item := range items {
+		ys[i] = item.Float64()
+	}
+	var scores []float64
+	var reason func(i int) string
+	w, threshold := opts.window(), opts.threshold()
+	switch opts.Method {
+	case AnomalyZScore:
+		scores = anomalyZScores(ys, w)
+		reason = func(i int) string {
+			mean := sumFloat64s(ys[i-w:i]) / float64(w)
+			return fmt.Sprintf("%.1f standard deviations %s the rolling mean of %s", math.Abs(scores[i]), aboveBelow(scores[i]), formatAnomalyValue(mean))
+		}
+	case AnomalyMAD:
+		scores = anomalyMADScores(ys, w)
+		reason = func(i int) string {
+			return fmt.Sprintf("%.1f robust deviations %s the rolling median of %s", math.Abs(scores[i]), aboveBelow(scores[i]), formatAnomalyValue(medianFloat64s(ys[i-w:i])))
+		}
+	case AnomalySeasonal:
+		m := opts.SeasonLength
+		if m <= 0 {
+			m = defaultSeasonLength(ts.Interval)
+		}
+		if _, _, err := ts.periodValues(); err != nil {
+			return nil, err
+		}
+		expected, err := seasonalExpected(ys, m)
+		if err != nil {
+			return nil, err
+		}
+		scores = robustScores(ys, expected)
+		reason = func(i int) string {
+			return fmt.Sprintf("%.1f robust deviations %s the seasonal expectation of %s", math.Abs(scores[i]), aboveBelow(scores[i]), formatAnomalyValue(expected[i]))
+		}
+	case AnomalyChangePoint:
+		w = max(w, 2)
+		scores = anomalyChangePointScores(ys, w, threshold)
+		reason = func(i int) string {
+			return fmt.Sprintf("level shift from a mean of %s to %s", formatAnomalyValue(sumFloat64s(ys[i-w:i])/float64(w)), formatAnomalyValue(sumFloat64s(ys[i:i+w])/float64(w)))
+		}
+	default:
+		return nil, fmt.Errorf("unknown anomaly method [%s]", opts.Method)
+	}
+
+	var anomalies []Anomaly
+	for i, score := range scores {
+		if math.IsNaN(score) || math.Abs(score) < threshold {
+			continue
+		}
+		anomalies = append(anomalies, Anomaly{
+			TimeItem: items[i],
+			Method:   opts.Method,
+			Score:    score,
+			Reason:   reason(i)})
+	}
+	return anomalies, nil
+}
+
+// Anomalies returns the anomalies of each series sorted by time and series
+// name.
+func (set *TimeSeriesSet) Anomalies(opts AnomalyOptions) ([]Anomaly, error) {
+	var anomalies []Anomaly
+	for _, name := range set.SeriesNames() {
+		ts := set.Series[name]
+		seriesAnomalies, err := ts.Anomalies(opts)
+		if err != nil {
+			return nil, fmt.Errorf("series [%s]: %w", name, err)
+		}
+		anomalies = append(anomalies, seriesAnomalies...)
+	}
+	slices.SortStableFunc(anomalies, func(a, b Anomaly) int {
+		return cmp.Or(a.Time.Compare(b.Time), strings.Compare(a.SeriesName, b.SeriesName))
+	})
+	return anomalies, nil
+}
+
+// AnomaliesByTime returns anomaly reasons keyed by RFC 3339 time, prefixed
+// by the series name when `withSeriesName` is set.
+func AnomaliesByTime(anomalies []Anomaly, withSeriesName bool) map[string][]string {
+	out := map[string][]string{}
+	for _, a := range anomalies {
+		key := a.Time.UTC().Format(time.RFC3339)
+		if withSeriesName && len(a.SeriesName) > 0 {
+			out[key] = append(out[key], a.SeriesName+": "+a.Reason)
+		} else {
+			out[key] = append(out[key], a.Reason)
+		}
+	}
+	return out
+}
+
+// anomalyZScores scores each value against the mean and sample standard
+// deviation of the `w` values before it.
+func anomalyZScores(ys []float64, w int) []float64 {
+	scores := nanFloat64s(len(ys))
+	for i := w; i < len(ys); i++ {
+		window := ys[i-w : i]
+		mean := sumFloat64s(window) / float64(w)
+		ss := 0.0
+		for _, v := range window {
+			ss += (v - mean) * (v - mean)
+		}
+		scores[i] = deviationScore(ys[i]-mean, math.Sqrt(ss/float64(max(w-1, 1))))
+	}
+	return scores
+}
+
+// anomalyMADScores scores each value with the modified z-score against the
+// median and median absolute deviation of the `w` values before it.
+func anomalyMADScores(ys []float64, w int) []float64 {
+	scores := nanFloat64s(len(ys))
+	devs := make([]float64, w)
+	for i := w; i < len(ys); i++ {
+		window := ys[i-w : i]
+		median := medianFloat64s(window)
+		for j, v := range window {
+			devs[j] = math.Abs(v - median)
+		}
+		scores[i] = deviationScore(ys[i]-median, 1.4826*medianFloat64s(devs))
+	}
+	return scores
+}
+
+// robustScores scores the residuals of `ys` from `expected` with the
+// modified z-score of all residuals.
+func robustScores(ys, expected []float64) []float64 {
+	resids := make([]float64, len(ys))
+	for i := range ys {
+		resids[i] = ys[i] - expected[i]
+	}
+	median := medianFloat64s(resids)
+	devs := make([]float64, len(resids))
+	for i, r := range resids {
+		devs[i] = math.Abs(r - median)
+	}
+	mad := 1.4826 * medianFloat64s(devs)
+	scores := make([]float64, len(ys))
+	for i, r := range resids {
+		scores[i] = deviationScore(r-median, mad)
+	}
+	return scores
+}
+
+// anomalyChangePointScores returns the Welch t statistic between the `w`
+// values before and from each index, keeping only the strongest score above
+// `threshold` within `w` indexes.
+func anomalyChangePointScores(ys []float64, w int, threshold float64) []float64 {
+	stats := nanFloat64s(len(ys))
+	var candidates []int
+	for i := w; i+w <= len(ys); i++ {
+		before, after := ys[i-w:i], ys[i:i+w]
+		mb, ma := sumFloat64s(before)/float64(w), sumFloat64s(after)/float64(w)
+		vb, va := 0.0, 0.0
+		for j := range w {
+			vb += (before[j] - mb) * (before[j] - mb)
+			va += (after[j] - ma) * (after[j] - ma)
+		}
+		stats[i] = deviationScore(ma-mb, math.Sqrt((vb+va)/float64(w-1)/float64(w)))
+		if math.Abs(stats[i]) >= threshold {
+			candidates = append(candidates, i)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b int) int {
+		return cmp.Compare(math.Abs(stats[b]), math.Abs(stats[a]))
+	})
+	scores := nanFloat64s(len(ys))
+	var kept []int
+	for _, i := range candidates {
+		if !slices.ContainsFunc(kept, func(k int) bool { return max(i-k, k-i) < w }) {
+			kept = append(kept, i)
+			scores[i] = stats[i]
+		}
+	}
+	return scores
+}
+
+// seasonalExpected returns the trend plus seasonal component of a classical
+// additive decomposition for each value. The centered moving average trend
+// is extended to the ends with its first and last values.
+func seasonalExpected(ys []float64, m int) ([]float64, error) {
+	if m < 2 {
+		return nil, errors.New("seasonal requires a season length of at least 2")
+	} else if len(ys) < 2*m {
+		return nil, fmt.Errorf("seasonal requires at least [%d] values for season length [%d]", 2*m, m)
+	}
+	trend := centeredMovingAverage(ys, m)
+	first, last := m/2, len(ys)-1-m/2
+	for i := range ys {
+		if i < first {
+			trend[i] = trend[first]
+		} else if i > last {
+			trend[i] = trend[last]
+		}
+	}
+	season := seasonalIndexes(ys, trend, m, false)
+	expected := make([]float64, len(ys))
+	for i := range ys {
+		expected[i] = trend[i] + season[i%m]
+	}
+	return expected, nil
+}
+
+// deviationScore returns `diff/spread`, or a signed infinity when the spread
+// is zero and the difference is not.
+func deviationScore(diff, spread float64) float64 {
+	switch {
+	case spread > 0:
+		return diff / spread
+	case diff == 0:
+		return 0
+	}
+	return math.Inf(int(math.Copysign(1, diff)))
+}
+
+func medianFloat64s(vals []float64) float64 {
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func nanFloat64s(n int) []float64 {
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = math.NaN()
+	}
+	return vals
+}
+
+func aboveBelow(score float64) string {
+	if score < 0 {
+		return "below"
+	}
+	return "above"
+}
+
+func formatAnomalyValue(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -0,0 +1,181 @@
+package timeseries
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+var anomalyTests = []struct {
+	name       string
+	interval   timeutil.Interval
+	vals       []float64
+	opts       AnomalyOptions
+	wantIndex  int // index of the single anomaly, or -1 for none
+	wantScore  float64
+	wantReason string
+}{
+	// the window before the outlier has a mean of 12 and a sample standard
+	// deviation of sqrt(2.5)
+	{"zscore", timeutil.IntervalDay, []float64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, 50, 12},
+		AnomalyOptions{Method: AnomalyZScore, Window: 5}, 10, 38 / math.Sqrt(2.5),
+		"24.0 standard deviations above the rolling mean of 12"},
+	{"zscore none", timeutil.IntervalDay, []float64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, 12, 12},
+		AnomalyOptions{Method: AnomalyZScore, Window: 5}, -1, 0, ""},
+	// the window before the outlier has a median of 12 and a median absolute
+	// deviation of 1
+	{"mad", timeutil.IntervalDay, []float64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, 50, 12},
+		AnomalyOptions{Method: AnomalyMAD, Window: 5}, 10, 38 / 1.4826,
+		"25.6 robust deviations above the rolling median of 12"},
+	{"mad below", timeutil.IntervalDay, []float64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, -20, 12},
+		AnomalyOptions{Method: AnomalyMAD, Window: 5}, 10, -32 / 1.4826,
+		"21.6 robust deviations below the rolling median of 12"},
+	{"mad none", timeutil.IntervalDay, []float64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, 12, 12},
+		AnomalyOptions{Method: AnomalyMAD, Window: 5}, -1, 0, ""},
+	// the means of the 4 values before and from index 6 are 10.5 and 20.5,
+	// each with a sum of squares of 1, so the t statistic is 10/sqrt(1/6)
+	{"changepoint", timeutil.IntervalDay, []float64{10, 11, 10, 11, 10, 11, 20, 21, 20, 21, 20, 21},
+		AnomalyOptions{Method: AnomalyChangePoint, Window: 4}, 6, 10 * math.Sqrt(6),
+		"level shift from a mean of 10.5 to 20.5"},
+	{"changepoint none", timeutil.IntervalDay, []float64{10, 11, 10, 11, 10, 11, 10, 11, 10, 11, 10, 11},
+		AnomalyOptions{Method: AnomalyChangePoint, Window: 4}, -1, 0, ""},
+}
+
+func TestAnomalies(t *testing.T) {
+	for _, tt := range anomalyTests {
+		ts := newTestSeries(tt.interval, tt.vals)
+		anomalies, err := ts.Anomalies(tt.opts)
+		if err != nil {
+			t.Errorf("TimeSeries.Anomalies() %s error: %v", tt.name, err)
+			continue
+		}
+		if tt.wantIndex < 0 {
+			if len(anomalies) != 0 {
+				t.Errorf("TimeSeries.Anomalies() %s mismatch: want none got [%v]", tt.name, anomalies)
+			}
+			continue
+		}
+		if len(anomalies) != 1 {
+			t.Errorf("TimeSeries.Anomalies() %s count mismatch: want [1] got [%d]", tt.name, len(anomalies))
+			continue
+		}
+		a := anomalies[0]
+		if want := intervalAdd(testStart, tt.interval, tt.wantIndex); !a.Time.Equal(want) {
+			t.Errorf("TimeSeries.Anomalies() %s time mismatch: want [%s] got [%s]", tt.name, want, a.Time)
+		}
+		if a.Method != tt.opts.Method || math.Abs(a.Score-tt.wantScore) > 1e-9 || a.Reason != tt.wantReason {
+			t.Errorf("TimeSeries.Anomalies() %s mismatch: want [%v] [%s] got [%v] [%s]", tt.name, tt.wantScore, tt.wantReason, a.Score, a.Reason)
+		}
+		if a.Float64() != tt.vals[tt.wantIndex] {
+			t.Errorf("TimeSeries.Anomalies() %s value mismatch: want [%v] got [%v]", tt.name, tt.vals[tt.wantIndex], a.Float64())
+		}
+	}
+}
+
+func TestAnomaliesSeasonal(t *testing.T) {
+	// an additive season of 0, 10, 20, 30 on a trend rising by 1 per season
+	// with 32 replaced by 60
+	vals := []float64{10, 20, 30, 40, 11, 21, 31, 41, 12, 22, 60, 42, 13, 23, 33, 43}
+	ts := newTestSeries(timeutil.IntervalQuarter, vals)
+	anomalies, err := ts.Anomalies(AnomalyOptions{Method: AnomalySeasonal})
+	if err != nil {
+		t.Fatalf("TimeSeries.Anomalies() seasonal error: %v", err)
+	}
+	if len(anomalies) != 1 || anomalies[0].Float64() != 60 || anomalies[0].Score <= 0 {
+		t.Fatalf("TimeSeries.Anomalies() seasonal mismatch: want one anomaly of [60] got [%v]", anomalies)
+	}
+	if !strings.Contains(anomalies[0].Reason, "above the seasonal expectation") {
+		t.Errorf("TimeSeries.Anomalies() seasonal reason mismatch: got [%s]", anomalies[0].Reason)
+	}
+
+	short := newTestSeries(timeutil.IntervalQuarter, vals[:7])
+	if _, err := short.Anomalies(AnomalyOptions{Method: AnomalySeasonal}); err == nil {
+		t.Error("TimeSeries.Anomalies() expected error for seasonal series shorter than two seasons")
+	}
+	short.DeleteTime(intervalAdd(testStart, timeutil.IntervalQuarter, 2))
+	if _, err := short.Anomalies(AnomalyOptions{Method: AnomalySeasonal, SeasonLength: 2}); err == nil {
+		t.Error("TimeSeries.Anomalies() expected error for seasonal series with a missing period")
+	}
+}
+
+func TestAnomaliesErrors(t *testing.T) {
+	ts := newTestSeries(timeutil.IntervalDay, []float64{1, 2, 3})
+	for _, opts := range []AnomalyOptions{{Method: "iqr"}, {Method: AnomalyZScore, Window: -1}} {
+		if _, err := ts.Anomalies(opts); err == nil {
+			t.Errorf("TimeSeries.Anomalies() expected error for options [%v]", opts)
+		}
+	}
+}
+
+func newAnomalyTestSet() TimeSeriesSet {
+	set := NewTimeSeriesSet("Traffic")
+	for i, v := range []int64{10, 12, 14, 11, 13, 12, 10, 14, 11, 13, 50, 12} {
+		dt := testStart.AddDate(0, 0, i)
+		set.AddInt64("Web", dt, v)
+		if i == 10 {
+			v = -20
+		}
+		set.AddInt64("API", dt, v)
+	}
+	return set
+}
+
+func TestTimeSeriesSetAnomalies(t *testing.T) {
+	set := newAnomalyTestSet()
+	anomalies, err := set.Anomalies(AnomalyOptions{Method: AnomalyMAD, Window: 5})
+	if err != nil {
+		t.Fatalf("TimeSeriesSet.Anomalies() error: %v", err)
+	}
+	if len(anomalies) != 2 || anomalies[0].SeriesName != "API" || anomalies[1].SeriesName != "Web" {
+		t.Fatalf("TimeSeriesSet.Anomalies() mismatch: want API and Web at the same time got [%v]", anomalies)
+	}
+	if _, err := set.Anomalies(AnomalyOptions{Method: "iqr"}); err == nil || !strings.Contains(err.Error(), "series [") {
+		t.Errorf("TimeSeriesSet.Anomalies() error mismatch: want series prefix got [%v]", err)
+	}
+
+	byTime := AnomaliesByTime(anomalies, true)
+	key := testStart.AddDate(0, 0, 10).Format(time.RFC3339)
+	if got := byTime[key]; len(got) != 2 || !strings.HasPrefix(got[0], "API: 21.6 robust deviations below") || !strings.HasPrefix(got[1], "Web: 25.6 robust deviations above") {
+		t.Errorf("AnomaliesByTime() mismatch: got [%v]", byTime)
+	}
+	if got := AnomaliesByTime(anomalies[1:], false)[key]; len(got) != 1 || strings.HasPrefix(got[0], "Web") {
+		t.Errorf("AnomaliesByTime() without series name mismatch: got [%v]", got)
+	}
+}
+
+func TestTimeSeriesSetTableAnomalies(t *testing.T) {
+	set := newAnomalyTestSet()
+	ts := set.Series["Web"]
+	anomalies, err := ts.Anomalies(AnomalyOptions{Method: AnomalyMAD, Window: 5})
+	if err != nil {
+		t.Fatalf("TimeSeries.Anomalies() error: %v", err)
+	}
+	tbl, err := set.Table(&TimeSeriesSetTableOpts{Anomalies: anomalies, AnomalyTitle: "Notes"})
+	if err != nil {
+		t.Fatalf("TimeSeriesSet.Table() error: %v", err)
+	}
+	if want := []string{"Time", "API", "Web", "Notes"}; strings.Join(tbl.Columns, ",") != strings.Join(want, ",") {
+		t.Fatalf("TimeSeriesSet.Table() columns mismatch: want [%v] got [%v]", want, tbl.Columns)
+	}
+	for i, row := range tbl.Rows {
+		note := row[len(row)-1]
+		if i == 10 {
+			if note != "Web: 25.6 robust deviations above the rolling median of 12" {
+				t.Errorf("TimeSeriesSet.Table() anomaly mismatch: got [%s]", note)
+			}
+		} else if note != "" {
+			t.Errorf("TimeSeriesSet.Table() row [%d] anomaly mismatch: want empty got [%s]", i, note)
+		}
+	}
+
+	tbl, err = set.Table(nil)
+	if err != nil {
+		t.Fatalf("TimeSeriesSet.Table() error: %v", err)
+	}
+	if len(tbl.Columns) != 3 {
+		t.Errorf("TimeSeriesSet.Table() without anomalies column count mismatch: want [3] got [%d]", len(tbl.Columns))
+	}
+}
@@ -0,0 +1,301 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DecomposeMethod is the algorithm used by `Decompose`.
+type DecomposeMethod string
+
+const (
+	DecomposeClassical DecomposeMethod = "classical" // centered moving average trend and mean seasonal indexes, the default
+	DecomposeSTL       DecomposeMethod = "stl"       // seasonal-trend decomposition using LOESS
+)
+
+const (
+	DecomposeSuffixTrend    = "Trend"
+	DecomposeSuffixSeasonal = "Seasonal"
+	DecomposeSuffixResidual = "Residual"
+
+	STLSeasonalWindowDefault = 7
+)
+
+// DecomposeOptions configures `Decompose`.
+type DecomposeOptions struct {
+	Method         DecomposeMethod
+	SeasonLength   int  // periods per season, default 4 for quarter, 12 for month, 52 for week and 7 for day
+	Multiplicative bool // classical only, where series = trend * seasonal * residual
+
+	// STL options. `SeasonalWindow` is the LOESS span in seasons for each
+	// cycle subseries, default 7. `TrendWindow` is the LOESS span in
+	// periods for the trend, by default the smallest odd number of at least
+	// `1.5*SeasonLength/(1-1.5/SeasonalWindow)`. `Robust` reweights items
+	// to reduce the effect of outliers on the trend and seasonal components.
+	SeasonalWindow int
+	TrendWindow    int
+	Robust         bool
+}
+
+// Decompose splits `ts` into trend, seasonal and residual components,
+// returned as a float set with one series per component, named with the
+// `DecomposeSuffix` constants. Items must be consecutive interval starts for
+// the series interval, which can be ensured with `Resample`, and there must
+// be at least two seasons. Classical trends are undefined for the first and
+// last half season, so those times are omitted from the trend and residual
+// series.
+func Decompose(ts TimeSeries, opts *DecomposeOptions) (TimeSeriesSet, error) {
+	if opts == nil {
+		opts = &DecomposeOptions{}
+	}
+	set := NewTimeSeriesSet(ts.SeriesName)
+	set.IsFloat = true
+	set.Interval = ts.Interval
+	ys, _, err := ts.periodValues()
+	if err != nil {
+		return set, err
+	}
+	m := opts.SeasonLength
+	if m <= 0 {
+		m = defaultSeasonLength(ts.Interval)
+	}
+	if m < 2 {
+		return set, errors.New("decompose requires a season length of at least 2")
+	} else if len(ys) < 2*m {
+		return set, fmt.Errorf("decompose requires at least [%d] values for season length [%d]", 2*m, m)
+	}
+
+	var trend, seasonal []float64
+	switch opts.Method {
+	case "", DecomposeClassical:
+		trend = centeredMovingAverage(ys, m)
+		indexes := seasonalIndexes(ys, trend, m, opts.Multiplicative)
+		seasonal = make([]float64, len(ys))
+		for i := range ys {
+			seasonal[i] = indexes[i%m]
+		}
+	case DecomposeSTL:
+		if opts.Multiplicative {
+			return set, errors.New("stl decomposition is additive only")
+		}
+		trend, seasonal = stl(ys, m, opts.SeasonalWindow, opts.TrendWindow, opts.Robust)
+	default:
+		return set, fmt.Errorf("unknown decompose method [%s]", opts.Method)
+	}
+
+	trendTS := ts.newDerivedSeries(DecomposeSuffixTrend)
+	seasonalTS := ts.newDerivedSeries(DecomposeSuffixSeasonal)
+	residualTS := ts.newDerivedSeries(DecomposeSuffixResidual)
+	for i, item := range ts.ItemsSorted() {
+		seasonalTS.AddFloat64(item.Time, seasonal[i])
+		if math.IsNaN(trend[i]) {
+			continue
+		}
+		trendTS.AddFloat64(item.Time, trend[i])
+		if opts.Multiplicative {
+			residualTS.AddFloat64(item.Time, ys[i]/(trend[i]*seasonal[i]))
+		} else {
+			residualTS.AddFloat64(item.Time, ys[i]-trend[i]-seasonal[i])
+		}
+	}
+	for _, component := range []TimeSeries{trendTS, seasonalTS, residualTS} {
+		component.SeriesSetName = set.Name
+		set.Series[component.SeriesName] = component
+		set.Order = append(set.Order, component.SeriesName)
+	}
+	set.Times = set.TimeSlice(true)
+	return set, nil
+}
+
+// centeredMovingAverage returns the centered moving average of order `m`,
+// using a 2 x m average for even `m`. Values without a full window are `NaN`.
+func centeredMovingAverage(ys []float64, m int) []float64 {
+	out := nanFloat64s(len(ys))
+	half := m / 2
+	for i := half; i+half < len(ys); i++ {
+		if m%2 == 1 {
+			out[i] = sumFloat64s(ys[i-half:i+half+1]) / float64(m)
+		} else {
+			out[i] = (sumFloat64s(ys[i-half:i+half]) + sumFloat64s(ys[i-half+1:i+half+1])) / float64(2*m)
+		}
+	}
+	return out
+}
+
+// seasonalIndexes returns the mean detrended value for each season position,
+// normalized to sum to zero, or for `multiplicative` the mean ratio to the
+// trend, normalized to average one.
+func seasonalIndexes(ys, trend []float64, m int, multiplicative bool) []float64 {
+	sums, counts := make([]float64, m), make([]int, m)
+	for i, t := range trend {
+		if math.IsNaN(t) {
+			continue
+		}
+		if multiplicative {
+			sums[i%m] += ys[i] / t
+		} else {
+			sums[i%m] += ys[i] - t
+		}
+		counts[i%m]++
+	}
+	season := make([]float64, m)
+	for j := range m {
+		if counts[j] > 0 {
+			season[j] = sums[j] / float64(counts[j])
+		}
+	}
+	mean := sumFloat64s(season) / float64(m)
+	for j := range season {
+		if multiplicative {
+			season[j] /= mean
+		} else {
+			season[j] -= mean
+		}
+	}
+	return season
+}
+
+// stl returns the trend and seasonal components using the STL procedure of
+// Cleveland et al. (1990) with linear LOESS smoothers.
+func stl(ys []float64, m, seasonalWindow, trendWindow int, robust bool) ([]float64, []float64) {
+	n := len(ys)
+	if seasonalWindow <= 0 {
+		seasonalWindow = STLSeasonalWindowDefault
+	}
+	seasonalWindow = nextOdd(max(seasonalWindow, 3))
+	if trendWindow <= 0 {
+		trendWindow = int(math.Ceil(1.5 * float64(m) / (1 - 1.5/float64(seasonalWindow))))
+	}
+	trendWindow = nextOdd(max(trendWindow, 3))
+	lowPassWindow := nextOdd(m)
+	inner, outer := 2, 0
+	if robust {
+		inner, outer = 1, 15
+	}
+
+	trend, seasonal := make([]float64, n), make([]float64, n)
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	detrended, deseasonalized := make([]float64, n), make([]float64, n)
+	for o := 0; o <= outer; o++ {
+		for range inner {
+			for i := range ys {
+				detrended[i] = ys[i] - trend[i]
+			}
+			// smooth each cycle subseries, extended by one season on each end
+			cycle := make([]float64, n+2*m)
+			for j := range m {
+				var sub, subWeights []float64
+				for i := j; i < n; i += m {
+					sub = append(sub, detrended[i])
+					subWeights = append(subWeights, weights[i])
+				}
+				for k := -1; k <= len(sub); k++ {
+					cycle[(k+1)*m+j] = loess(sub, subWeights, seasonalWindow, float64(k))
+				}
+			}
+			// remove low frequency from the cycle subseries
+			low := movingAverage(movingAverage(movingAverage(cycle, m), m), 3)
+			for i := range n {
+				seasonal[i] = cycle[m+i] - loess(low, nil, lowPassWindow, float64(i))
+				deseasonalized[i] = ys[i] - seasonal[i]
+			}
+			for i := range n {
+				trend[i] = loess(deseasonalized, weights, trendWindow, float64(i))
+			}
+		}
+		if o < outer {
+			stlRobustWeights(ys, trend, seasonal, weights)
+		}
+	}
+	return trend, seasonal
+}
+
+// stlRobustWeights sets bisquare weights from the remainder.
+func stlRobustWeights(ys, trend, seasonal, weights []float64) {
+	abs := make([]float64, len(ys))
+	for i := range ys {
+		abs[i] = math.Abs(ys[i] - trend[i] - seasonal[i])
+	}
+	h := 6 * medianFloat64s(abs)
+	for i, r := range abs {
+		switch {
+		case h == 0:
+			weights[i] = 1
+		case r/h < 1:
+			u := 1 - (r/h)*(r/h)
+			weights[i] = u * u
+		default:
+			weights[i] = 0
+		}
+	}
+}
+
+// loess returns the locally weighted linear fit of `ys` at `x0`, where the
+// x of `ys[i]` is `i`. The neighborhood is the nearest `q` values with
+// tricube weights, multiplied by `weights` if set.
+func loess(ys, weights []float64, q int, x0 float64) float64 {
+	n := len(ys)
+	dists := make([]float64, n)
+	for i := range ys {
+		dists[i] = math.Abs(float64(i) - x0)
+	}
+	sorted := slices.Clone(dists)
+	slices.Sort(sorted)
+	maxDist := sorted[min(q, n)-1]
+	if q > n {
+		maxDist += float64(q-n) / 2
+	}
+	maxDist = max(maxDist, 1)
+	var sw, swx, swy, swxx, swxy float64
+	for i, y := range ys {
+		u := dists[i] / maxDist
+		if u >= 1 {
+			continue
+		}
+		w := math.Pow(1-u*u*u, 3)
+		if weights != nil {
+			w *= weights[i]
+		}
+		x := float64(i)
+		sw += w
+		swx += w * x
+		swy += w * y
+		swxx += w * x * x
+		swxy += w * x * y
+	}
+	if sw == 0 {
+		return 0
+	}
+	meanX, meanY := swx/sw, swy/sw
+	varX := swxx/sw - meanX*meanX
+	if varX <= 1e-12*max(1, meanX*meanX) {
+		return meanY
+	}
+	slope := (swxy/sw - meanX*meanY) / varX
+	return meanY + slope*(x0-meanX)
+}
+
+// movingAverage returns the trailing moving averages of length `w`, with
+// `len(ys)-w+1` values.
+func movingAverage(ys []float64, w int) []float64 {
+	out := make([]float64, 0, len(ys)-w+1)
+	sum := sumFloat64s(ys[:w])
+	out = append(out, sum/float64(w))
+	for i := w; i < len(ys); i++ {
+		sum += ys[i] - ys[i-w]
+		out = append(out, sum/float64(w))
+	}
+	return out
+}
+
+func nextOdd(v int) int {
+	if v%2 == 0 {
+		return v + 1
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+// decomposeTestValues returns `n` quarterly values of `100 + 2t` plus a
+// season of -6, -2, 2 and 6.
+func decomposeTestValues(n int) []float64 {
+	season := []float64{-6, -2, 2, 6}
+	ys := make([]float64, n)
+	for i := range ys {
+		ys[i] = 100 + 2*float64(i) + season[i%4]
+	}
+	return ys
+}
+
+// decomposeComponents returns the trend, seasonal and residual series of a
+// decomposition, checking the names and order.
+func decomposeComponents(t *testing.T, set TimeSeriesSet) (TimeSeries, TimeSeries, TimeSeries) {
+	t.Helper()
+	want := []string{"Test Trend", "Test Seasonal", "Test Residual"}
+	if len(set.Order) != len(want) {
+		t.Fatalf("Decompose() components mismatch: want [%v] got [%v]", want, set.Order)
+	}
+	for i, name := range want {
+		if set.Order[i] != name {
+			t.Fatalf("Decompose() components mismatch: want [%v] got [%v]", want, set.Order)
+		}
+	}
+	return set.Series[want[0]], set.Series[want[1]], set.Series[want[2]]
+}
+
+// checkReconstruction verifies that the components combine to the input at
+// each time with a trend.
+func checkReconstruction(t *testing.T, name string, ts TimeSeries, set TimeSeriesSet, multiplicative bool, wantCount int) {
+	t.Helper()
+	trend, seasonal, residual := decomposeComponents(t, set)
+	if len(trend.ItemMap) != wantCount || len(residual.ItemMap) != wantCount || len(seasonal.ItemMap) != len(ts.ItemMap) {
+		t.Errorf("Decompose() %s count mismatch: want trend [%d] seasonal [%d] got trend [%d] seasonal [%d] residual [%d]",
+			name, wantCount, len(ts.ItemMap), len(trend.ItemMap), len(seasonal.ItemMap), len(residual.ItemMap))
+	}
+	for _, tr := range trend.ItemsSorted() {
+		item, _ := ts.Get(tr.Time)
+		s, _ := seasonal.Get(tr.Time)
+		r, _ := residual.Get(tr.Time)
+		got := tr.Float64() + s.Float64() + r.Float64()
+		if multiplicative {
+			got = tr.Float64() * s.Float64() * r.Float64()
+		}
+		if math.Abs(got-item.Float64()) > 1e-9 {
+			t.Errorf("Decompose() %s reconstruction mismatch at [%s]: want [%v] got [%v]", name, tr.Time, item.Float64(), got)
+		}
+	}
+}
+
+func TestDecomposeClassical(t *testing.T) {
+	// the 2x4 moving average recovers the linear trend exactly, so the
+	// seasonal indexes are the planted season and the residuals are zero
+	ts := newTestSeries(timeutil.IntervalQuarter, decomposeTestValues(12))
+	set, err := Decompose(ts, nil)
+	if err != nil {
+		t.Fatalf("Decompose() classical error: %v", err)
+	}
+	if !set.IsFloat || set.Interval != timeutil.IntervalQuarter {
+		t.Errorf("Decompose() set mismatch: want float quarter got float [%v] interval [%s]", set.IsFloat, set.Interval.String())
+	}
+	checkReconstruction(t, "classical", ts, set, false, 8)
+	trend, seasonal, residual := decomposeComponents(t, set)
+	for i, item := range seasonal.ItemsSorted() {
+		if want := []float64{-6, -2, 2, 6}[i%4]; math.Abs(item.Float64()-want) > 1e-9 {
+			t.Errorf("Decompose() classical seasonal mismatch at [%d]: want [%v] got [%v]", i, want, item.Float64())
+		}
+	}
+	for i, item := range trend.ItemsSorted() {
+		if want := 100 + 2*float64(i+2); math.Abs(item.Float64()-want) > 1e-9 {
+			t.Errorf("Decompose() classical trend mismatch at [%d]: want [%v] got [%v]", i+2, want, item.Float64())
+		}
+	}
+	for _, item := range residual.ItemsSorted() {
+		if math.Abs(item.Float64()) > 1e-9 {
+			t.Errorf("Decompose() classical residual mismatch: want [0] got [%v]", item.Float64())
+		}
+	}
+}
+
+func TestDecomposeMultiplicative(t *testing.T) {
+	// a constant level of 100 with seasonal factors that average one
+	factors := []float64{0.8, 0.9, 1.1, 1.2}
+	ys := make([]float64, 12)
+	for i := range ys {
+		ys[i] = 100 * factors[i%4]
+	}
+	ts := newTestSeries(timeutil.IntervalQuarter, ys)
+	set, err := Decompose(ts, &DecomposeOptions{Multiplicative: true})
+	if err != nil {
+		t.Fatalf("Decompose() multiplicative error: %v", err)
+	}
+	checkReconstruction(t, "multiplicative", ts, set, true, 8)
+	_, seasonal, _ := decomposeComponents(t, set)
+	for i, item := range seasonal.ItemsSorted() {
+		if math.Abs(item.Float64()-factors[i%4]) > 1e-9 {
+			t.Errorf("Decompose() multiplicative seasonal mismatch at [%d]: want [%v] got [%v]", i, factors[i%4], item.Float64())
+		}
+	}
+
+	// odd season lengths use a simple centered moving average
+	ts = newTestSeries(timeutil.IntervalDay, []float64{1, 2, 3, 1, 2, 3, 1, 2, 3})
+	set, err = Decompose(ts, &DecomposeOptions{SeasonLength: 3, Multiplicative: true})
+	if err != nil {
+		t.Fatalf("Decompose() odd season error: %v", err)
+	}
+	checkReconstruction(t, "odd season", ts, set, true, 7)
+	_, seasonal, _ = decomposeComponents(t, set)
+	for i, item := range seasonal.ItemsSorted()[:3] {
+		if want := float64(i+1) / 2; math.Abs(item.Float64()-want) > 1e-9 {
+			t.Errorf("Decompose() odd season seasonal mismatch at [%d]: want [%v] got [%v]", i, want, item.Float64())
+		}
+	}
+}
+
+func TestDecomposeSTL(t *testing.T) {
+	ys := decomposeTestValues(24)
+	ts := newTestSeries(timeutil.IntervalQuarter, ys)
+	set, err := Decompose(ts, &DecomposeOptions{Method: DecomposeSTL})
+	if err != nil {
+		t.Fatalf("Decompose() stl error: %v", err)
+	}
+	checkReconstruction(t, "stl", ts, set, false, 24)
+	_, seasonal, _ := decomposeComponents(t, set)
+	for i, item := range seasonal.ItemsSorted() {
+		if want := []float64{-6, -2, 2, 6}[i%4]; math.Abs(item.Float64()-want) > 0.5 {
+			t.Errorf("Decompose() stl seasonal mismatch at [%d]: want [%v] got [%v]", i, want, item.Float64())
+		}
+	}
+
+	// robust fitting down-weights a planted outlier so that it stays in the
+	// residual instead of distorting the trend
+	outlier := 13
+	ys[outlier] += 40
+	ts = newTestSeries(timeutil.IntervalQuarter, ys)
+	errAt := func(robust bool) (float64, float64) {
+		set, err := Decompose(ts, &DecomposeOptions{Method: DecomposeSTL, Robust: robust})
+		if err != nil {
+			t.Fatalf("Decompose() stl robust [%v] error: %v", robust, err)
+		}
+		checkReconstruction(t, "stl robust", ts, set, false, 24)
+		trend, _, residual := decomposeComponents(t, set)
+		trendItems, residualItems := trend.ItemsSorted(), residual.ItemsSorted()
+		trendErr := 0.0
+		for i := outlier - 2; i <= outlier+2; i++ {
+			trendErr = max(trendErr, math.Abs(trendItems[i].Float64()-(100+2*float64(i))))
+		}
+		return residualItems[outlier].Float64(), trendErr
+	}
+	plainResidual, plainTrendErr := errAt(false)
+	robustResidual, robustTrendErr := errAt(true)
+	if robustResidual <= plainResidual || robustResidual < 35 {
+		t.Errorf("Decompose() stl robust residual mismatch: want above [%v] and near [40] got [%v]", plainResidual, robustResidual)
+	}
+	if robustTrendErr >= plainTrendErr || robustTrendErr > 1 {
+		t.Errorf("Decompose() stl robust trend error mismatch: want below [%v] and [1] got [%v]", plainTrendErr, robustTrendErr)
+	}
+}
+
+func TestDecomposeErrors(t *testing.T) {
+	ts := newTestSeries(timeutil.IntervalQuarter, decomposeTestValues(7))
+	for _, tt := range []struct {
+		name string
+		ts   TimeSeries
+		opts *DecomposeOptions
+	}{
+		{"too short", ts, nil},
+		{"season length one", ts, &DecomposeOptions{SeasonLength: 1}},
+		{"yearly default season length", newTestSeries(timeutil.IntervalYear, decomposeTestValues(8)), nil},
+		{"unknown method", newTestSeries(timeutil.IntervalQuarter, decomposeTestValues(8)), &DecomposeOptions{Method: "x11"}},
+		{"stl multiplicative", newTestSeries(timeutil.IntervalQuarter, decomposeTestValues(8)), &DecomposeOptions{Method: DecomposeSTL, Multiplicative: true}},
+		{"empty", NewTimeSeries("Test"), nil},
+	} {
+		if _, err := Decompose(tt.ts, tt.opts); err == nil {
+			t.Errorf("Decompose() expected error for %s", tt.name)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grokify/mogo/time/timeutil"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ForecastMethod is the model used by `TimeSeries.Forecast`.
+type ForecastMethod string
+
+const (
+	ForecastHoltWintersAdditive       ForecastMethod = "holt-winters-additive"
+	ForecastHoltWintersMultiplicative ForecastMethod = "holt-winters-multiplicative"
+	ForecastSeasonalNaive             ForecastMethod = "seasonal-naive"
+	ForecastDrift                     ForecastMethod = "drift"
+	ForecastARIMA                     ForecastMethod = "arima"
+)
+
+const ForecastLevelDefault = 0.95
+
+// ForecastOptions configures `TimeSeries.Forecast`.
+type ForecastOptions struct {
+	Method       ForecastMethod
+	Horizon      int     // number of periods to forecast
+	SeasonLength int     // periods per season, default 4 for quarter, 12 for month, 52 for week and 7 for day
+	Level        float64 // prediction interval coverage, default 0.95
+
+	// Alpha, Beta and Gamma are the Holt-Winters level, trend and seasonal
+	// smoothing parameters in (0, 1]. Parameters that are zero are not set
+	// and are fit by grid search to minimize the one-step squared error.
+	Alpha float64
+	Beta  float64
+	Gamma float64
+
+	ARIMAOrder ARIMAOrder
+}
+
+func (opts ForecastOptions) level() (float64, error) {
+	if opts.Level == 0 {
+		return ForecastLevelDefault, nil
+	} else if opts.Level < 0 || opts.Level >= 1 {
+		return 0, fmt.Errorf("forecast level must be in (0, 1) [%v]", opts.Level)
+	}
+	return opts.Level, nil
+}
+
+func (opts ForecastOptions) seasonLength(interval timeutil.Interval) int {
+	if opts.SeasonLength > 0 {
+		return opts.SeasonLength
+	}
+	return defaultSeasonLength(interval)
+}
+
+// defaultSeasonLength returns the periods in a year, or a week for days.
+func defaultSeasonLength(interval timeutil.Interval) int {
+	switch interval {
+	case timeutil.IntervalQuarter:
+		return 4
+	case timeutil.IntervalMonth:
+		return 12
+	case timeutil.IntervalWeek:
+		return 52
+	case timeutil.IntervalDay:
+		return 7
+	}
+	return 1
+}
+
+// Forecast is the result of `TimeSeries.Forecast`. `Lower` and `Upper` are
+// the prediction interval bounds at `Level` coverage.
+type Forecast struct {
+	Method ForecastMethod
+	Level  float64
+	Sigma  float64 // standard deviation of the one-step residuals
+	Alpha  float64 // fitted Holt-Winters parameters
+	Beta   float64
+	Gamma  float64
+	Mean   TimeSeries
+	Lower  TimeSeries
+	Upper  TimeSeries
+}
+
+// TimeSeriesSet returns a float set with the `Mean`, `Lower` and `Upper`
+// series in that order.
+func (fc *Forecast) TimeSeriesSet() TimeSeriesSet {
+	set := NewTimeSeriesSet(fc.Mean.SeriesSetName)
+	set.IsFloat = true
+	set.Interval = fc.Mean.Interval
+	for _, ts := range []TimeSeries{fc.Mean, fc.Lower, fc.Upper} {
+		set.Series[ts.SeriesName] = ts
+		set.Order = append(set.Order, ts.SeriesName)
+	}
+	set.Times = set.TimeSlice(true)
+	return set
+}
+
+// Forecast projects the series `Horizon` periods past its last item. Items
+// must be consecutive interval starts for the series interval, which can be
+// ensured with `Resample`. Prediction intervals assume normal errors. For
+// Holt-Winters they use the additive model variance, which is approximate
+// for multiplicative seasonality.
+func (ts *TimeSeries) Forecast(opts ForecastOptions) (*Forecast, error) {
+	if opts.Horizon < 1 {
+		return nil, errors.New("forecast horizon must be at least 1")
+	}
+	level, err := opts.level()
+	if err != nil {
+		return nil, err
+	}
+	ys, last, err := ts.periodValues()
+	if err != nil {
+		return nil, err
+	}
+	fc := &Forecast{Method: opts.Method, Level: level}
+	var means, ses []float64
+	m := opts.seasonLength(ts.Interval)
+	switch opts.Method {
+	case ForecastHoltWintersAdditive, ForecastHoltWintersMultiplicative:
+		hw, err := fitHoltWinters(ys, m, opts.Method == ForecastHoltWintersMultiplicative, opts.Alpha, opts.Beta, opts.Gamma)
+		if err != nil {
+			return nil, err
+		}
+		fc.Alpha, fc.Beta, fc.Gamma = hw.alpha, hw.beta, hw.gamma
+		means, ses, fc.Sigma = hw.forecast(opts.Horizon)
+	case ForecastSeasonalNaive:
+		means, ses, fc.Sigma, err = forecastSeasonalNaive(ys, m, opts.Horizon)
+	case ForecastDrift:
+		means, ses, fc.Sigma, err = forecastDrift(ys, opts.Horizon)
+	case ForecastARIMA:
+		means, ses, fc.Sigma, err = forecastARIMA(ys, opts.ARIMAOrder, opts.Horizon)
+	default:
+		return nil, fmt.Errorf("unknown forecast method [%s]", opts.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	z := distuv.UnitNormal.Quantile(0.5 + level/2)
+	fc.Mean = ts.newDerivedSeries("Forecast")
+	fc.Lower = ts.newDerivedSeries("Forecast Lower")
+	fc.Upper = ts.newDerivedSeries("Forecast Upper")
+	for h := range opts.Horizon {
+		t := intervalAdd(last, ts.Interval, h+1)
+		fc.Mean.AddFloat64(t, means[h])
+		fc.Lower.AddFloat64(t, means[h]-z*ses[h])
+		fc.Upper.AddFloat64(t, means[h]+z*ses[h])
+	}
+	return fc, nil
+}
+
+// periodValues returns the item values in time order and the last time,
+// verifying that items are consecutive periods.
+func (ts *TimeSeries) periodValues() ([]float64, time.Time, error) {
+	items := ts.ItemsSorted()
+	if len(items) == 0 {
+		return nil, time.Time{}, ErrNoTimeItem
+	}
+	if _, err := intervalStart(items[0].Time, ts.Interval); err != nil {
+		return nil, time.Time{}, err
+	}
+	ys := make([]float64, len(items))
+	for i, item := range items {
+		if i > 0 {
+			if want := intervalAdd(items[i-1].Time.UTC(), ts.Interval, 1); !item.Time.UTC().Equal(want) {
+				return nil, time.Time{}, fmt.Errorf("series requires consecutive %s periods, missing [%s]", ts.Interval.String(), want.Format(time.RFC3339))
+			}
+		}
+		ys[i] = item.Float64()
+	}
+	return ys, items[len(items)-1].Time.UTC(), nil
+}
+
+type holtWinters struct {
+	alpha, beta, gamma float64
+	multiplicative     bool
+	level, trend       float64
+	season             []float64 // seasonal index by period modulo season length
+	n                  int
+	sse                float64
+	count              int
+}
+
+// fitHoltWinters fits a Holt-Winters model, searching a grid for parameters
+// that are zero.
+func fitHoltWinters(ys []float64, m int, multiplicative bool, alpha, beta, gamma float64) (holtWinters, error) {
+	if m < 2 {
+		return holtWinters{}, errors.New("holt-winters requires a season length of at least 2")
+	} else if len(ys) < 2*m+1 {
+		return holtWinters{}, fmt.Errorf("holt-winters requires at least [%d] values for season length [%d]", 2*m+1, m)
+	}
+	for _, v := range []float64{alpha, beta, gamma} {
+		if v < 0 || v > 1 {
+			return holtWinters{}, fmt.Errorf("holt-winters parameters must be in (0, 1] [%v]", v)
+		}
+	}
+	if multiplicative {
+		for _, y := range ys {
+			if y <= 0 {
+				return holtWinters{}, errors.New("holt-winters multiplicative requires positive values")
+			}
+		}
+	}
+	grid := []float64{0.01}
+	for i := 1; i < 20; i++ {
+		grid = append(grid, float64(i)/20)
+	}
+	candidates := func(v float64) []float64 {
+		if v > 0 {
+			return []float64{v}
+		}
+		return grid
+	}
+	var best holtWinters
+	for _, a := range candidates(alpha) {
+		for _, b := range candidates(beta) {
+			for _, g := range candidates(gamma) {
+				hw := runHoltWinters(ys, m, multiplicative, a, b, g)
+				if best.season == nil || hw.sse < best.sse {
+					best = hw
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+func runHoltWinters(ys []float64, m int, multiplicative bool, alpha, beta, gamma float64) holtWinters {
+	hw := holtWinters{alpha: alpha, beta: beta, gamma: gamma, multiplicative: multiplicative, n: len(ys)}
+	first, second := sumFloat64s(ys[:m])/float64(m), sumFloat64s(ys[m:2*m])/float64(m)
+	hw.level, hw.trend = first, (second-first)/float64(m)
+	hw.season = make([]float64, m)
+	for i := range m {
+		if multiplicative {
+			hw.season[i] = ys[i] / first
+		} else {
+			hw.season[i] = ys[i] - first
+		}
+	}
+	for t := m; t < len(ys); t++ {
+		s, prevLevel, prevBase := hw.season[t%m], hw.level, hw.level+hw.trend
+		var fit float64
+		if multiplicative {
+			fit = prevBase * s
+			hw.level = alpha*ys[t]/s + (1-alpha)*prevBase
+			hw.season[t%m] = gamma*ys[t]/prevBase + (1-gamma)*s
+		} else {
+			fit = prevBase + s
+			hw.level = alpha*(ys[t]-s) + (1-alpha)*prevBase
+			hw.season[t%m] = gamma*(ys[t]-prevBase) + (1-gamma)*s
+		}
+		hw.trend = beta*(hw.level-prevLevel) + (1-beta)*hw.trend
+		hw.sse += (ys[t] - fit) * (ys[t] - fit)
+		hw.count++
+	}
+	return hw
+}
+
+// forecast returns the point forecasts, their standard errors and the
+// residual standard deviation.
+func (hw holtWinters) forecast(horizon int) ([]float64, []float64, float64) {
+	m := len(hw.season)
+	sigma := math.Sqrt(hw.sse / float64(hw.count))
+	means, ses := make([]float64, horizon), make([]float64, horizon)
+	variance := 1.0
+	for h := 1; h <= horizon; h++ {
+		base, s := hw.level+float64(h)*hw.trend, hw.season[(hw.n+h-1)%m]
+		if hw.multiplicative {
+			means[h-1] = base * s
+		} else {
+			means[h-1] = base + s
+		}
+		ses[h-1] = sigma * math.Sqrt(variance)
+		c := hw.alpha * (1 + float64(h)*hw.beta)
+		if h%m == 0 {
+			c += hw.gamma
+		}
+		variance += c * c
+	}
+	return means, ses, sigma
+}
+
+// forecastSeasonalNaive forecasts each period as the value one season
+// earlier.
+func forecastSeasonalNaive(ys []float64, m, horizon int) ([]float64, []float64, float64, error) {
+	if m < 1 {
+		return nil, nil, 0, errors.New("seasonal naive requires a season length of at least 1")
+	} else if len(ys) <= m {
+		return nil, nil, 0, fmt.Errorf("seasonal naive requires more than [%d] values", m)
+	}
+	n, sse := len(ys), 0.0
+	for t := m; t < n; t++ {
+		sse += (ys[t] - ys[t-m]) * (ys[t] - ys[t-m])
+	}
+	sigma := math.Sqrt(sse / float64(n-m))
+	means, ses := make([]float64, horizon), make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		means[h-1] = ys[n-m+(h-1)%m]
+		ses[h-1] = sigma * math.Sqrt(float64((h-1)/m+1))
+	}
+	return means, ses, sigma, nil
+}
+
+// forecastDrift forecasts a line from the first to the last value.
+func forecastDrift(ys []float64, horizon int) ([]float64, []float64, float64, error) {
+	n := len(ys)
+	if n < 2 {
+		return nil, nil, 0, errors.New("drift requires at least [2] values")
+	}
+	slope := (ys[n-1] - ys[0]) / float64(n-1)
+	sigma := 0.0
+	if n > 2 {
+		sse := 0.0
+		for t := 1; t < n; t++ {
+			e := ys[t] - ys[t-1] - slope
+			sse += e * e
+		}
+		sigma = math.Sqrt(sse / float64(n-2))
+	}
+	means, ses := make([]float64, horizon), make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		fh := float64(h)
+		means[h-1] = ys[n-1] + fh*slope
+		ses[h-1] = sigma * math.Sqrt(fh*(1+fh/float64(n-1)))
+	}
+	return means, ses, sigma, nil
+}
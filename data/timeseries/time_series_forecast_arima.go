@@ -0,0 +1,143 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+// ARIMAOrder is the ARIMA(p, d, q) model order: `P` autoregressive terms,
+// `D` differences and `Q` moving average terms.
+type ARIMAOrder struct {
+	P int
+	D int
+	Q int
+}
+
+// forecastARIMA fits an ARIMA model by conditional sum of squares and returns
+// the point forecasts, their standard errors and the residual standard
+// deviation. A mean is fit when `D` is 0.
+func forecastARIMA(ys []float64, order ARIMAOrder, horizon int) ([]float64, []float64, float64, error) {
+	p, d, q := order.P, order.D, order.Q
+	if p < 0 || d < 0 || q < 0 {
+		return nil, nil, 0, errors.New("arima order cannot be negative")
+	}
+	ws := ys
+	for range d {
+		diffs := make([]float64, 0, len(ws))
+		for i := 1; i < len(ws); i++ {
+			diffs = append(diffs, ws[i]-ws[i-1])
+		}
+		ws = diffs
+	}
+	if len(ws) <= p+q+1 {
+		return nil, nil, 0, fmt.Errorf("arima requires more than [%d] values after differencing", p+q+1)
+	}
+
+	// css returns the conditional sum of squares and residuals for
+	// parameters `[phi..., theta..., mean]`.
+	css := func(params []float64) (float64, []float64) {
+		phi, theta, mean := params[:p], params[p:p+q], 0.0
+		if d == 0 {
+			mean = params[p+q]
+		}
+		es, sse := make([]float64, len(ws)), 0.0
+		for t := p; t < len(ws); t++ {
+			e := ws[t] - mean
+			for i, v := range phi {
+				e -= v * (ws[t-1-i] - mean)
+			}
+			for j, v := range theta {
+				if t-1-j >= 0 {
+					e -= v * es[t-1-j]
+				}
+			}
+			es[t] = e
+			sse += e * e
+		}
+		return sse, es
+	}
+	params := make([]float64, p+q)
+	if d == 0 {
+		params = append(params, sumFloat64s(ws)/float64(len(ws)))
+	}
+	if p+q > 0 {
+		result, err := optimize.Minimize(optimize.Problem{
+			Func: func(x []float64) float64 {
+				sse, _ := css(x)
+				return sse
+			},
+		}, params, nil, &optimize.NelderMead{})
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("arima fit: %w", err)
+		}
+		params = result.X
+	}
+	sse, es := css(params)
+	sigma := math.Sqrt(sse / float64(max(len(ws)-p-len(params), 1)))
+	phi, theta, constant := params[:p], params[p:p+q], 0.0
+	if d == 0 {
+		constant = params[p+q]
+		for _, v := range phi {
+			constant -= v * params[p+q]
+		}
+	}
+
+	// poly is phi(B)(1-B)^d and ar holds its coefficients as
+	// y_t = constant + sum(ar[k] y_{t-k}) + MA terms.
+	poly := []float64{1}
+	for _, v := range phi {
+		poly = append(poly, -v)
+	}
+	for range d {
+		next := make([]float64, len(poly)+1)
+		for i, v := range poly {
+			next[i] += v
+			next[i+1] -= v
+		}
+		poly = next
+	}
+	ar := make([]float64, len(poly))
+	for k := 1; k < len(poly); k++ {
+		ar[k] = -poly[k]
+	}
+
+	// residuals of the differenced series align with the values `d` later
+	n := len(ys)
+	ext := append(make([]float64, 0, n+horizon), ys...)
+	means, ses := make([]float64, horizon), make([]float64, horizon)
+	psi := make([]float64, horizon)
+	for h := range horizon {
+		t := n + h
+		v := constant
+		for k := 1; k < len(ar); k++ {
+			v += ar[k] * ext[t-k]
+		}
+		for j, th := range theta {
+			if i := t - 1 - j - d; i < n-d && i >= 0 {
+				v += th * es[i]
+			}
+		}
+		ext = append(ext, v)
+		means[h] = v
+
+		if h == 0 {
+			psi[h] = 1
+		} else {
+			if h <= q {
+				psi[h] = theta[h-1]
+			}
+			for k := 1; k < len(ar) && k <= h; k++ {
+				psi[h] += ar[k] * psi[h-k]
+			}
+		}
+		sum := 0.0
+		for _, v := range psi[:h+1] {
+			sum += v * v
+		}
+		ses[h] = sigma * math.Sqrt(sum)
+	}
+	return means, ses, sigma, nil
+}
@@ -0,0 +1,215 @@
+package timeseries
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+const (
+	z80 = 1.2815515655446004 // standard normal quantile for 80% intervals
+	z95 = 1.959963984540054  // standard normal quantile for 95% intervals
+)
+
+// checkForecast compares the forecast means and the interval bounds
+// `mean -/+ z*se` to the reference values.
+func checkForecast(t *testing.T, name string, fc *Forecast, z float64, means, ses []float64) {
+	t.Helper()
+	mean, lower, upper := fc.Mean.ItemsSorted(), fc.Lower.ItemsSorted(), fc.Upper.ItemsSorted()
+	if len(mean) != len(means) || len(lower) != len(means) || len(upper) != len(means) {
+		t.Fatalf("%s horizon mismatch: want [%d] got [%d]", name, len(means), len(mean))
+	}
+	for h := range means {
+		if got := mean[h].Float64(); math.Abs(got-means[h]) > 1e-9 {
+			t.Errorf("%s mean mismatch at h [%d]: want [%v] got [%v]", name, h+1, means[h], got)
+		}
+		if got, want := lower[h].Float64(), means[h]-z*ses[h]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s lower mismatch at h [%d]: want [%v] got [%v]", name, h+1, want, got)
+		}
+		if got, want := upper[h].Float64(), means[h]+z*ses[h]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s upper mismatch at h [%d]: want [%v] got [%v]", name, h+1, want, got)
+		}
+	}
+}
+
+// TestForecastBaselines checks the seasonal naive and drift methods against
+// the forecast and standard deviation formulas of Hyndman and Athanasopoulos,
+// Forecasting: Principles and Practice, Table 5.2.
+func TestForecastBaselines(t *testing.T) {
+	// seasonal naive residuals are all 2, so sigma is 2 and the standard
+	// deviation is sigma*sqrt(k+1) for k complete seasons before h
+	ts := newTestSeries(timeutil.IntervalQuarter, []float64{10, 20, 30, 40, 12, 22, 32, 42})
+	fc, err := ts.Forecast(ForecastOptions{Method: ForecastSeasonalNaive, Horizon: 5})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() seasonal naive error: %v", err)
+	}
+	if fc.Sigma != 2 || fc.Level != ForecastLevelDefault {
+		t.Errorf("TimeSeries.Forecast() seasonal naive mismatch: want sigma [2] level [%v] got sigma [%v] level [%v]", ForecastLevelDefault, fc.Sigma, fc.Level)
+	}
+	checkForecast(t, "TimeSeries.Forecast() seasonal naive", fc, z95,
+		[]float64{12, 22, 32, 42, 12},
+		[]float64{2, 2, 2, 2, 2 * math.Sqrt(2)})
+	if first := fc.Mean.ItemsSorted()[0].Time.Format("2006-01-02"); first != "2026-01-01" {
+		t.Errorf("TimeSeries.Forecast() first time mismatch: want [2026-01-01] got [%s]", first)
+	}
+
+	// drift slope is 7/3 and the residuals -1/3, -4/3 and 5/3 give a
+	// sigma of sqrt(7/3), with a standard deviation of sigma*sqrt(h(1+h/(T-1)))
+	ts = newTestSeries(timeutil.IntervalQuarter, []float64{1, 3, 4, 8})
+	fc, err = ts.Forecast(ForecastOptions{Method: ForecastDrift, Horizon: 2, Level: 0.8})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() drift error: %v", err)
+	}
+	checkForecast(t, "TimeSeries.Forecast() drift", fc, z80,
+		[]float64{31.0 / 3, 38.0 / 3},
+		[]float64{math.Sqrt(28) / 3, math.Sqrt(70) / 3})
+}
+
+func TestForecastHoltWinters(t *testing.T) {
+	// The additive recursions with alpha, beta and gamma of 0.5 from a level
+	// of 2, trend of 0.5 and season of [-1, 1] end with a level of 3.734375,
+	// trend of 0.5390625 and season of [-0.734375, 0.8125]. The one-step
+	// errors are 0.5, -0.375 and 0.03125. The variance multipliers are from
+	// the ETS(A,A,A) forecast variance in Forecasting: Principles and
+	// Practice, section 8.7, with an ETS beta of alpha*beta = 0.25, giving
+	// 1, 1.5625 and 3.8125.
+	ts := newTestSeries(timeutil.IntervalQuarter, []float64{1, 3, 2, 4, 3})
+	fc, err := ts.Forecast(ForecastOptions{Method: ForecastHoltWintersAdditive, Horizon: 3, SeasonLength: 2, Alpha: 0.5, Beta: 0.5, Gamma: 0.5})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() additive error: %v", err)
+	}
+	sigma := math.Sqrt((0.25 + 0.140625 + 0.0009765625) / 3)
+	if math.Abs(fc.Sigma-sigma) > 1e-12 || fc.Alpha != 0.5 || fc.Beta != 0.5 || fc.Gamma != 0.5 {
+		t.Errorf("TimeSeries.Forecast() additive fit mismatch: want sigma [%v] got sigma [%v] alpha [%v] beta [%v] gamma [%v]", sigma, fc.Sigma, fc.Alpha, fc.Beta, fc.Gamma)
+	}
+	checkForecast(t, "TimeSeries.Forecast() additive", fc, z95,
+		[]float64{4.2734375 + 0.8125, 4.8125 - 0.734375, 5.3515625 + 0.8125},
+		[]float64{sigma, sigma * 1.25, sigma * math.Sqrt(3.8125)})
+
+	// a series that is exactly seasonal is fit without error by the first
+	// grid parameters, so the forecast continues the seasonal pattern with
+	// zero width intervals
+	ts = newTestSeries(timeutil.IntervalQuarter, []float64{10, 20, 10, 20, 10})
+	fc, err = ts.Forecast(ForecastOptions{Method: ForecastHoltWintersMultiplicative, Horizon: 3, SeasonLength: 2})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() multiplicative error: %v", err)
+	}
+	if fc.Alpha != 0.01 || fc.Beta != 0.01 || fc.Gamma != 0.01 {
+		t.Errorf("TimeSeries.Forecast() multiplicative grid mismatch: want [0.01] got alpha [%v] beta [%v] gamma [%v]", fc.Alpha, fc.Beta, fc.Gamma)
+	}
+	checkForecast(t, "TimeSeries.Forecast() multiplicative", fc, z95,
+		[]float64{20, 10, 20},
+		[]float64{0, 0, 0})
+
+	for _, tt := range []struct {
+		name    string
+		opts    ForecastOptions
+		ys      []float64
+		errPart string
+	}{
+		{"too short", ForecastOptions{SeasonLength: 2}, []float64{1, 2, 3, 4}, "at least [5] values"},
+		{"season length", ForecastOptions{SeasonLength: 1}, []float64{1, 2, 3, 4, 5}, "season length of at least 2"},
+		{"alpha above one", ForecastOptions{SeasonLength: 2, Alpha: 1.5}, []float64{1, 2, 3, 4, 5}, "(0, 1]"},
+		{"negative gamma", ForecastOptions{SeasonLength: 2, Gamma: -0.5}, []float64{1, 2, 3, 4, 5}, "(0, 1]"},
+	} {
+		tt.opts.Method, tt.opts.Horizon = ForecastHoltWintersAdditive, 1
+		ts := newTestSeries(timeutil.IntervalQuarter, tt.ys)
+		if _, err := ts.Forecast(tt.opts); err == nil || !strings.Contains(err.Error(), tt.errPart) {
+			t.Errorf("TimeSeries.Forecast() %s error mismatch: want [%s] got [%v]", tt.name, tt.errPart, err)
+		}
+	}
+	ts = newTestSeries(timeutil.IntervalQuarter, []float64{1, 2, 0, 4, 5})
+	if _, err := ts.Forecast(ForecastOptions{Method: ForecastHoltWintersMultiplicative, Horizon: 1, SeasonLength: 2}); err == nil {
+		t.Error("TimeSeries.Forecast() expected error for multiplicative with a zero value")
+	}
+}
+
+func TestForecastARIMA(t *testing.T) {
+	ys := []float64{1, 3, 4, 8}
+	ts := newTestSeries(timeutil.IntervalQuarter, ys)
+
+	// ARIMA(0,1,0) is the naive method, with residuals 2, 1 and 4
+	fc, err := ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 3, ARIMAOrder: ARIMAOrder{D: 1}})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() ARIMA(0,1,0) error: %v", err)
+	}
+	sigma := math.Sqrt(7)
+	checkForecast(t, "TimeSeries.Forecast() ARIMA(0,1,0)", fc, z95,
+		[]float64{8, 8, 8},
+		[]float64{sigma, sigma * math.Sqrt(2), sigma * math.Sqrt(3)})
+
+	// ARIMA(0,2,0) extrapolates the last two values, with second differences
+	// -1 and 3 and psi weights 1, 2 and 3
+	fc, err = ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 3, ARIMAOrder: ARIMAOrder{D: 2}})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() ARIMA(0,2,0) error: %v", err)
+	}
+	sigma = math.Sqrt(5)
+	checkForecast(t, "TimeSeries.Forecast() ARIMA(0,2,0)", fc, z95,
+		[]float64{12, 16, 20},
+		[]float64{sigma, sigma * math.Sqrt(5), sigma * math.Sqrt(14)})
+
+	// ARIMA(0,0,0) is the mean method
+	fc, err = ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 2})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() ARIMA(0,0,0) error: %v", err)
+	}
+	sigma = math.Sqrt(26.0 / 3)
+	checkForecast(t, "TimeSeries.Forecast() ARIMA(0,0,0)", fc, z95,
+		[]float64{4, 4},
+		[]float64{sigma, sigma})
+
+	// ARIMA(0,1,1) is simple exponential smoothing with alpha = 1+theta,
+	// starting from the first value, so the forecasts are flat at the final
+	// level and the variance multipliers are 1+(h-1)*alpha^2.
+	ys = []float64{10, 11, 13, 12, 14, 15, 14, 16, 18, 17}
+	ts = newTestSeries(timeutil.IntervalQuarter, ys)
+	fc, err = ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 3, ARIMAOrder: ARIMAOrder{D: 1, Q: 1}})
+	if err != nil {
+		t.Fatalf("TimeSeries.Forecast() ARIMA(0,1,1) error: %v", err)
+	}
+	mean, lower := fc.Mean.ItemsSorted(), fc.Lower.ItemsSorted()
+	ses := make([]float64, len(mean))
+	for h := range mean {
+		ses[h] = (mean[h].Float64() - lower[h].Float64()) / z95
+	}
+	alpha := math.Sqrt(ses[1]*ses[1]/(ses[0]*ses[0]) - 1)
+	if alpha <= 0 || alpha >= 1 {
+		t.Fatalf("TimeSeries.Forecast() ARIMA(0,1,1) alpha out of range: got [%v]", alpha)
+	}
+	level := ys[0]
+	for _, y := range ys[1:] {
+		level += alpha * (y - level)
+	}
+	checkForecast(t, "TimeSeries.Forecast() ARIMA(0,1,1)", fc, z95,
+		[]float64{level, level, level},
+		[]float64{ses[0], ses[0] * math.Sqrt(1+alpha*alpha), ses[0] * math.Sqrt(1+2*alpha*alpha)})
+
+	// ARIMA(1,1,1) needs more than 3 differences
+	ts = newTestSeries(timeutil.IntervalQuarter, []float64{1, 2, 4})
+	if _, err := ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 1, ARIMAOrder: ARIMAOrder{P: 1, D: 1, Q: 1}}); err == nil || !strings.Contains(err.Error(), "more than [3] values") {
+		t.Errorf("TimeSeries.Forecast() ARIMA too short error mismatch: got [%v]", err)
+	}
+	if _, err := ts.Forecast(ForecastOptions{Method: ForecastARIMA, Horizon: 1, ARIMAOrder: ARIMAOrder{P: -1}}); err == nil {
+		t.Error("TimeSeries.Forecast() expected error for negative ARIMA order")
+	}
+}
+
+func TestForecastErrors(t *testing.T) {
+	ts := newTestSeries(timeutil.IntervalQuarter, []float64{1, 2, 3, 4})
+	for _, opts := range []ForecastOptions{
+		{Method: ForecastDrift},
+		{Method: ForecastDrift, Horizon: 1, Level: 1},
+		{Method: "theta", Horizon: 1},
+	} {
+		if _, err := ts.Forecast(opts); err == nil {
+			t.Errorf("TimeSeries.Forecast() expected error for options [%v]", opts)
+		}
+	}
+	ts.DeleteTime(testStart.AddDate(0, 3, 0))
+	if _, err := ts.Forecast(ForecastOptions{Method: ForecastDrift, Horizon: 1}); err == nil || !strings.Contains(err.Error(), "consecutive") {
+		t.Errorf("TimeSeries.Forecast() error mismatch for missing period: got [%v]", err)
+	}
+}
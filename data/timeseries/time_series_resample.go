@@ -0,0 +1,248 @@
+package timeseries
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+// ResampleAgg is the aggregation of the items in each interval.
+type ResampleAgg string
+
+const (
+	ResampleSum   ResampleAgg = "sum"
+	ResampleMean  ResampleAgg = "mean"
+	ResampleLast  ResampleAgg = "last"
+	ResampleMax   ResampleAgg = "max"
+	ResampleMin   ResampleAgg = "min"
+	ResampleCount ResampleAgg = "count"
+)
+
+// ResampleFill is the policy for intervals without items.
+type ResampleFill string
+
+const (
+	ResampleFillNone    ResampleFill = "none"    // leave missing, the default
+	ResampleFillZero    ResampleFill = "zero"    // fill with zero
+	ResampleFillForward ResampleFill = "forward" // fill with the previous value
+	ResampleFillLinear  ResampleFill = "linear"  // interpolate linearly between the surrounding values
+)
+
+// Resample aggregates items into `interval` periods using `agg`, and fills
+// periods without items between the first and last periods using `fill`.
+// Intervals must be year, quarter, month, week or day, with periods starting
+// at midnight UTC and weeks starting on Sunday. Other intervals, including
+// sub-day intervals, return `ErrIntervalNotSupported`. Mean aggregation and
+// linear fill return a float series, count aggregation otherwise returns an
+// int series, and others keep the series type. Forward and linear fill do not
+// fill before the first period and linear fill does not fill after the last
+// period.
+func (ts *TimeSeries) Resample(interval timeutil.Interval, agg ResampleAgg, fill ResampleFill) (TimeSeries, error) {
+	if err := validateResample(interval, agg, fill); err != nil {
+		return TimeSeries{}, err
+	}
+	minTime, maxTime := time.Time{}, time.Time{}
+	for _, item := range ts.ItemMap {
+		if minTime.IsZero() || item.Time.Before(minTime) {
+			minTime = item.Time
+		}
+		if maxTime.IsZero() || item.Time.After(maxTime) {
+			maxTime = item.Time
+		}
+	}
+	return ts.resample(interval, agg, fill, minTime, maxTime)
+}
+
+// resample resamples the series, filling periods from `minTime` to `maxTime`.
+func (ts *TimeSeries) resample(interval timeutil.Interval, agg ResampleAgg, fill ResampleFill, minTime, maxTime time.Time) (TimeSeries, error) {
+	out := NewTimeSeries(ts.SeriesName)
+	out.SeriesSetName = ts.SeriesSetName
+	out.Interval = interval
+	out.IsFloat = resampleIsFloat(ts.IsFloat, agg, fill)
+
+	type bucket struct {
+		sum, min, max, last float64
+		count               int
+	}
+	buckets := map[time.Time]*bucket{}
+	for _, item := range ts.ItemsSorted() {
+		start, err := intervalStart(item.Time, interval)
+		if err != nil {
+			return out, err
+		}
+		v := item.Float64()
+		b, ok := buckets[start]
+		if !ok {
+			buckets[start] = &bucket{sum: v, min: v, max: v, last: v, count: 1}
+			continue
+		}
+		b.sum += v
+		b.min = min(b.min, v)
+		b.max = max(b.max, v)
+		b.last = v
+		b.count++
+	}
+	if len(buckets) == 0 {
+		return out, nil
+	}
+
+	first, err := intervalStart(minTime, interval)
+	if err != nil {
+		return out, err
+	}
+	last, err := intervalStart(maxTime, interval)
+	if err != nil {
+		return out, err
+	}
+	var periods []time.Time
+	for t := first; !t.After(last); t = intervalAdd(t, interval, 1) {
+		periods = append(periods, t)
+	}
+
+	values := make([]float64, len(periods))
+	known := make([]bool, len(periods))
+	for i, t := range periods {
+		b, ok := buckets[t]
+		if !ok {
+			continue
+		}
+		known[i] = true
+		switch agg {
+		case ResampleSum:
+			values[i] = b.sum
+		case ResampleMean:
+			values[i] = b.sum / float64(b.count)
+		case ResampleLast:
+			values[i] = b.last
+		case ResampleMax:
+			values[i] = b.max
+		case ResampleMin:
+			values[i] = b.min
+		case ResampleCount:
+			values[i] = float64(b.count)
+		}
+	}
+
+	prev := -1
+	for i, t := range periods {
+		if known[i] {
+			out.AddFloat64(t, values[i])
+			prev = i
+			continue
+		}
+		switch fill {
+		case ResampleFillZero:
+			out.AddFloat64(t, 0)
+		case ResampleFillForward:
+			if prev >= 0 {
+				out.AddFloat64(t, values[prev])
+			}
+		case ResampleFillLinear:
+			next := i + 1
+			for next < len(periods) && !known[next] {
+				next++
+			}
+			if prev >= 0 && next < len(periods) {
+				// interpolate by elapsed time as periods such as months vary in length
+				frac := float64(t.Sub(periods[prev])) / float64(periods[next].Sub(periods[prev]))
+				out.AddFloat64(t, values[prev]+frac*(values[next]-values[prev]))
+			}
+		}
+	}
+	return out, nil
+}
+
+// Resample returns a set with `TimeSeries.Resample` applied to each series.
+// Periods are filled across the time range of the whole set so that series
+// align.
+func (set *TimeSeriesSet) Resample(interval timeutil.Interval, agg ResampleAgg, fill ResampleFill) (TimeSeriesSet, error) {
+	if err := validateResample(interval, agg, fill); err != nil {
+		return TimeSeriesSet{}, err
+	}
+	minTime, maxTime := time.Time{}, time.Time{}
+	for _, ts := range set.Series {
+		for _, item := range ts.ItemMap {
+			if minTime.IsZero() || item.Time.Before(minTime) {
+				minTime = item.Time
+			}
+			if maxTime.IsZero() || item.Time.After(maxTime) {
+				maxTime = item.Time
+			}
+		}
+	}
+	newSet, err := set.mapSeries(func(ts TimeSeries) (TimeSeries, error) {
+		return ts.resample(interval, agg, fill, minTime, maxTime)
+	})
+	if err != nil {
+		return newSet, err
+	}
+	newSet.Interval = interval
+	newSet.IsFloat = resampleIsFloat(set.IsFloat, agg, fill)
+	return newSet, nil
+}
+
+func validateResample(interval timeutil.Interval, agg ResampleAgg, fill ResampleFill) error {
+	switch interval {
+	case timeutil.IntervalYear, timeutil.IntervalQuarter, timeutil.IntervalMonth, timeutil.IntervalWeek, timeutil.IntervalDay:
+	default:
+		return fmt.Errorf("%w [%s]", ErrIntervalNotSupported, interval.String())
+	}
+	switch agg {
+	case ResampleSum, ResampleMean, ResampleLast, ResampleMax, ResampleMin, ResampleCount:
+	default:
+		return fmt.Errorf("unknown resample agg [%s]", agg)
+	}
+	switch fill {
+	case "", ResampleFillNone, ResampleFillZero, ResampleFillForward, ResampleFillLinear:
+		return nil
+	}
+	return fmt.Errorf("unknown resample fill [%s]", fill)
+}
+
+// resampleIsFloat returns true if resampled values can be fractional.
+// Linear fill can interpolate between counts, so it takes precedence.
+func resampleIsFloat(isFloat bool, agg ResampleAgg, fill ResampleFill) bool {
+	switch {
+	case agg == ResampleMean || fill == ResampleFillLinear:
+		return true
+	case agg == ResampleCount:
+		return false
+	}
+	return isFloat
+}
+
+// intervalStart returns the start of the interval containing `t` in UTC.
+func intervalStart(t time.Time, interval timeutil.Interval) (time.Time, error) {
+	t = t.UTC()
+	tm := timeutil.NewTimeMore(t, time.Sunday)
+	switch interval {
+	case timeutil.IntervalYear:
+		return tm.YearStart(), nil
+	case timeutil.IntervalQuarter:
+		return tm.QuarterStart(), nil
+	case timeutil.IntervalMonth:
+		return tm.MonthStart(), nil
+	case timeutil.IntervalWeek:
+		return tm.WeekStart(), nil
+	case timeutil.IntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("%w [%s]", ErrIntervalNotSupported, interval.String())
+}
+
+// intervalAdd adds `n` intervals to `t`, which must be an interval start
+// from `intervalStart`. Days are added for unsupported intervals.
+func intervalAdd(t time.Time, interval timeutil.Interval, n int) time.Time {
+	switch interval {
+	case timeutil.IntervalYear:
+		return t.AddDate(n, 0, 0)
+	case timeutil.IntervalQuarter:
+		return t.AddDate(0, 3*n, 0)
+	case timeutil.IntervalMonth:
+		return t.AddDate(0, n, 0)
+	case timeutil.IntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	}
+	return t.AddDate(0, 0, n)
+}
@@ -0,0 +1,149 @@
+package timeseries
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+func newResampleTestSeries() TimeSeries {
+	ts := NewTimeSeries("Orders")
+	for _, item := range []struct {
+		date  string
+		value int64
+	}{
+		{"2024-01-05", 2}, // Friday
+		{"2024-01-06", 4}, // Saturday
+		{"2024-01-07", 6}, // Sunday
+		{"2024-03-31", 10},
+		{"2024-04-01", 1},
+	} {
+		dt, err := time.Parse(time.DateOnly, item.date)
+		if err != nil {
+			panic(err)
+		}
+		ts.AddInt64(dt, item.value)
+	}
+	return ts
+}
+
+// checkDateValues compares the series values to `want`, keyed by date.
+func checkDateValues(t *testing.T, name string, ts TimeSeries, want map[string]float64) {
+	t.Helper()
+	if len(ts.ItemMap) != len(want) {
+		t.Errorf("%s count mismatch: want [%d] got [%d]", name, len(want), len(ts.ItemMap))
+	}
+	for _, item := range ts.ItemsSorted() {
+		date := item.Time.Format(time.DateOnly)
+		wantVal, ok := want[date]
+		if !ok {
+			t.Errorf("%s unexpected date: got [%s]", name, date)
+		} else if math.Abs(item.Float64()-wantVal) > 1e-9 {
+			t.Errorf("%s mismatch at [%s]: want [%v] got [%v]", name, date, wantVal, item.Float64())
+		}
+	}
+}
+
+var resampleTests = []struct {
+	name     string
+	interval timeutil.Interval
+	agg      ResampleAgg
+	fill     ResampleFill
+	isFloat  bool
+	want     map[string]float64
+}{
+	{"sum", timeutil.IntervalMonth, ResampleSum, ResampleFillNone, false,
+		map[string]float64{"2024-01-01": 12, "2024-03-01": 10, "2024-04-01": 1}},
+	{"mean", timeutil.IntervalMonth, ResampleMean, "", true,
+		map[string]float64{"2024-01-01": 4, "2024-03-01": 10, "2024-04-01": 1}},
+	{"last", timeutil.IntervalMonth, ResampleLast, "", false,
+		map[string]float64{"2024-01-01": 6, "2024-03-01": 10, "2024-04-01": 1}},
+	{"max", timeutil.IntervalMonth, ResampleMax, "", false,
+		map[string]float64{"2024-01-01": 6, "2024-03-01": 10, "2024-04-01": 1}},
+	{"min", timeutil.IntervalMonth, ResampleMin, "", false,
+		map[string]float64{"2024-01-01": 2, "2024-03-01": 10, "2024-04-01": 1}},
+	{"count", timeutil.IntervalMonth, ResampleCount, "", false,
+		map[string]float64{"2024-01-01": 3, "2024-03-01": 1, "2024-04-01": 1}},
+	{"zero fill", timeutil.IntervalMonth, ResampleSum, ResampleFillZero, false,
+		map[string]float64{"2024-01-01": 12, "2024-02-01": 0, "2024-03-01": 10, "2024-04-01": 1}},
+	{"forward fill", timeutil.IntervalMonth, ResampleSum, ResampleFillForward, false,
+		map[string]float64{"2024-01-01": 12, "2024-02-01": 12, "2024-03-01": 10, "2024-04-01": 1}},
+	// February is 31 of the 60 days from January to March
+	{"linear fill", timeutil.IntervalMonth, ResampleSum, ResampleFillLinear, true,
+		map[string]float64{"2024-01-01": 12, "2024-02-01": 12 - 2*31.0/60, "2024-03-01": 10, "2024-04-01": 1}},
+	{"count linear fill", timeutil.IntervalMonth, ResampleCount, ResampleFillLinear, true,
+		map[string]float64{"2024-01-01": 3, "2024-02-01": 3 - 2*31.0/60, "2024-03-01": 1, "2024-04-01": 1}},
+	// weeks start on Sunday, so Saturday 2024-01-06 is in the week of 2023-12-31
+	// and Monday 2024-04-01 is in the week of Sunday 2024-03-31
+	{"week", timeutil.IntervalWeek, ResampleSum, "", false,
+		map[string]float64{"2023-12-31": 6, "2024-01-07": 6, "2024-03-31": 11}},
+	{"quarter", timeutil.IntervalQuarter, ResampleSum, ResampleFillZero, false,
+		map[string]float64{"2024-01-01": 22, "2024-04-01": 1}},
+	{"year", timeutil.IntervalYear, ResampleSum, "", false,
+		map[string]float64{"2024-01-01": 23}},
+}
+
+func TestResample(t *testing.T) {
+	ts := newResampleTestSeries()
+	for _, tt := range resampleTests {
+		out, err := ts.Resample(tt.interval, tt.agg, tt.fill)
+		if err != nil {
+			t.Errorf("TimeSeries.Resample() %s error: %v", tt.name, err)
+			continue
+		}
+		if out.IsFloat != tt.isFloat || out.Interval != tt.interval {
+			t.Errorf("TimeSeries.Resample() %s type mismatch: want float [%v] interval [%s] got float [%v] interval [%s]",
+				tt.name, tt.isFloat, tt.interval.String(), out.IsFloat, out.Interval.String())
+		}
+		checkDateValues(t, "TimeSeries.Resample() "+tt.name, out, tt.want)
+	}
+}
+
+func TestResampleDayBoundaries(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	ts := NewTimeSeries("Orders")
+	ts.AddInt64(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), 1)
+	ts.AddInt64(time.Date(2024, time.January, 1, 23, 59, 59, 0, time.UTC), 2)
+	ts.AddInt64(time.Date(2024, time.January, 1, 23, 30, 0, 0, est), 4) // 2024-01-02T04:30:00Z
+	out, err := ts.Resample(timeutil.IntervalDay, ResampleSum, "")
+	if err != nil {
+		t.Fatalf("TimeSeries.Resample() error: %v", err)
+	}
+	checkDateValues(t, "TimeSeries.Resample() day", out, map[string]float64{"2024-01-01": 3, "2024-01-02": 4})
+}
+
+func TestResampleErrors(t *testing.T) {
+	ts := newResampleTestSeries()
+	empty := NewTimeSeries("Empty")
+	if _, err := empty.Resample(timeutil.IntervalMonth, "median", ""); err == nil {
+		t.Error("TimeSeries.Resample() expected error for unknown agg on empty series")
+	}
+	if _, err := empty.Resample(timeutil.IntervalMonth, ResampleSum, "backward"); err == nil {
+		t.Error("TimeSeries.Resample() expected error for unknown fill on empty series")
+	}
+	for _, interval := range []timeutil.Interval{timeutil.IntervalHour, timeutil.IntervalMinute, timeutil.IntervalHalfYear} {
+		if _, err := ts.Resample(interval, ResampleSum, ""); !errors.Is(err, ErrIntervalNotSupported) {
+			t.Errorf("TimeSeries.Resample() error mismatch for interval [%s]: want [%v] got [%v]", interval.String(), ErrIntervalNotSupported, err)
+		}
+	}
+	set := NewTimeSeriesSet("Empty")
+	if _, err := set.Resample(timeutil.IntervalMonth, "median", ""); err == nil {
+		t.Error("TimeSeriesSet.Resample() expected error for unknown agg on empty set")
+	}
+}
+
+func TestTimeSeriesSetResample(t *testing.T) {
+	set := NewTimeSeriesSet("Orders")
+	set.AddInt64("A", time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC), 5)
+	set.AddInt64("B", time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), 7)
+	out, err := set.Resample(timeutil.IntervalMonth, ResampleSum, ResampleFillZero)
+	if err != nil {
+		t.Fatalf("TimeSeriesSet.Resample() error: %v", err)
+	}
+	// series are filled across the range of the whole set
+	checkDateValues(t, "TimeSeriesSet.Resample() A", out.Series["A"], map[string]float64{"2024-01-01": 5, "2024-02-01": 0, "2024-03-01": 0})
+	checkDateValues(t, "TimeSeriesSet.Resample() B", out.Series["B"], map[string]float64{"2024-01-01": 0, "2024-02-01": 0, "2024-03-01": 7})
+}
@@ -0,0 +1,269 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RollingFunc is the statistic computed over a rolling window.
+type RollingFunc string
+
+const (
+	RollingSum    RollingFunc = "sum"
+	RollingMean   RollingFunc = "mean"
+	RollingMedian RollingFunc = "median"
+	RollingMin    RollingFunc = "min"
+	RollingMax    RollingFunc = "max"
+	RollingStdDev RollingFunc = "stddev" // sample standard deviation
+)
+
+// RollingAlign sets the position of a rolling window relative to its time.
+type RollingAlign string
+
+const (
+	RollingAlignTrailing RollingAlign = "trailing" // window ends at the time, the default
+	RollingAlignCenter   RollingAlign = "center"   // window is centered on the time
+)
+
+// RollingOptions configures `TimeSeries.Rolling`. A window spans `Periods`
+// consecutive items or, if `Periods` is not set, the items within `Duration`
+// of the item time. Trailing duration windows include times in `(t-Duration,
+// t]` and centered duration windows include times in `[t-Duration/2,
+// t+Duration/2]`.
+type RollingOptions struct {
+	Periods    int
+	Duration   time.Duration
+	Align      RollingAlign
+	MinPeriods int    // minimum items in a window for a value, default `Periods`, or 1 for duration windows
+	Suffix     string // appended to the series name, such as "7D Avg"
+}
+
+func (opts RollingOptions) validate() error {
+	if opts.Periods < 0 || opts.Duration < 0 {
+		return errors.New("rolling window cannot be negative")
+	} else if opts.Periods == 0 && opts.Duration == 0 {
+		return errors.New("rolling window periods or duration must be set")
+	}
+	switch opts.Align {
+	case "", RollingAlignTrailing, RollingAlignCenter:
+		return nil
+	}
+	return fmt.Errorf("unknown rolling align [%s]", opts.Align)
+}
+
+func (opts RollingOptions) minPeriods() int {
+	if opts.MinPeriods > 0 {
+		return opts.MinPeriods
+	} else if opts.Periods > 0 {
+		return opts.Periods
+	}
+	return 1
+}
+
+// window returns the item index range `[start, end)` for the window of item `i`.
+func (opts RollingOptions) window(items []TimeItem, i int) (int, int) {
+	center := opts.Align == RollingAlignCenter
+	if opts.Periods > 0 {
+		if center {
+			return max(i-opts.Periods/2, 0), min(i+(opts.Periods-1)/2+1, len(items))
+		}
+		return max(i-opts.Periods+1, 0), i + 1
+	}
+	t := items[i].Time
+	if center {
+		lo, hi := t.Add(-opts.Duration/2), t.Add(opts.Duration/2)
+		start, _ := slices.BinarySearchFunc(items, lo, compareItemTime)
+		end, found := slices.BinarySearchFunc(items, hi, compareItemTime)
+		if found {
+			end++
+		}
+		return start, end
+	}
+	start, found := slices.BinarySearchFunc(items, t.Add(-opts.Duration), compareItemTime)
+	if found {
+		start++
+	}
+	return start, i + 1
+}
+
+func compareItemTime(item TimeItem, t time.Time) int {
+	return item.Time.Compare(t)
+}
+
+// Rolling returns a float series with the statistic `fn` computed over a
+// rolling window for each item. Items whose window has fewer than
+// `MinPeriods` items, or fewer than 2 for `RollingStdDev`, are omitted.
+func (ts *TimeSeries) Rolling(fn RollingFunc, opts RollingOptions) (TimeSeries, error) {
+	if err := opts.validate(); err != nil {
+		return TimeSeries{}, err
+	}
+	agg, err := rollingAggregator(fn)
+	if err != nil {
+		return TimeSeries{}, err
+	}
+	out := ts.newDerivedSeries(opts.Suffix)
+	items := ts.ItemsSorted()
+	minPeriods := opts.minPeriods()
+	if fn == RollingStdDev {
+		minPeriods = max(minPeriods, 2)
+	}
+	vals := make([]float64, 0, opts.Periods)
+	for i, item := range items {
+		start, end := opts.window(items, i)
+		if end-start < minPeriods {
+			continue
+		}
+		vals = vals[:0]
+		for _, wi := range items[start:end] {
+			vals = append(vals, wi.Float64())
+		}
+		out.AddFloat64(item.Time, agg(vals))
+	}
+	return out, nil
+}
+
+func rollingAggregator(fn RollingFunc) (func([]float64) float64, error) {
+	switch fn {
+	case RollingSum:
+		return sumFloat64s, nil
+	case RollingMean:
+		return func(vals []float64) float64 { return sumFloat64s(vals) / float64(len(vals)) }, nil
+	case RollingMedian:
+		return medianFloat64s, nil
+	case RollingMin:
+		return slices.Min[[]float64], nil
+	case RollingMax:
+		return slices.Max[[]float64], nil
+	case RollingStdDev:
+		return func(vals []float64) float64 {
+			mean := sumFloat64s(vals) / float64(len(vals))
+			ss := 0.0
+			for _, v := range vals {
+				ss += (v - mean) * (v - mean)
+			}
+			return math.Sqrt(ss / float64(len(vals)-1))
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown rolling func [%s]", fn)
+}
+
+func sumFloat64s(vals []float64) float64 {
+	sum := 0.0
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+// EMAOptions configures `TimeSeries.EMA`. The smoothing factor is `Alpha` or,
+// if not set, `2/(Span+1)`.
+type EMAOptions struct {
+	Span       int
+	Alpha      float64
+	Adjust     bool   // weight by the sum of decayed weights, which reduces bias for early items
+	MinPeriods int    // minimum items before a value is returned, default 1
+	Suffix     string // appended to the series name, such as "EMA"
+}
+
+func (opts EMAOptions) alpha() (float64, error) {
+	if opts.Alpha != 0 {
+		if opts.Alpha < 0 || opts.Alpha > 1 {
+			return 0, fmt.Errorf("ema alpha must be in (0, 1] [%v]", opts.Alpha)
+		}
+		return opts.Alpha, nil
+	} else if opts.Span < 1 {
+		return 0, errors.New("ema span or alpha must be set")
+	}
+	return 2 / (float64(opts.Span) + 1), nil
+}
+
+// EMA returns a float series with the exponential moving average of the
+// items in time order. Without `Adjust`, each value is
+// `alpha*value + (1-alpha)*previous`, starting from the first value.
+func (ts *TimeSeries) EMA(opts EMAOptions) (TimeSeries, error) {
+	alpha, err := opts.alpha()
+	if err != nil {
+		return TimeSeries{}, err
+	}
+	out := ts.newDerivedSeries(opts.Suffix)
+	ema, num, den := 0.0, 0.0, 0.0
+	for i, item := range ts.ItemsSorted() {
+		v := item.Float64()
+		switch {
+		case opts.Adjust:
+			num = v + (1-alpha)*num
+			den = 1 + (1-alpha)*den
+			ema = num / den
+		case i == 0:
+			ema = v
+		default:
+			ema = alpha*v + (1-alpha)*ema
+		}
+		if i+1 >= opts.MinPeriods {
+			out.AddFloat64(item.Time, ema)
+		}
+	}
+	return out, nil
+}
+
+// newDerivedSeries returns an empty float series with the same name and
+// interval, with `suffix` appended to the name if set.
+func (ts *TimeSeries) newDerivedSeries(suffix string) TimeSeries {
+	out := NewTimeSeries(ts.SeriesName)
+	if suffix = strings.TrimSpace(suffix); len(suffix) > 0 {
+		if len(out.SeriesName) > 0 {
+			out.SeriesName += " " + suffix
+		} else {
+			out.SeriesName = suffix
+		}
+	}
+	out.SeriesSetName = ts.SeriesSetName
+	out.IsFloat = true
+	out.Interval = ts.Interval
+	return out
+}
+
+// Rolling returns a float set with `TimeSeries.Rolling` applied to each series.
+func (set *TimeSeriesSet) Rolling(fn RollingFunc, opts RollingOptions) (TimeSeriesSet, error) {
+	return set.mapSeries(func(ts TimeSeries) (TimeSeries, error) {
+		return ts.Rolling(fn, opts)
+	})
+}
+
+// EMA returns a float set with `TimeSeries.EMA` applied to each series.
+func (set *TimeSeriesSet) EMA(opts EMAOptions) (TimeSeriesSet, error) {
+	return set.mapSeries(func(ts TimeSeries) (TimeSeries, error) {
+		return ts.EMA(opts)
+	})
+}
+
+// mapSeries returns a float set with `fn` applied to each series, keeping
+// the series order.
+func (set *TimeSeriesSet) mapSeries(fn func(ts TimeSeries) (TimeSeries, error)) (TimeSeriesSet, error) {
+	newSet := NewTimeSeriesSet(set.Name)
+	newSet.IsFloat = true
+	newSet.Interval = set.Interval
+	names := set.Order
+	if len(names) == 0 {
+		names = set.SeriesNames()
+	}
+	for _, name := range names {
+		ts, ok := set.Series[name]
+		if !ok {
+			continue
+		}
+		newTS, err := fn(ts)
+		if err != nil {
+			return newSet, fmt.Errorf("series [%s]: %w", name, err)
+		}
+		newTS.SeriesSetName = set.Name
+		newSet.Series[newTS.SeriesName] = newTS
+		newSet.Order = append(newSet.Order, newTS.SeriesName)
+	}
+	newSet.Times = newSet.TimeSlice(true)
+	return newSet, nil
+}
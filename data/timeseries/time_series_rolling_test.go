@@ -0,0 +1,136 @@
+package timeseries
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grokify/mogo/time/timeutil"
+)
+
+var testStart = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestSeries returns a float series with `vals` on consecutive intervals
+// from `testStart`.
+func newTestSeries(interval timeutil.Interval, vals []float64) TimeSeries {
+	ts := NewTimeSeries("Test")
+	ts.IsFloat = true
+	ts.Interval = interval
+	dt := testStart
+	for _, v := range vals {
+		ts.AddFloat64(dt, v)
+		dt = intervalAdd(dt, interval, 1)
+	}
+	return ts
+}
+
+// checkSeriesValues compares the series values in time order to `want`,
+// keyed by the number of days from `testStart`.
+func checkSeriesValues(t *testing.T, name string, ts TimeSeries, want map[int]float64) {
+	t.Helper()
+	items := ts.ItemsSorted()
+	if len(items) != len(want) {
+		t.Errorf("%s count mismatch: want [%d] got [%d]", name, len(want), len(items))
+		return
+	}
+	for _, item := range items {
+		day := int(item.Time.Sub(testStart) / (24 * time.Hour))
+		wantVal, ok := want[day]
+		if !ok {
+			t.Errorf("%s unexpected time: got [%s]", name, item.Time.Format(time.RFC3339))
+		} else if math.Abs(item.Float64()-wantVal) > 1e-9 {
+			t.Errorf("%s mismatch at day [%d]: want [%v] got [%v]", name, day, wantVal, item.Float64())
+		}
+	}
+}
+
+var rollingTests = []struct {
+	name string
+	fn   RollingFunc
+	opts RollingOptions
+	want map[int]float64
+}{
+	{"trailing periods", RollingMean, RollingOptions{Periods: 3},
+		map[int]float64{2: 2, 3: 11.0 / 3, 4: 4}},
+	{"trailing periods min periods", RollingSum, RollingOptions{Periods: 3, MinPeriods: 1},
+		map[int]float64{0: 1, 1: 4, 2: 6, 3: 11, 4: 12}},
+	{"center periods", RollingMean, RollingOptions{Periods: 3, Align: RollingAlignCenter},
+		map[int]float64{1: 2, 2: 11.0 / 3, 3: 4}},
+	{"center even periods", RollingMax, RollingOptions{Periods: 4, Align: RollingAlignCenter, MinPeriods: 1},
+		map[int]float64{0: 3, 1: 3, 2: 6, 3: 6, 4: 6}},
+	{"trailing duration", RollingSum, RollingOptions{Duration: 48 * time.Hour},
+		map[int]float64{0: 1, 1: 4, 2: 5, 3: 8, 4: 10}},
+	{"center duration", RollingMedian, RollingOptions{Duration: 48 * time.Hour, Align: RollingAlignCenter},
+		map[int]float64{0: 2, 1: 2, 2: 3, 3: 4, 4: 5}},
+	{"min", RollingMin, RollingOptions{Periods: 2},
+		map[int]float64{1: 1, 2: 2, 3: 2, 4: 4}},
+	{"sample stddev", RollingStdDev, RollingOptions{Periods: 3},
+		map[int]float64{2: 1, 3: math.Sqrt(13.0 / 3), 4: 2}},
+	{"stddev needs two items", RollingStdDev, RollingOptions{Periods: 2, MinPeriods: 1},
+		map[int]float64{1: math.Sqrt(2), 2: math.Sqrt(0.5), 3: math.Sqrt(8), 4: math.Sqrt(2)}},
+	{"window longer than series", RollingMean, RollingOptions{Periods: 10},
+		map[int]float64{}},
+}
+
+func TestRolling(t *testing.T) {
+	ts := newTestSeries(timeutil.IntervalDay, []float64{1, 3, 2, 6, 4})
+	for _, tt := range rollingTests {
+		out, err := ts.Rolling(tt.fn, tt.opts)
+		if err != nil {
+			t.Errorf("TimeSeries.Rolling() %s error: %v", tt.name, err)
+			continue
+		}
+		checkSeriesValues(t, "TimeSeries.Rolling() "+tt.name, out, tt.want)
+	}
+
+	empty := newTestSeries(timeutil.IntervalDay, nil)
+	if out, err := empty.Rolling(RollingMean, RollingOptions{Periods: 3, MinPeriods: 1}); err != nil || len(out.ItemMap) != 0 {
+		t.Errorf("TimeSeries.Rolling() empty series mismatch: want [0] got [%d] err [%v]", len(out.ItemMap), err)
+	}
+
+	for _, opts := range []RollingOptions{{}, {Periods: -1}, {Duration: -time.Hour}, {Periods: 3, Align: "left"}} {
+		if _, err := ts.Rolling(RollingMean, opts); err == nil {
+			t.Errorf("TimeSeries.Rolling() expected error for options [%v]", opts)
+		}
+	}
+	if _, err := ts.Rolling("mode", RollingOptions{Periods: 3}); err == nil {
+		t.Error("TimeSeries.Rolling() expected error for unknown func")
+	}
+}
+
+var emaTests = []struct {
+	name string
+	opts EMAOptions
+	want map[int]float64
+}{
+	{"recursive alpha", EMAOptions{Alpha: 0.5}, map[int]float64{0: 2, 1: 3, 2: 5.5}},
+	{"recursive span", EMAOptions{Span: 3}, map[int]float64{0: 2, 1: 3, 2: 5.5}},
+	{"adjusted alpha", EMAOptions{Alpha: 0.5, Adjust: true}, map[int]float64{0: 2, 1: 10.0 / 3, 2: 6}},
+	{"alpha takes precedence over span", EMAOptions{Alpha: 0.5, Span: 9}, map[int]float64{0: 2, 1: 3, 2: 5.5}},
+	{"alpha one", EMAOptions{Alpha: 1}, map[int]float64{0: 2, 1: 4, 2: 8}},
+	{"span one", EMAOptions{Span: 1, Adjust: true}, map[int]float64{0: 2, 1: 4, 2: 8}},
+	{"min periods", EMAOptions{Alpha: 0.5, MinPeriods: 2}, map[int]float64{1: 3, 2: 5.5}},
+}
+
+func TestEMA(t *testing.T) {
+	ts := newTestSeries(timeutil.IntervalDay, []float64{2, 4, 8})
+	for _, tt := range emaTests {
+		out, err := ts.EMA(tt.opts)
+		if err != nil {
+			t.Errorf("TimeSeries.EMA() %s error: %v", tt.name, err)
+			continue
+		}
+		checkSeriesValues(t, "TimeSeries.EMA() "+tt.name, out, tt.want)
+	}
+
+	empty := newTestSeries(timeutil.IntervalDay, nil)
+	if out, err := empty.EMA(EMAOptions{Span: 3}); err != nil || len(out.ItemMap) != 0 {
+		t.Errorf("TimeSeries.EMA() empty series mismatch: want [0] got [%d] err [%v]", len(out.ItemMap), err)
+	}
+
+	for _, opts := range []EMAOptions{{}, {Alpha: 1.5}, {Alpha: -0.5}, {Span: -2}} {
+		if _, err := ts.EMA(opts); err == nil {
+			t.Errorf("TimeSeries.EMA() expected error for options [%v]", opts)
+		}
+	}
+}
@@ -136,6 +136,8 @@ type TimeSeriesSetTableOpts struct {
 	TotalTitle      string
 	PercentInclude  bool
 	PercentSuffix   string
+	Anomalies       []Anomaly // adds a column with the anomaly reasons for each time
+	AnomalyTitle    string
 }
 
 func (opts *TimeSeriesSetTableOpts) TotalTitleOrDefault() string {
@@ -152,6 +154,13 @@ func (opts *TimeSeriesSetTableOpts) PercentSuffixOrDefault() string {
 	return "%"
 }
 
+func (opts *TimeSeriesSetTableOpts) AnomalyTitleOrDefault() string {
+	if len(opts.AnomalyTitle) > 0 {
+		return opts.AnomalyTitle
+	}
+	return "Anomaly"
+}
+
 // Table returns a `table.Table`.
 func (set *TimeSeriesSet) Table(opts *TimeSeriesSetTableOpts) (table.Table, error) {
 	if opts == nil {
@@ -189,6 +198,12 @@ func (set *TimeSeriesSet) Table(opts *TimeSeriesSetTableOpts) (table.Table, erro
 			tbl.FormatMap[len(tbl.Columns)-1] = table.FormatFloat
 		}
 	}
+	var anomalies map[string][]string
+	if len(opts.Anomalies) > 0 {
+		tbl.Columns = append(tbl.Columns, opts.AnomalyTitleOrDefault())
+		tbl.FormatMap[len(tbl.Columns)-1] = table.FormatString
+		anomalies = AnomaliesByTime(opts.Anomalies, len(seriesNames) > 1)
+	}
 	timeStrings := set.TimeStrings()
 	for _, rfc3339 := range timeStrings {
 		line := []string{}
@@ -234,6 +249,9 @@ func (set *TimeSeriesSet) Table(opts *TimeSeriesSetTableOpts) (table.Table, erro
 				}
 			}
 		}
+		if anomalies != nil {
+			line = append(line, strings.Join(anomalies[rfc3339], "; "))
+		}
 		tbl.Rows = append(tbl.Rows, line)
 	}
 	return tbl, nil
@@ -309,3 +327,28 @@ func (set *TimeSeriesSet) WriteXLSX(filename string, opts *TimeSeriesSetTableOpt
 	}
 	return table.WriteXLSX(filename, []*table.Table{&tbl})
 }
+
+// WriteXLSXReport writes the TimeSeriesSet as a formatted XLSX report using
+// `table.WriteXLSXReport`. If `rptOpts.Charts` is empty, a native line chart
+// of the series is added.
+func (set *TimeSeriesSet) WriteXLSXReport(filename string, tblOpts *TimeSeriesSetTableOpts, rptOpts *table.XLSXReportOptions) error {
+	tbl, err := set.Table(tblOpts)
+	if err != nil {
+		return err
+	}
+	if tblOpts != nil && tblOpts.FuncFormatTime != nil {
+		tbl.FormatMap[0] = table.FormatString
+	}
+	opts := table.XLSXReportOptions{}
+	if rptOpts != nil {
+		opts = *rptOpts
+	}
+	if seriesNames := set.SeriesNames(); len(opts.Charts) == 0 && len(seriesNames) > 0 {
+		opts.Charts = []table.XLSXChart{{
+			Title:    set.Name,
+			XColumn:  tbl.Columns[0],
+			YColumns: seriesNames,
+		}}
+	}
+	return table.WriteXLSXReport(filename, []*table.Table{&tbl}, &opts)
+}